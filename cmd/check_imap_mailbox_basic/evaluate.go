@@ -0,0 +1,215 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/checks"
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// evaluateAccount evaluates the mail check results for the given account
+// against the specified thresholds. The check result for the account is
+// returned along with performance data for the account. Message ages are
+// relative to the given time. An error is returned if the thresholds could
+// not be evaluated (e.g., an invalid range); the account is then reported as
+// not checked alongside the other accounts.
+func evaluateAccount(
+	account config.MailAccount,
+	results mbxs.MailboxCheckResults,
+	missing []string,
+	history map[string][]mbxs.MailboxSample,
+	cfg *config.Config,
+	logger zerolog.Logger,
+	now time.Time,
+) (checks.AccountResult, []nagios.PerformanceData, error) {
+
+	// In expect mail mode only messages received within the (optional)
+	// time window are counted against thresholds.
+	counted := results
+	if cfg.ExpectMail && cfg.MessageWindow > 0 {
+		counted = results.MessagesSince(now.Add(-cfg.MessageWindow))
+	}
+
+	// Evaluate message counts against thresholds and sound an alert if any
+	// are exceeded.
+	warning, critical := cfg.TotalThresholds()
+	evaluation, evalErr := checks.EvaluateThresholds(
+		counted,
+		warning,
+		critical,
+		cfg.FolderThresholds,
+	)
+	if evalErr != nil {
+		return checks.AccountResult{}, nil, &accountError{summary: "Failed to evaluate thresholds", err: evalErr}
+	}
+
+	evaluation.Ages = checks.EvaluateAgeThresholds(
+		results,
+		cfg.AgeWarningThreshold,
+		cfg.AgeCriticalThreshold,
+		now,
+	)
+
+	if cfg.ExpectMail {
+		evaluation.Freshness = checks.EvaluateFreshness(
+			results,
+			cfg.NewestAgeWarningThreshold,
+			cfg.NewestAgeCriticalThreshold,
+			now,
+		)
+	}
+
+	// Folders which were not found or could not be checked are reported
+	// alongside the results for the folders which were checked.
+	evaluation.FolderErrors = checks.EvaluateFolderErrors(
+		results,
+		missing,
+		cfg.MissingFolderExitCode(),
+	)
+
+	// Message arrival, drain and growth rates are evaluated using the check
+	// history recorded by previous checks.
+	if cfg.Rates {
+		rates, rateErr := checks.EvaluateRates(
+			results,
+			history,
+			cfg.EvaluationRateWindows(),
+			cfg.RateThresholds,
+		)
+		if rateErr != nil {
+			return checks.AccountResult{}, nil, &accountError{summary: "Failed to evaluate rate thresholds", err: rateErr}
+		}
+		evaluation.Rates = rates
+	}
+
+	// Metrics for each folder are labeled with the account name if multiple
+	// accounts are checked so that labels remain unique.
+	var perfDataPrefix string
+	if len(cfg.Accounts) > 1 {
+		perfDataPrefix = account.Username
+	}
+
+	perfData := checks.MailPerfData(
+		perfDataPrefix,
+		results,
+		evaluation,
+		cfg.FetchMessageDetails(),
+		cfg.AgeWarningThreshold,
+		cfg.AgeCriticalThreshold,
+		now,
+	)
+	perfData = append(perfData, checks.RatePerfData(perfDataPrefix, evaluation.Rates)...)
+
+	summary := fmt.Sprintf(
+		"%s: %d messages found: %s",
+		account.Username,
+		results.TotalMessagesFound(),
+		results.MessagesFoundSummary(),
+	)
+
+	if len(evaluation.Ages) > 0 {
+		summary += fmt.Sprintf(
+			" (oldest message: %s)",
+			mbxs.FormatMessageAge(results.OldestMessageAge(now)),
+		)
+	}
+
+	if cfg.ExpectMail {
+		switch {
+		case results.GotMail():
+			summary += fmt.Sprintf(
+				" (%s)",
+				checks.NewestMessageSummary(results),
+			)
+		default:
+			summary += " (no messages found)"
+		}
+
+		if cfg.MessageWindow > 0 {
+			summary += fmt.Sprintf(
+				" [%d messages within %s]",
+				counted.TotalMessagesFound(),
+				cfg.MessageWindow,
+			)
+		}
+	}
+
+	if folderErrorsSummary := checks.FolderErrorsSummary(evaluation.FolderErrors); folderErrorsSummary != "" {
+		summary += fmt.Sprintf(" (%s)", folderErrorsSummary)
+	}
+
+	if ratesSummary := checks.RatesSummary(evaluation.Rates); ratesSummary != "" {
+		summary += fmt.Sprintf(" (%s)", ratesSummary)
+	}
+
+	if reset := results.CheckpointsReset(); len(reset) > 0 {
+		summary += fmt.Sprintf(
+			" (UIDVALIDITY changed for %s; all messages counted as new)",
+			strings.Join(reset, ", "),
+		)
+	}
+
+	state := evaluation.ExitStatusCode()
+	if state != nagios.StateOKExitCode {
+		logger.Debug().
+			Str("state", nagios.ExitCodeToStateLabel(state)).
+			Msgf("%d messages found: %s",
+				results.TotalMessagesFound(),
+				results.MessagesFoundSummary(),
+			)
+	}
+
+	accountResult := checks.AccountResult{
+		Name:           account.Username,
+		Summary:        summary,
+		ExitStatusCode: state,
+	}
+
+	switch {
+	case state != nagios.StateOKExitCode ||
+		results.GotMail() ||
+		cfg.ExpectMail ||
+		len(evaluation.FolderErrors) > 0 ||
+		len(evaluation.Rates) > 0:
+		accountResult.Report = evaluation.Report() +
+			checks.MessagesReport(results, messageListOptions(cfg))
+
+	default:
+		// Not much to say if no messages were found.
+		noMessages := "No messages"
+		if cfg.Delta {
+			noMessages = "No new messages"
+		}
+
+		accountResult.Summary = fmt.Sprintf(
+			"%s: %s found in folders: %s",
+			account.Username,
+			noMessages,
+			account.Folders.String(),
+		)
+	}
+
+	return accountResult, perfData, nil
+}
+
+// messageListOptions returns the options used to optionally list messages
+// found in each folder so that it is clear what triggered an alert.
+func messageListOptions(cfg *config.Config) checks.MessageListOptions {
+	return checks.MessageListOptions{
+		Limit:       cfg.ListMessages,
+		OldestFirst: cfg.ListOldestFirst(),
+		HTML:        cfg.ListHTML(),
+	}
+}
@@ -14,9 +14,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/atc0005/check-mail/internal/checks"
 	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
 	"github.com/atc0005/go-nagios"
 	"github.com/rs/zerolog"
 )
@@ -66,9 +69,63 @@ func main() {
 		plugin.BrandingCallback = config.Branding("Notification generated by ")
 	}
 
-	// NOTE: This plugin is still intended for checking a single account, but
-	// sufficient work is in place to allow bulk processing if there is
-	// sufficient interest.
+	// Limit plugin runtime so that transient errors are only retried while
+	// there is still time to report results.
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	checkAccounts(ctx, cfg, plugin, processAccount, cfg.AccountProcessDelay())
+}
+
+// checkAccounts checks mail for each configured account using the given
+// process function, waiting for the given delay between accounts. The
+// results, performance data and errors for all accounts are recorded in the
+// given plugin; an error for one account does not prevent checking or
+// evaluating the others.
+func checkAccounts(
+	ctx context.Context,
+	cfg *config.Config,
+	plugin *nagios.Plugin,
+	process accountProcessor,
+	delay time.Duration,
+) {
+
+	// Retries performed after transient errors for all accounts.
+	var retries int
+	defer func() {
+		retryPerfData := checks.RetryPerfData(retries, cfg.Retries*len(cfg.Accounts))
+		if err := plugin.AddPerfData(false, retryPerfData); err != nil {
+			cfg.Log.Error().Err(err).Msg("failed to add retry performance data")
+			plugin.AddError(err)
+		}
+
+		if retries > 0 {
+			plugin.LongServiceOutput += checks.RetriesReport(retries)
+		}
+	}()
+
+	// Check results for each account. The worst state of any account is
+	// used as the plugin state.
+	accountResults := make(checks.AccountResults, 0, len(cfg.Accounts))
+
+	// Results for all checked accounts; used to report message ages across
+	// all accounts.
+	var allResults mbxs.MailboxCheckResults
+
+	// List the folders checked for accounts using folder patterns, recursion
+	// or exclusions so that it is clear exactly what was evaluated.
+	var foldersChecked []string
+	defer func() {
+		if len(foldersChecked) > 0 {
+			plugin.LongServiceOutput += foldersCheckedReport(foldersChecked)
+		}
+	}()
+
+	// Every account is checked; an error or non-OK state for one account
+	// does not prevent checking the others.
 	for i, account := range cfg.Accounts {
 		// Building with `go build -gcflags=all=-d=loopvar=2` identified this
 		// loop as compiling differently with Go 1.22 (per-iteration) loop
@@ -81,6 +138,14 @@ func main() {
 		//
 		// account := account
 
+		if i > 0 {
+			// Delay processing the next account in an attempt to prevent
+			// encountering the "User is authenticated but not connected"
+			// error that is believed to occur when remote connections limit
+			// is exceeded.
+			time.Sleep(delay)
+		}
+
 		logger := cfg.Log.With().
 			Str("username", account.Username).
 			Str("server", account.Server).
@@ -88,50 +153,68 @@ func main() {
 			Str("folders_to_check", account.Folders.String()).
 			Logger()
 
-		// processAccount is responsible for setting the nagios.ExitState
-		// values, logging errors, etc.
-		results, err := processAccount(ctx, account, cfg, plugin, logger)
+		// The process function is responsible for logging errors, etc.
+		// Errors are reported alongside the results for other accounts.
+		results, missing, history, accountRetries, err := process(ctx, account, cfg, logger)
+		retries += accountRetries
 		if err != nil {
-			return
+			plugin.AddError(err)
+			accountResults = append(accountResults, accountErrorResult(account.Username, err))
+
+			continue
 		}
+		allResults = append(allResults, results...)
 
-		// Evaluate whether anything was found and sound an alert if so
-		if results.GotMail() {
-			logger.Debug().Msgf("%d messages found: %s",
-				results.TotalMessagesFound(),
-				results.MessagesFoundSummary(),
+		if account.FolderSelection().IsExpanded() {
+			foldersChecked = append(
+				foldersChecked,
+				fmt.Sprintf("%s: %s", account.Username, strings.Join(results.MailboxNames(), ", ")),
 			)
-			plugin.ServiceOutput = fmt.Sprintf(
-				"%s: %s: %d messages found: %s",
-				nagios.StateWARNINGLabel,
-				account.Username,
-				results.TotalMessagesFound(),
-				results.MessagesFoundSummary(),
-			)
-			plugin.ExitStatusCode = nagios.StateWARNINGExitCode
-			return
 		}
 
-		if i+1 < len(cfg.Accounts) {
-			// Delay processing the next account (unless we've processed them
-			// all) in an attempt to prevent encountering the "User is
-			// authenticated but not connected" error that is believed to
-			// occur when remote connections limit is exceeded.
-			time.Sleep(cfg.AccountProcessDelay())
+		// Threshold evaluation errors are reported alongside the results for
+		// other accounts.
+		accountResult, perfData, evalErr := evaluateAccount(account, results, missing, history, cfg, logger, time.Now())
+		if evalErr != nil {
+			logger.Error().Err(evalErr).Msg("Failed to evaluate thresholds")
+			plugin.AddError(evalErr)
+			accountResults = append(accountResults, accountErrorResult(account.Username, evalErr))
+
+			continue
+		}
+
+		for _, result := range results.Failed() {
+			plugin.AddError(result.Err)
+		}
+
+		if err := plugin.AddPerfData(false, perfData...); err != nil {
+			logger.Error().Err(err).Msg("failed to add mail performance data")
+			plugin.AddError(err)
 		}
+
+		accountResults = append(accountResults, accountResult)
 	}
 
-	// Give the all clear: no mail was found
-	cfg.Log.Debug().Msg("No messages found to report")
+	// Message ages are only known if message details were retrieved.
+	if cfg.FetchMessageDetails() && len(allResults) > 0 {
+		agePerfData := checks.AgePerfData(
+			allResults,
+			cfg.AgeWarningThreshold,
+			cfg.AgeCriticalThreshold,
+			time.Now(),
+		)
+		if err := plugin.AddPerfData(false, agePerfData...); err != nil {
+			cfg.Log.Error().Err(err).Msg("failed to add message age performance data")
+			plugin.AddError(err)
+		}
+	}
 
-	// these values are known, consistent regardless of checking one or many
-	// accounts
-	plugin.ExitStatusCode = nagios.StateOKExitCode
+	cfg.Log.Debug().
+		Int("accounts", len(accountResults)).
+		Int("accounts_not_ok", len(accountResults.NotOK())).
+		Msg("Accounts checked")
 
 	// customize ServiceOutput and LongServiceOutput based on number of
 	// specified accounts
-	setSummary(cfg.Accounts, plugin)
-
-	// implied return here :)
-
+	setSummary(accountResults, plugin)
 }
@@ -8,11 +8,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/files"
+	"github.com/atc0005/check-mail/internal/mbxs"
 	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
 )
 
 // TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric
@@ -54,3 +60,55 @@ func TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric(t *tes
 		t.Logf("OK: Emitted performance data contains the expected time metric.")
 	}
 }
+
+// TestAccountErrorReportedWithOtherAccounts asserts that an account which
+// could not be checked is reported alongside the results for the other
+// accounts.
+func TestAccountErrorReportedWithOtherAccounts(t *testing.T) {
+	t.Parallel()
+
+	accounts := []config.MailAccount{
+		{OAuth2Settings: config.OAuth2ClientCredentialsFlow{SharedMailbox: "queue@example.com"}, Server: "imap.example.com", Folders: []string{"INBOX"}},
+		{OAuth2Settings: config.OAuth2ClientCredentialsFlow{SharedMailbox: "intake@example.com"}, Server: "imap.example.com", Folders: []string{"INBOX"}},
+	}
+
+	cfg := &config.Config{
+		Accounts: accounts,
+		Delta:    true,
+		StateDir: t.TempDir(),
+		Log:      zerolog.Nop(),
+	}
+
+	// The state file for the first account is corrupt, so that account
+	// cannot be checked.
+	stateFile := files.StateFilename(cfg.StateDir, stateFileAppName, accounts[0].Server, accounts[0].OAuth2Settings.SharedMailbox, accounts[0].StateScope())
+	if err := os.WriteFile(stateFile, []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	process := func(
+		ctx context.Context,
+		account config.MailAccount,
+		cfg *config.Config,
+		logger zerolog.Logger,
+	) (mbxs.MailboxCheckResults, []string, map[string][]mbxs.MailboxSample, int, error) {
+		if account.OAuth2Settings.SharedMailbox == accounts[0].OAuth2Settings.SharedMailbox {
+			return processAccount(ctx, account, cfg, logger)
+		}
+
+		return mbxs.MailboxCheckResults{{MailboxName: "INBOX", ItemsFound: 2}}, nil, nil, 0, nil
+	}
+
+	plugin := nagios.NewPlugin()
+	checkAccounts(context.Background(), cfg, plugin, process, 0)
+
+	if want, got := nagios.StateCRITICALExitCode, plugin.ExitStatusCode; want != got {
+		t.Errorf("want state %d, got %d", want, got)
+	}
+
+	for _, want := range []string{"queue@example.com: Error loading state file", "intake@example.com: 2 messages found"} {
+		if !strings.Contains(plugin.ServiceOutput+plugin.LongServiceOutput, want) {
+			t.Errorf("want output to contain %q, got:\n%s\n%s", want, plugin.ServiceOutput, plugin.LongServiceOutput)
+		}
+	}
+}
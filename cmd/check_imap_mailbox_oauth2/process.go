@@ -9,42 +9,178 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"time"
 
+	"github.com/atc0005/check-mail/internal/checks"
 	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/files"
 	"github.com/atc0005/check-mail/internal/mbxs"
-	"github.com/atc0005/go-nagios"
 	"github.com/rs/zerolog"
 )
 
+// stateFileAppName identifies this plugin in the names of state files used
+// in delta mode and for rate evaluation.
+const stateFileAppName string = "check_imap_mailbox_oauth2"
+
+// accountError records an error which prevented checking an account along
+// with a summary of the step which failed.
+type accountError struct {
+	summary string
+	err     error
+}
+
+// Error implements the error interface.
+func (e *accountError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *accountError) Unwrap() error {
+	return e.err
+}
+
+// accountErrorResult returns the check result for an account which could not
+// be checked due to the given error. The state is based on the error
+// category.
+func accountErrorResult(name string, err error) checks.AccountResult {
+	summary := err.Error()
+
+	var acctErr *accountError
+	if errors.As(err, &acctErr) {
+		summary = acctErr.summary
+	}
+
+	return checks.AccountResult{
+		Name:           name,
+		Summary:        summary,
+		ExitStatusCode: checks.ErrorExitCode(err),
+		Err:            err,
+	}
+}
+
+// accountProcessor checks mail for the given account and returns the
+// results, missing folders, recorded check history and number of retries
+// performed. The processAccount function is used outside of tests.
+type accountProcessor func(
+	ctx context.Context,
+	account config.MailAccount,
+	cfg *config.Config,
+	logger zerolog.Logger,
+) (mbxs.MailboxCheckResults, []string, map[string][]mbxs.MailboxSample, int, error)
+
+// processAccount checks mail for the given account. The connect, login and
+// mail check sequence is retried (within the plugin timeout) if a transient
+// error occurs, including when checking individual folders. The number of
+// retries is returned along with the results. In delta mode the state file
+// for the account is used to limit the check to messages which arrived
+// since the previous check and is updated once the check completes. If rate
+// evaluation is enabled a sample of each folder is recorded in the state
+// file and the recorded check history is returned.
 func processAccount(
 	ctx context.Context,
 	account config.MailAccount,
 	cfg *config.Config,
-	state *nagios.Plugin,
 	logger zerolog.Logger,
-) (mbxs.MailboxCheckResults, error) {
+) (mbxs.MailboxCheckResults, []string, map[string][]mbxs.MailboxSample, int, error) {
+
+	filter := account.MessageFilter()
+
+	var state files.AccountState
+	var stateFile string
+	if cfg.Delta || cfg.Rates {
+		stateFile = files.StateFilename(cfg.StateDir, stateFileAppName, account.Server, account.OAuth2Settings.SharedMailbox, account.StateScope())
+		logger = logger.With().Str("state_file", stateFile).Logger()
+
+		var stateErr error
+		state, stateErr = files.LoadAccountState(stateFile)
+		if stateErr != nil {
+			logger.Error().Err(stateErr).Msg("failed to load state file")
+			return nil, nil, nil, 0, &accountError{summary: "Error loading state file", err: stateErr}
+		}
+	}
+
+	if cfg.Delta {
+		filter.Checkpoints = state.Checkpoints()
+	}
+
+	var results mbxs.MailboxCheckResults
+	var missing []string
+
+	retries, err := mbxs.Retry(ctx, cfg.RetryPolicy(), logger, func() error {
+		var checkErr error
+		results, missing, checkErr = checkAccount(ctx, account, filter, cfg, logger)
+		if checkErr != nil {
+			return checkErr
+		}
+
+		if folderErr := results.Err(); mbxs.IsTransient(folderErr) {
+			return folderErr
+		}
+
+		return nil
+	})
+
+	var acctErr *accountError
+	if errors.As(err, &acctErr) {
+		return nil, nil, nil, retries, acctErr
+	}
+
+	var history map[string][]mbxs.MailboxSample
+	if cfg.Delta || cfg.Rates {
+		now := time.Now()
+		state.Account = account.OAuth2Settings.SharedMailbox
+		state.Server = account.Server
+
+		// Folders which could not be checked retain their previous position
+		// so that messages which arrived since are counted by the next
+		// check.
+		if cfg.Delta {
+			state.Update(results, now)
+		}
+
+		// Samples are only recorded for folders which were checked; history
+		// older than the longest rate window is discarded.
+		if cfg.Rates {
+			state.Record(results, now, cfg.RateHistoryRetention())
+			history = state.History
+		}
+
+		if saveErr := files.SaveAccountState(stateFile, state); saveErr != nil {
+			logger.Error().Err(saveErr).Msg("failed to save state file")
+			return nil, nil, nil, retries, &accountError{summary: "Error saving state file", err: saveErr}
+		}
+		logger.Debug().Msg("Saved state file")
+	}
+
+	// Folders which still could not be checked after retrying are reported
+	// alongside the results for the remaining folders.
+	return results, missing, history, retries, nil
+}
+
+// checkAccount connects and logs in to the server and checks mail in the
+// folders specified for the given account using the given message filter.
+func checkAccount(
+	ctx context.Context,
+	account config.MailAccount,
+	filter mbxs.MessageFilter,
+	cfg *config.Config,
+	logger zerolog.Logger,
+) (mbxs.MailboxCheckResults, []string, error) {
 
 	c, connectErr := mbxs.Connect(account.Server, account.Port, cfg.NetworkType, cfg.MinTLSVersion(), logger)
 	if connectErr != nil {
 		logger.Error().Err(connectErr).Msg("error connecting to server")
-		state.AddError(connectErr)
-		state.ServiceOutput = fmt.Sprintf(
-			"%s: Error connecting to %s",
-			nagios.StateCRITICALLabel,
-			account.Server,
-		)
-		state.ExitStatusCode = nagios.StateCRITICALExitCode
-
-		return nil, connectErr
+		return nil, nil, &accountError{summary: "Error connecting to " + account.Server, err: connectErr}
 	}
 	logger.Debug().Msg("Connection established to server")
 
 	// Enable client network command/response logging if global logging
-	// level indicates user wishes to see verbose details.
+	// level indicates user wishes to see verbose details. Credentials are
+	// masked so that debug output can be safely shared.
 	if zerolog.GlobalLevel() == zerolog.DebugLevel ||
 		zerolog.GlobalLevel() == zerolog.TraceLevel {
-		c.SetDebug(&logger)
+		c.SetDebug(mbxs.NewRedactingDebugWriter(&logger))
 	}
 
 	// https://github.com/emersion/go-imap#client-
@@ -57,59 +193,87 @@ func processAccount(
 		logger.Debug().Msg("Connection to server successfully closed")
 	}()
 
-	loginErr := mbxs.OAuth2ClientCredsAuth(
-		ctx,
-		c,
-		account.OAuth2Settings.SharedMailbox,
-		account.OAuth2Settings.ClientID,
-		account.OAuth2Settings.ClientSecret,
-		account.OAuth2Settings.Scopes,
-		account.OAuth2Settings.TokenURL,
-		cfg.RetrievalAttempts(),
-		logger,
-	)
-	if loginErr != nil {
-		logger.Error().Err(loginErr).Msg("Login error occurred")
-		state.AddError(loginErr)
-		state.ServiceOutput = fmt.Sprintf(
-			"%s: Login error occurred",
-			nagios.StateCRITICALLabel,
+	var loginErr error
+	switch {
+	case account.OAuth2Settings.UsesJWTBearerGrant():
+		loginErr = mbxs.OAuth2JWTBearerAuth(
+			ctx,
+			c,
+			account.OAuth2Settings.SharedMailbox,
+			account.OAuth2Settings.ServiceAccountKeyFile,
+			account.OAuth2Settings.ImpersonatedSubject(),
+			account.OAuth2Settings.Scopes,
+			account.OAuth2Settings.TokenURL,
+			cfg.RetrievalAttempts(),
+			logger,
 		)
-		state.ExitStatusCode = nagios.StateCRITICALExitCode
 
-		return nil, loginErr
+	default:
+		loginErr = mbxs.OAuth2ClientCredsAuth(
+			ctx,
+			c,
+			account.OAuth2Settings.SharedMailbox,
+			account.OAuth2Settings.ClientID,
+			account.OAuth2Settings.ClientSecret,
+			account.OAuth2Settings.Scopes,
+			account.OAuth2Settings.TokenURL,
+			cfg.RetrievalAttempts(),
+			logger,
+		)
+	}
+	if loginErr != nil {
+		logger.Error().Err(loginErr).Msg("Login error occurred")
+		return nil, nil, &accountError{summary: "Login error occurred", err: loginErr}
 	}
 	logger.Debug().Msg("Successfully logged in")
 
-	// Confirm that requested folders are present on server
-	validatedMBXList, validateErr := mbxs.ValidateMailboxesList(
-		c, account.Folders, logger)
-	if validateErr != nil {
-		state.AddError(validateErr)
-		state.ServiceOutput = fmt.Sprintf(
-			"%s: %s",
-			nagios.StateCRITICALLabel,
-			validateErr.Error(),
-		)
-		state.ExitStatusCode = nagios.StateCRITICALExitCode
+	// Send and receive international folder names as UTF-8 (instead of
+	// modified UTF-7) if supported by the server.
+	utf8Accept, utf8Err := mbxs.EnableUTF8Accept(c, logger)
+	if utf8Err != nil {
+		logger.Warn().Err(utf8Err).Msg("failed to enable UTF-8 mailbox names")
+	}
+	session := &mbxs.Session{Client: c, UTF8Accept: utf8Accept}
 
-		return nil, validateErr
+	// Expand folder patterns and confirm that requested folders are present
+	// on server. Folders which are found are still checked if others are
+	// missing; the missing folders are returned for evaluation.
+	var missing []string
+	validatedMBXList, validateErr := mbxs.ExpandMailboxesList(
+		session, account.FolderSelection(), logger)
+	var missingErr *mbxs.MissingMailboxesError
+	switch {
+	case errors.As(validateErr, &missingErr):
+		logger.Warn().
+			Strs("missing", missingErr.Mailboxes).
+			Msg("Requested folders not found")
+		missing = missingErr.Mailboxes
 
+	case validateErr != nil:
+		return nil, nil, &accountError{summary: validateErr.Error(), err: validateErr}
 	}
 
-	results, chkMailErr := mbxs.CheckMail(c, account.OAuth2Settings.SharedMailbox, validatedMBXList, logger)
-	if chkMailErr != nil {
-		state.AddError(chkMailErr)
-		state.ServiceOutput = fmt.Sprintf(
-			"%s: Error occurred checking mail: %s",
-			nagios.StateCRITICALLabel,
-			chkMailErr.Error(),
+	// Message details are only retrieved if needed to evaluate thresholds;
+	// otherwise the (much faster) mailbox status is used to count messages.
+	var results mbxs.MailboxCheckResults
+	var chkMailErr error
+	switch {
+	case cfg.FetchMessageDetails():
+		results, chkMailErr = mbxs.CheckMail(
+			session,
+			account.OAuth2Settings.SharedMailbox,
+			validatedMBXList,
+			filter,
+			mbxs.FetchOptions{BatchSize: cfg.FetchBatchSize},
+			logger,
 		)
-		state.ExitStatusCode = nagios.StateCRITICALExitCode
-
-		return nil, chkMailErr
+	default:
+		results, chkMailErr = mbxs.CountMail(session, account.OAuth2Settings.SharedMailbox, validatedMBXList, filter, logger)
+	}
+	if chkMailErr != nil {
+		return nil, nil, &accountError{summary: "Error occurred checking mail: " + chkMailErr.Error(), err: chkMailErr}
 	}
 
-	return results, nil
+	return results, missing, nil
 
 }
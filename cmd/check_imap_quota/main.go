@@ -0,0 +1,163 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:generate go-winres make --product-version=git-tag --file-version=git-tag
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/checks"
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+func main() {
+
+	ctx := context.Background()
+
+	plugin := nagios.NewPlugin()
+
+	// defer this from the start so it is the last deferred function to run
+	defer plugin.ReturnCheckResults()
+
+	// Setup configuration by parsing user-provided flags.
+	cfg, cfgErr := config.New(config.AppType{PluginIMAPQuota: true})
+	switch {
+	case errors.Is(cfgErr, config.ErrVersionRequested):
+		fmt.Println(config.Version())
+
+		return
+
+	case errors.Is(cfgErr, config.ErrHelpRequested):
+		fmt.Println(cfg.Help())
+
+		return
+
+	case cfgErr != nil:
+		// We make some assumptions when setting up our logger as we do not
+		// have a working configuration based on sysadmin-specified choices.
+		consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr, NoColor: true}
+		logger := zerolog.New(consoleWriter).With().Timestamp().Caller().Logger()
+
+		logger.Err(cfgErr).Msg("Error initializing application")
+
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Error initializing application",
+			nagios.StateUNKNOWNLabel,
+		)
+		plugin.AddError(cfgErr)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return
+	}
+
+	if cfg.EmitBranding {
+		// If enabled, show application details at end of notification
+		plugin.BrandingCallback = config.Branding("Notification generated by ")
+	}
+
+	// Limit plugin runtime so that transient errors are only retried while
+	// there is still time to report results.
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	// Retries performed after transient errors for all accounts.
+	var retries int
+	defer func() {
+		retryPerfData := checks.RetryPerfData(retries, cfg.Retries*len(cfg.Accounts))
+		if err := plugin.AddPerfData(false, retryPerfData); err != nil {
+			cfg.Log.Error().Err(err).Msg("failed to add retry performance data")
+			plugin.AddError(err)
+		}
+
+		if retries > 0 {
+			plugin.LongServiceOutput += checks.RetriesReport(retries)
+		}
+	}()
+
+	// Check results for each account. The worst state of any account is
+	// used as the plugin state.
+	accountResults := make(checks.AccountResults, 0, len(cfg.Accounts))
+
+	// Every account is checked; an error or non-OK state for one account
+	// does not prevent checking the others.
+	for i, account := range cfg.Accounts {
+
+		if i > 0 {
+			// Delay processing the next account in an attempt to prevent
+			// encountering the "User is authenticated but not connected"
+			// error that is believed to occur when remote connections limit
+			// is exceeded.
+			time.Sleep(cfg.AccountProcessDelay())
+		}
+
+		logger := cfg.Log.With().
+			Str("auth_type", account.AuthType).
+			Str("mailbox", account.Mailbox()).
+			Str("server", account.Server).
+			Int("port", account.Port).
+			Logger()
+
+		// processAccount is responsible for logging errors, etc. Errors
+		// are reported alongside the results for other accounts.
+		quotas, accountRetries, err := processAccount(ctx, account, cfg, logger)
+		retries += accountRetries
+		if err != nil {
+			plugin.AddError(err)
+			accountResults = append(accountResults, accountErrorResult(account.Mailbox(), err))
+
+			continue
+		}
+
+		evaluation := checks.EvaluateQuotas(
+			quotas,
+			cfg.QuotaWarningThreshold,
+			cfg.QuotaCriticalThreshold,
+		)
+
+		// Metrics are labeled with the account name if multiple accounts
+		// are checked so that labels remain unique.
+		var perfDataPrefix string
+		if len(cfg.Accounts) > 1 {
+			perfDataPrefix = account.Mailbox()
+		}
+
+		if err := plugin.AddPerfData(false, checks.QuotaPerfData(perfDataPrefix, evaluation)...); err != nil {
+			logger.Error().Err(err).Msg("failed to add quota performance data")
+			plugin.AddError(err)
+		}
+
+		accountResult := quotaAccountResult(account.Mailbox(), evaluation)
+		if accountResult.ExitStatusCode != nagios.StateOKExitCode {
+			logger.Debug().
+				Str("state", nagios.ExitCodeToStateLabel(accountResult.ExitStatusCode)).
+				Msg(evaluation.Summary())
+		}
+
+		accountResults = append(accountResults, accountResult)
+	}
+
+	cfg.Log.Debug().
+		Int("accounts", len(accountResults)).
+		Int("accounts_not_ok", len(accountResults.NotOK())).
+		Msg("Accounts checked")
+
+	// customize ServiceOutput and LongServiceOutput based on number of
+	// specified accounts
+	setSummary(accountResults, plugin)
+
+}
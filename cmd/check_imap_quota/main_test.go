@@ -0,0 +1,103 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/check-mail/internal/checks"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric
+// asserts that omitted performance data from client code produces a default
+// time metric when using the Plugin constructor.
+func TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric(t *testing.T) {
+	t.Parallel()
+
+	// Setup Plugin type the same way that client code using the
+	// constructor would.
+	plugin := nagios.NewPlugin()
+
+	// Performance Data metrics are not emitted if we do not supply a
+	// ServiceOutput value.
+	plugin.ServiceOutput = "TacoTuesday"
+
+	var outputBuffer strings.Builder
+
+	plugin.SetOutputTarget(&outputBuffer)
+
+	// os.Exit calls break tests
+	plugin.SkipOSExit()
+
+	// Process exit state, emit output to our output buffer.
+	plugin.ReturnCheckResults()
+
+	want := fmt.Sprintf(
+		"%s | %s",
+		plugin.ServiceOutput,
+		"'time'=",
+	)
+
+	got := outputBuffer.String()
+
+	if !strings.Contains(got, want) {
+		t.Errorf("ERROR: Plugin output does not contain the expected time metric")
+		t.Errorf("\nwant %q\ngot %q", want, got)
+	} else {
+		t.Logf("OK: Emitted performance data contains the expected time metric.")
+	}
+}
+
+// TestSetSummaryAggregatesAccounts asserts that every account is reported
+// and that the worst state of any account is used as the plugin state, even
+// if an earlier account is not OK or could not be checked.
+func TestSetSummaryAggregatesAccounts(t *testing.T) {
+	t.Parallel()
+
+	quotaEvaluation := func(usage uint64) checks.QuotaEvaluation {
+		return checks.EvaluateQuotas([]mbxs.Quota{
+			{
+				Root: "User quota",
+				Resources: []mbxs.QuotaResource{
+					{Name: mbxs.QuotaResourceStorage, Usage: usage, Limit: 100},
+				},
+			},
+		}, 80, 90)
+	}
+
+	results := checks.AccountResults{
+		quotaAccountResult("warning@example.com", quotaEvaluation(85)),
+		accountErrorResult("broken@example.com", &accountError{
+			summary: "Login error occurred",
+			err:     fmt.Errorf("login failed: %w", mbxs.ErrAuthRejected),
+		}),
+		quotaAccountResult("critical@example.com", quotaEvaluation(95)),
+		quotaAccountResult("ok@example.com", quotaEvaluation(10)),
+	}
+
+	plugin := nagios.NewPlugin()
+	setSummary(results, plugin)
+
+	if want, got := nagios.StateCRITICALExitCode, plugin.ExitStatusCode; want != got {
+		t.Errorf("want state %d, got %d", want, got)
+	}
+
+	if want := "3 of 4 accounts not OK"; !strings.Contains(plugin.ServiceOutput, want) {
+		t.Errorf("want summary to contain %q, got %q", want, plugin.ServiceOutput)
+	}
+
+	for _, name := range []string{"warning@example.com", "broken@example.com", "critical@example.com", "ok@example.com"} {
+		if !strings.Contains(plugin.LongServiceOutput, name) {
+			t.Errorf("want report to list account %s, got:\n%s", name, plugin.LongServiceOutput)
+		}
+	}
+}
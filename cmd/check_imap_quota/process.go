@@ -0,0 +1,200 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/atc0005/check-mail/internal/checks"
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/rs/zerolog"
+)
+
+// quotaMailbox is the mailbox whose quota roots are evaluated. Every
+// account has an INBOX and the quota applied to it is typically the quota
+// applied to the account as a whole.
+const quotaMailbox string = "INBOX"
+
+// accountError records an error which prevented checking an account along
+// with a summary of the step which failed.
+type accountError struct {
+	summary string
+	err     error
+}
+
+// Error implements the error interface.
+func (e *accountError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *accountError) Unwrap() error {
+	return e.err
+}
+
+// accountErrorResult returns the check result for an account which could not
+// be checked due to the given error. The state is based on the error
+// category.
+func accountErrorResult(name string, err error) checks.AccountResult {
+	summary := err.Error()
+
+	var acctErr *accountError
+	if errors.As(err, &acctErr) {
+		summary = acctErr.summary
+	}
+
+	return checks.AccountResult{
+		Name:           name,
+		Summary:        summary,
+		ExitStatusCode: checks.ErrorExitCode(err),
+		Err:            err,
+	}
+}
+
+// quotaAccountResult returns the check result for an account with the given
+// quota evaluation results.
+func quotaAccountResult(name string, evaluation checks.QuotaEvaluation) checks.AccountResult {
+	return checks.AccountResult{
+		Name:           name,
+		Summary:        fmt.Sprintf("%s: %s", name, evaluation.Summary()),
+		Report:         evaluation.Report(),
+		ExitStatusCode: evaluation.ExitStatusCode(),
+	}
+}
+
+// processAccount retrieves quota usage for the given account. The connect,
+// login and quota retrieval sequence is retried (within the plugin timeout)
+// if a transient error occurs. The number of retries is returned along with
+// the quotas.
+func processAccount(
+	ctx context.Context,
+	account config.MailAccount,
+	cfg *config.Config,
+	logger zerolog.Logger,
+) ([]mbxs.Quota, int, error) {
+
+	var quotas []mbxs.Quota
+
+	retries, err := mbxs.Retry(ctx, cfg.RetryPolicy(), logger, func() error {
+		var checkErr error
+		quotas, checkErr = checkAccount(ctx, account, cfg, logger)
+
+		return checkErr
+	})
+
+	var acctErr *accountError
+	if errors.As(err, &acctErr) {
+		return nil, retries, acctErr
+	}
+
+	return quotas, retries, nil
+}
+
+// checkAccount connects and logs in to the server and retrieves quota usage
+// for the given account.
+func checkAccount(
+	ctx context.Context,
+	account config.MailAccount,
+	cfg *config.Config,
+	logger zerolog.Logger,
+) ([]mbxs.Quota, error) {
+
+	c, connectErr := mbxs.Connect(account.Server, account.Port, cfg.NetworkType, cfg.MinTLSVersion(), logger)
+	if connectErr != nil {
+		logger.Error().Err(connectErr).Msg("error connecting to server")
+		return nil, &accountError{summary: "Error connecting to " + account.Server, err: connectErr}
+	}
+	logger.Debug().Msg("Connection established to server")
+
+	// Enable client network command/response logging if global logging
+	// level indicates user wishes to see verbose details. Credentials are
+	// masked so that debug output can be safely shared.
+	if zerolog.GlobalLevel() == zerolog.DebugLevel ||
+		zerolog.GlobalLevel() == zerolog.TraceLevel {
+		c.SetDebug(mbxs.NewRedactingDebugWriter(&logger))
+	}
+
+	// https://github.com/emersion/go-imap#client-
+	logger.Debug().Msg("Defer closing connection to server")
+	defer func() {
+		logger.Debug().Msg("Calling Logout to gracefully close connection to server")
+		if err := c.Logout(); err != nil {
+			logger.Error().Err(err).Msg("failed to close connection to server")
+		}
+		logger.Debug().Msg("Connection to server successfully closed")
+	}()
+
+	var loginErr error
+	switch {
+	case account.AuthType == config.AuthTypeBasic:
+		loginErr = mbxs.Login(c, account.Username, account.Password, logger)
+
+	case account.OAuth2Settings.UsesJWTBearerGrant():
+		loginErr = mbxs.OAuth2JWTBearerAuth(
+			ctx,
+			c,
+			account.OAuth2Settings.SharedMailbox,
+			account.OAuth2Settings.ServiceAccountKeyFile,
+			account.OAuth2Settings.ImpersonatedSubject(),
+			account.OAuth2Settings.Scopes,
+			account.OAuth2Settings.TokenURL,
+			cfg.RetrievalAttempts(),
+			logger,
+		)
+
+	default:
+		loginErr = mbxs.OAuth2ClientCredsAuth(
+			ctx,
+			c,
+			account.OAuth2Settings.SharedMailbox,
+			account.OAuth2Settings.ClientID,
+			account.OAuth2Settings.ClientSecret,
+			account.OAuth2Settings.Scopes,
+			account.OAuth2Settings.TokenURL,
+			cfg.RetrievalAttempts(),
+			logger,
+		)
+	}
+	if loginErr != nil {
+		logger.Error().Err(loginErr).Msg("Login error occurred")
+		return nil, &accountError{summary: "Login error occurred", err: loginErr}
+	}
+	logger.Debug().Msg("Successfully logged in")
+
+	// Send and receive international folder names as UTF-8 (instead of
+	// modified UTF-7) if supported by the server.
+	utf8Accept, utf8Err := mbxs.EnableUTF8Accept(c, logger)
+	if utf8Err != nil {
+		logger.Warn().Err(utf8Err).Msg("failed to enable UTF-8 mailbox names")
+	}
+	session := &mbxs.Session{Client: c, UTF8Accept: utf8Accept}
+
+	quotas, quotaErr := mbxs.GetQuotaRoot(session, quotaMailbox, logger)
+	switch {
+	case errors.Is(quotaErr, mbxs.ErrQuotaUnsupported):
+		// Quota usage can not be determined; this is not a problem with the
+		// mailbox itself.
+		return nil, &accountError{
+			summary: fmt.Sprintf(
+				"Quota usage not available; server %s does not support the %s capability",
+				account.Server,
+				mbxs.IMAPv4CapabilityQuota,
+			),
+			err: quotaErr,
+		}
+
+	case quotaErr != nil:
+		return nil, &accountError{summary: "Error occurred retrieving quota: " + quotaErr.Error(), err: quotaErr}
+	}
+
+	return quotas, nil
+
+}
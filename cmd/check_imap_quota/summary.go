@@ -0,0 +1,28 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/atc0005/check-mail/internal/checks"
+	"github.com/atc0005/go-nagios"
+)
+
+// setSummary customizes nagios.Plugin's ServiceOutput, LongServiceOutput
+// and exit state based on the results for the user-specified accounts. The
+// worst state of any account is used as the plugin state.
+func setSummary(results checks.AccountResults, plugin *nagios.Plugin) {
+	plugin.ExitStatusCode = results.ExitStatusCode()
+	plugin.ServiceOutput = fmt.Sprintf(
+		"%s: %s",
+		nagios.ExitCodeToStateLabel(plugin.ExitStatusCode),
+		results.Summary(),
+	)
+	plugin.LongServiceOutput = results.Report()
+}
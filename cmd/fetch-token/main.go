@@ -21,6 +21,7 @@ import (
 	"github.com/atc0005/check-mail/internal/config"
 	"github.com/atc0005/check-mail/internal/oauth2"
 	"github.com/rs/zerolog"
+	goauth2 "golang.org/x/oauth2"
 )
 
 func main() {
@@ -64,15 +65,43 @@ func main() {
 
 	logger.Debug().Msg("Application configuration initialized")
 
-	logger.Debug().Msg("Fetching Client Credentials token")
-	token, err := oauth2.GetClientCredentialsToken(
-		ctx,
-		cfg.FetcherOAuth2TokenSettings.ClientID,
-		cfg.FetcherOAuth2TokenSettings.ClientSecret,
-		cfg.FetcherOAuth2TokenSettings.Scopes,
-		cfg.FetcherOAuth2TokenSettings.TokenURL,
-		cfg.RetrievalAttempts(),
-	)
+	var token *goauth2.Token
+	var err error
+	switch {
+	case cfg.FetcherOAuth2TokenSettings.UsesJWTBearerGrant():
+		logger.Debug().Msg("Loading service account key")
+		key, keyErr := oauth2.ReadServiceAccountKeyFile(
+			cfg.FetcherOAuth2TokenSettings.ServiceAccountKeyFile,
+		)
+		if keyErr != nil {
+			logger.Error().Err(keyErr).Msg("Failed to load service account key")
+			os.Exit(1)
+		}
+
+		logger.Debug().
+			Str("service_account", key.ClientEmail).
+			Str("subject", cfg.FetcherOAuth2TokenSettings.Subject).
+			Msg("Fetching JWT bearer grant token")
+		token, err = oauth2.GetJWTBearerToken(
+			ctx,
+			key,
+			cfg.FetcherOAuth2TokenSettings.Subject,
+			cfg.FetcherOAuth2TokenSettings.Scopes,
+			cfg.FetcherOAuth2TokenSettings.TokenURL,
+			cfg.RetrievalAttempts(),
+		)
+
+	default:
+		logger.Debug().Msg("Fetching Client Credentials token")
+		token, err = oauth2.GetClientCredentialsToken(
+			ctx,
+			cfg.FetcherOAuth2TokenSettings.ClientID,
+			cfg.FetcherOAuth2TokenSettings.ClientSecret,
+			cfg.FetcherOAuth2TokenSettings.Scopes,
+			cfg.FetcherOAuth2TokenSettings.TokenURL,
+			cfg.RetrievalAttempts(),
+		)
+	}
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to retrieve token")
 		os.Exit(1)
@@ -105,6 +134,21 @@ func main() {
 		emittedAsJSON = false
 	}
 
+	if cfg.FetcherOAuth2TokenSettings.EncryptionKey != "" {
+		key, err := oauth2.ParseTokenEncryptionKey(cfg.FetcherOAuth2TokenSettings.EncryptionKey)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to load token encryption key")
+			os.Exit(1)
+		}
+
+		data, err = oauth2.EncryptTokenData(key, data)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to encrypt token")
+			os.Exit(1)
+		}
+		logger.Debug().Msg("Successfully encrypted token")
+	}
+
 	switch {
 	case cfg.FetcherOAuth2TokenSettings.Filename != "":
 		err := os.WriteFile(filepath.Clean(cfg.FetcherOAuth2TokenSettings.Filename), data, 0600)
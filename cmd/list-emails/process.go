@@ -9,6 +9,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,6 +20,10 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// stateFileAppName identifies this application in the names of state files
+// used for incremental reports.
+const stateFileAppName string = "list-emails"
+
 func processAccount(
 	ctx context.Context,
 	account config.MailAccount,
@@ -38,6 +43,7 @@ func processAccount(
 		// Add OAuth2 related fields to logger.
 		logger = logger.With().
 			Str("client_id", account.OAuth2Settings.ClientID).
+			Str("grant_type", account.OAuth2Settings.GrantType).
 			Str("scopes", func() string {
 				return strings.Join(account.OAuth2Settings.Scopes, ", ")
 			}()).
@@ -52,6 +58,50 @@ func processAccount(
 
 	}
 
+	// Retry the connect, login and report sequence if a transient error
+	// (e.g., throttling or a dropped connection) occurs.
+	retries, err := mbxs.Retry(ctx, cfg.RetryPolicy(), logger, func() error {
+		return reportAccount(ctx, account, cfg, logger)
+	})
+	if retries > 0 {
+		logger.Info().
+			Int("retries", retries).
+			Msg("Retried account after transient errors")
+	}
+
+	return err
+}
+
+// reportAccount connects and logs in to the server, retrieves messages from
+// the requested folders and generates a report for the given account. For
+// incremental reports only messages which arrived since the previous report
+// are listed and the state file for the account is updated once the report
+// is generated.
+func reportAccount(
+	ctx context.Context,
+	account config.MailAccount,
+	cfg *config.Config,
+	logger zerolog.Logger,
+) error {
+
+	filter := account.MessageFilter()
+
+	var state files.AccountState
+	var stateFile string
+	if cfg.Delta {
+		stateFile = files.StateFilename(cfg.StateDir, stateFileAppName, account.Server, account.Name, account.StateScope())
+		logger = logger.With().Str("state_file", stateFile).Logger()
+
+		var stateErr error
+		state, stateErr = files.LoadAccountState(stateFile)
+		if stateErr != nil {
+			logger.Error().Err(stateErr).Msg("failed to load state file")
+			return stateErr
+		}
+
+		filter.Checkpoints = state.Checkpoints()
+	}
+
 	c, connectErr := mbxs.Connect(account.Server, account.Port, cfg.NetworkType, cfg.MinTLSVersion(), logger)
 	if connectErr != nil {
 		logger.Error().Err(connectErr).Msg("failed to connect to server")
@@ -60,10 +110,11 @@ func processAccount(
 	logger.Info().Msg("Connection established to server")
 
 	// Enable client network command/response logging if global logging
-	// level indicates user wishes to see verbose details.
+	// level indicates user wishes to see verbose details. Credentials are
+	// masked so that debug output can be safely shared.
 	if zerolog.GlobalLevel() == zerolog.DebugLevel ||
 		zerolog.GlobalLevel() == zerolog.TraceLevel {
-		c.SetDebug(&logger)
+		c.SetDebug(mbxs.NewRedactingDebugWriter(&logger))
 	}
 
 	// https://github.com/emersion/go-imap#client-
@@ -85,21 +136,42 @@ func processAccount(
 		}
 
 	case config.AuthTypeOAuth2ClientCreds:
-		loginErr := mbxs.OAuth2ClientCredsAuth(
-			ctx,
-			c,
-			account.OAuth2Settings.SharedMailbox,
-			account.OAuth2Settings.ClientID,
-			account.OAuth2Settings.ClientSecret,
-			account.OAuth2Settings.Scopes,
-			account.OAuth2Settings.TokenURL,
-
-			// We're going to use the default/fallback value instead of
-			// exposing a max retrieval attempts flag or attempting to pull
-			// the value from a config file.
-			cfg.RetrievalAttempts(),
-			logger,
-		)
+		var loginErr error
+		switch {
+		case account.OAuth2Settings.UsesJWTBearerGrant():
+			loginErr = mbxs.OAuth2JWTBearerAuth(
+				ctx,
+				c,
+				account.OAuth2Settings.SharedMailbox,
+				account.OAuth2Settings.ServiceAccountKeyFile,
+				account.OAuth2Settings.ImpersonatedSubject(),
+				account.OAuth2Settings.Scopes,
+				account.OAuth2Settings.TokenURL,
+
+				// We're going to use the default/fallback value instead of
+				// exposing a max retrieval attempts flag or attempting to
+				// pull the value from a config file.
+				cfg.RetrievalAttempts(),
+				logger,
+			)
+
+		default:
+			loginErr = mbxs.OAuth2ClientCredsAuth(
+				ctx,
+				c,
+				account.OAuth2Settings.SharedMailbox,
+				account.OAuth2Settings.ClientID,
+				account.OAuth2Settings.ClientSecret,
+				account.OAuth2Settings.Scopes,
+				account.OAuth2Settings.TokenURL,
+
+				// We're going to use the default/fallback value instead of
+				// exposing a max retrieval attempts flag or attempting to
+				// pull the value from a config file.
+				cfg.RetrievalAttempts(),
+				logger,
+			)
+		}
 		if loginErr != nil {
 			logger.Error().Err(loginErr).Msg("failed to login to server")
 			return loginErr
@@ -108,21 +180,99 @@ func processAccount(
 
 	}
 
-	// Confirm that requested folders are present on server
-	validatedMBXList, validateErr := mbxs.ValidateMailboxesList(
-		c, account.Folders, logger)
-	if validateErr != nil {
+	// Send and receive international folder names as UTF-8 (instead of
+	// modified UTF-7) if supported by the server.
+	utf8Accept, utf8Err := mbxs.EnableUTF8Accept(c, logger)
+	if utf8Err != nil {
+		logger.Warn().Err(utf8Err).Msg("failed to enable UTF-8 mailbox names")
+	}
+	session := &mbxs.Session{Client: c, UTF8Accept: utf8Accept}
+
+	// Expand folder patterns and confirm that requested folders are present
+	// on server. Folders which are found are still reported on if others are
+	// missing.
+	validatedMBXList, validateErr := mbxs.ExpandMailboxesList(
+		session, account.FolderSelection(), logger)
+	var missingErr *mbxs.MissingMailboxesError
+	switch {
+	case errors.As(validateErr, &missingErr):
+		logger.Warn().
+			Strs("missing", missingErr.Mailboxes).
+			Msg("Requested folders not found")
+
+	case validateErr != nil:
 		logger.Error().Err(validateErr).Msg("failed to validate mailboxes list")
 		return validateErr
+	}
 
+	// Report rows are written to a spool file as messages are retrieved in
+	// order to limit memory usage for very large mailboxes.
+	spool, spoolErr := files.NewMessageSpool(cfg.ReportFileOutputDir)
+	if spoolErr != nil {
+		logger.Error().Err(spoolErr).Msg("failed to create message spool file")
+		return spoolErr
 	}
+	defer func() {
+		if err := spool.Close(); err != nil {
+			logger.Error().Err(err).Msg("failed to remove message spool file")
+		}
+	}()
+
+	var lastMailbox string
+	fetchOpts := mbxs.FetchOptions{
+		BatchSize:   cfg.FetchBatchSize,
+		MaxMessages: cfg.MaxMessagesPerFolder,
+		OnMessage: func(mailbox string, msg mbxs.Message) error {
+			if cfg.LoggingLevel == config.LogLevelDebug {
+				if mailbox != lastMailbox {
+					fmt.Printf(
+						"\n\nEmail messages in mailbox %q: \n\n",
+						mailbox,
+					)
+					lastMailbox = mailbox
+				}
+				printMessage(msg)
+			}
 
-	results, chkMailErr := mbxs.CheckMail(c, account.Name, validatedMBXList, logger)
+			return spool.Add(mailbox, msg)
+		},
+	}
+
+	results, chkMailErr := mbxs.CheckMail(session, account.Name, validatedMBXList, filter, fetchOpts, logger)
 	if chkMailErr != nil {
 		logger.Error().Err(chkMailErr).Msg("failed to check mail in mailboxes")
 		return chkMailErr
 	}
 
+	for _, result := range results.Failed() {
+		logger.Error().
+			Err(result.Err).
+			Str("mailbox", result.MailboxName).
+			Msg("failed to check mail in mailbox")
+	}
+
+	// Retry the account if a folder could not be checked due to a transient
+	// error instead of generating a report which omits it.
+	if folderErr := results.Err(); mbxs.IsTransient(folderErr) {
+		return folderErr
+	}
+
+	if reset := results.CheckpointsReset(); len(reset) > 0 {
+		logger.Warn().
+			Strs("mailboxes", reset).
+			Msg("UIDVALIDITY changed since previous report; all messages listed")
+	}
+
+	messageRows, spoolReadErr := spool.Reader()
+	if spoolReadErr != nil {
+		logger.Error().Err(spoolReadErr).Msg("failed to read message spool file")
+		return spoolReadErr
+	}
+
+	logger.Debug().
+		Int("report_rows", spool.Rows()).
+		Msg("Collected report rows for retrieved messages")
+
 	summaryMsg := fmt.Sprintf("%d messages found: %s",
 		results.TotalMessagesFound(),
 		results.MessagesFoundSummary(),
@@ -136,6 +286,7 @@ func processAccount(
 		MessagesFoundSummary:  results.MessagesFoundSummary(),
 		ReportTime:            time.Now(),
 		UnicodeCharSubstitute: mbxs.DefaultReplacementString,
+		MessageRows:           messageRows,
 	}
 
 	reportGenErr := files.GenerateReport(reportData, cfg.ReportFileOutputDir, logger)
@@ -149,39 +300,62 @@ func processAccount(
 		)
 	}
 
-	if cfg.LoggingLevel == config.LogLevelDebug {
-		for _, mailbox := range results {
-			fmt.Printf(
-				"\n\nEmail messages in mailbox %q: \n\n",
-				mailbox.MailboxName,
-			)
+	// Folders which could not be checked retain their previous position so
+	// that messages which arrived since are listed by the next report.
+	if cfg.Delta {
+		state.Account = account.Name
+		state.Server = account.Server
+		state.Update(results, reportData.ReportTime)
 
-			if len(mailbox.Messages) == 0 {
-				fmt.Println("* No messages")
-				continue
-			}
+		if saveErr := files.SaveAccountState(stateFile, state); saveErr != nil {
+			logger.Error().Err(saveErr).Msg("failed to save state file")
+			return saveErr
+		}
+		logger.Debug().Msg("Saved state file")
+	}
 
-			for _, msg := range mailbox.Messages {
+	if cfg.LoggingLevel == config.LogLevelDebug {
+		for _, mailbox := range results {
+			if mailbox.ItemsFound == 0 {
 				fmt.Printf(
-					// "{\n\tMessageID: %s\n\tEnvelopeDate: %v\n\tEnvelopeLocalDate: %v\n\tReceivedDate: %v\n\tReceivedLocalDate: %v\n\tOriginalSubject: %s\n\tModifiedSubject: %s\n}\n",
-					"{\n\tMessageID: %s\n\t"+
-						"EnvelopeDate: %v\n\t"+
-						"EnvelopeLocalDate: %v\n\t"+
-						"OriginalSubject: %s\n\t"+
-						"ModifiedSubject: %s\n}\n",
-					msg.MessageID,
-					msg.EnvelopeDate,
-					msg.EnvelopeDate.Local(),
-					// msg.ReceivedDate,
-					// msg.ReceivedDate.Local(),
-					msg.OriginalSubject,
-					msg.ModifiedSubject,
+					"\n\nEmail messages in mailbox %q: \n\n* No messages\n",
+					mailbox.MailboxName,
 				)
 			}
-
 		}
-
 	}
 
 	return nil
 }
+
+// printMessage prints the details of a retrieved message to the console.
+func printMessage(msg mbxs.Message) {
+	fmt.Printf(
+		"{\n\tMessageID: %s\n\t"+
+			"UID: %d\n\t"+
+			"EnvelopeDate: %v\n\t"+
+			"EnvelopeLocalDate: %v\n\t"+
+			"InternalDate: %v\n\t"+
+			"From: %s\n\t"+
+			"Sender: %s\n\t"+
+			"ReplyTo: %s\n\t"+
+			"To: %s\n\t"+
+			"Size: %s\n\t"+
+			"Flags: %s\n\t"+
+			"OriginalSubject: %s\n\t"+
+			"ModifiedSubject: %s\n}\n",
+		msg.MessageID,
+		msg.UID,
+		msg.EnvelopeDate,
+		msg.EnvelopeDate.Local(),
+		msg.InternalDate,
+		msg.From,
+		msg.Sender,
+		msg.ReplyTo,
+		msg.To,
+		msg.SizeFormatted(),
+		strings.Join(msg.Flags, " "),
+		msg.OriginalSubject,
+		msg.ModifiedSubject,
+	)
+}
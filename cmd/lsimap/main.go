@@ -66,10 +66,11 @@ func main() {
 		logger.Info().Msg("Connection established to server")
 
 		// Enable client network command/response logging if global logging
-		// level indicates user wishes to see verbose details.
+		// level indicates user wishes to see verbose details. Credentials are
+		// masked so that debug output can be safely shared.
 		if zerolog.GlobalLevel() == zerolog.DebugLevel ||
 			zerolog.GlobalLevel() == zerolog.TraceLevel {
-			c.SetDebug(&logger)
+			c.SetDebug(mbxs.NewRedactingDebugWriter(&logger))
 		}
 
 		logger.Info().Msg("Gathering pre-login capabilities")
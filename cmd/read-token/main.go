@@ -18,8 +18,9 @@ import (
 	"time"
 
 	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/oauth2"
 	"github.com/rs/zerolog"
-	"golang.org/x/oauth2"
+	goauth2 "golang.org/x/oauth2"
 )
 
 func main() {
@@ -63,12 +64,38 @@ func main() {
 	}
 	logger.Debug().Msg("Successfully read file contents")
 
+	switch {
+	case oauth2.IsEncryptedTokenData(data):
+		if cfg.FetcherOAuth2TokenSettings.EncryptionKey == "" {
+			logger.Error().Msg("File contents are encrypted but encryption key not provided")
+			os.Exit(1)
+		}
+
+		key, err := oauth2.ParseTokenEncryptionKey(cfg.FetcherOAuth2TokenSettings.EncryptionKey)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to load token encryption key")
+			os.Exit(1)
+		}
+
+		data, err = oauth2.DecryptTokenData(key, data)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to decrypt file contents")
+			os.Exit(1)
+		}
+		logger.Debug().Msg("Successfully decrypted file contents")
+
+	case cfg.FetcherOAuth2TokenSettings.EncryptionKey != "":
+		// Permit reading plaintext files written before encryption was
+		// enabled.
+		logger.Warn().Msg("Encryption key provided but file contents are not encrypted")
+	}
+
 	var output []byte
 	switch {
 	case bytes.Contains(data, []byte("{")):
 		logger.Error().Err(err).Msg("File contents appear to be JSON, will attempt to parse as JSON")
 
-		var token oauth2.Token
+		var token goauth2.Token
 		if err := json.Unmarshal(data, &token); err != nil {
 			logger.Error().Err(err).Msg("Failed to parse file contents as JSON")
 			os.Exit(1)
@@ -0,0 +1,168 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// AccountResult is the outcome of checking mail for a single account.
+type AccountResult struct {
+	// Name identifies the account (e.g., the username or shared mailbox).
+	Name string
+
+	// Summary is a one line summary of the check result for the account
+	// without a state label.
+	Summary string
+
+	// Report is the detailed threshold evaluation report for the account.
+	// This is empty if there is nothing of note to report (e.g., no
+	// messages were found).
+	Report string
+
+	// ExitStatusCode is the Nagios state for the account.
+	ExitStatusCode int
+
+	// Err is the error which prevented checking the account, if any.
+	Err error
+}
+
+// AccountResults is a collection of account check results.
+type AccountResults []AccountResult
+
+// label returns the summary for the account, prefixed with the account name
+// if the summary does not already identify the account.
+func (ar AccountResult) label() string {
+	if ar.Err != nil {
+		return fmt.Sprintf("%s: %s", ar.Name, ar.Summary)
+	}
+
+	return ar.Summary
+}
+
+// ExitStatusCode returns the worst Nagios state of all accounts in the
+// collection or an OK state if the collection is empty.
+func (ars AccountResults) ExitStatusCode() int {
+	exitCode := nagios.StateOKExitCode
+	for _, ar := range ars {
+		if ar.ExitStatusCode > exitCode {
+			exitCode = ar.ExitStatusCode
+		}
+	}
+
+	return exitCode
+}
+
+// NotOK returns the accounts in the collection with a non-OK state.
+func (ars AccountResults) NotOK() AccountResults {
+	notOK := make(AccountResults, 0, len(ars))
+	for _, ar := range ars {
+		if ar.ExitStatusCode != nagios.StateOKExitCode {
+			notOK = append(notOK, ar)
+		}
+	}
+
+	return notOK
+}
+
+// Names returns the names of all accounts in the collection.
+func (ars AccountResults) Names() []string {
+	names := make([]string, 0, len(ars))
+	for _, ar := range ars {
+		names = append(names, ar.Name)
+	}
+
+	return names
+}
+
+// Summary returns a one line summary (without a state label) of the check
+// results for all accounts in the collection. Accounts with a non-OK state
+// are summarized if present, otherwise accounts with something of note to
+// report.
+func (ars AccountResults) Summary() string {
+	if len(ars) == 1 {
+		if ars[0].ExitStatusCode == nagios.StateOKExitCode && ars[0].Report != "" {
+			return fmt.Sprintf("%s (within thresholds)", ars[0].Summary)
+		}
+
+		return ars[0].Summary
+	}
+
+	if notOK := ars.NotOK(); len(notOK) > 0 {
+		labels := make([]string, 0, len(notOK))
+		for _, ar := range notOK {
+			labels = append(labels, ar.label())
+		}
+
+		return fmt.Sprintf(
+			"%d of %d accounts not OK: %s",
+			len(notOK),
+			len(ars),
+			strings.Join(labels, "; "),
+		)
+	}
+
+	labels := make([]string, 0, len(ars))
+	for _, ar := range ars {
+		if ar.Report != "" {
+			labels = append(labels, ar.label())
+		}
+	}
+
+	if len(labels) == 0 {
+		return fmt.Sprintf(
+			"No messages found in specified folders for accounts: %s",
+			strings.Join(ars.Names(), ", "),
+		)
+	}
+
+	return fmt.Sprintf("%s (within thresholds)", strings.Join(labels, "; "))
+}
+
+// Report returns a LongServiceOutput breakdown of the check results for each
+// account in the collection followed by the detailed report for each account
+// which has one. Only the detailed report is returned if the collection
+// contains a single account.
+func (ars AccountResults) Report() string {
+	if len(ars) == 1 {
+		return ars[0].Report
+	}
+
+	var report strings.Builder
+
+	fmt.Fprintf(&report, "Accounts checked:%s%s", nagios.CheckOutputEOL, nagios.CheckOutputEOL)
+	for _, ar := range ars {
+		fmt.Fprintf(
+			&report,
+			"* %s: %s%s",
+			nagios.ExitCodeToStateLabel(ar.ExitStatusCode),
+			ar.label(),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	for _, ar := range ars {
+		if ar.Report == "" {
+			continue
+		}
+
+		fmt.Fprintf(
+			&report,
+			"%sAccount %s:%s%s",
+			nagios.CheckOutputEOL,
+			ar.Name,
+			nagios.CheckOutputEOL,
+			ar.Report,
+		)
+	}
+
+	return report.String()
+}
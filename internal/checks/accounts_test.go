@@ -0,0 +1,134 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// TestAccountResults asserts that the worst account state is used and that
+// account summaries are combined as expected.
+func TestAccountResults(t *testing.T) {
+	t.Parallel()
+
+	ok := AccountResult{
+		Name:           "alice",
+		Summary:        "alice: No messages found in folders: INBOX",
+		ExitStatusCode: nagios.StateOKExitCode,
+	}
+	okWithMail := AccountResult{
+		Name:           "bob",
+		Summary:        "bob: 2 messages found: INBOX(2)",
+		Report:         "Threshold evaluation:",
+		ExitStatusCode: nagios.StateOKExitCode,
+	}
+	warning := AccountResult{
+		Name:           "carol",
+		Summary:        "carol: 10 messages found: INBOX(10)",
+		Report:         "Threshold evaluation:",
+		ExitStatusCode: nagios.StateWARNINGExitCode,
+	}
+	failed := AccountResult{
+		Name:           "dave",
+		Summary:        "Login error occurred",
+		ExitStatusCode: nagios.StateCRITICALExitCode,
+		Err:            errors.New("login failed"),
+	}
+
+	tests := map[string]struct {
+		results     AccountResults
+		wantState   int
+		wantSummary string
+	}{
+		"single account without mail": {
+			results:     AccountResults{ok},
+			wantState:   nagios.StateOKExitCode,
+			wantSummary: "alice: No messages found in folders: INBOX",
+		},
+		"single account with mail": {
+			results:     AccountResults{okWithMail},
+			wantState:   nagios.StateOKExitCode,
+			wantSummary: "bob: 2 messages found: INBOX(2) (within thresholds)",
+		},
+		"single account with error": {
+			results:     AccountResults{failed},
+			wantState:   nagios.StateCRITICALExitCode,
+			wantSummary: "Login error occurred",
+		},
+		"multiple accounts without mail": {
+			results:     AccountResults{ok, ok},
+			wantState:   nagios.StateOKExitCode,
+			wantSummary: "No messages found in specified folders for accounts: alice, alice",
+		},
+		"multiple accounts within thresholds": {
+			results:     AccountResults{ok, okWithMail},
+			wantState:   nagios.StateOKExitCode,
+			wantSummary: "bob: 2 messages found: INBOX(2) (within thresholds)",
+		},
+		"worst state used": {
+			results:     AccountResults{okWithMail, failed, warning},
+			wantState:   nagios.StateCRITICALExitCode,
+			wantSummary: "2 of 3 accounts not OK: dave: Login error occurred; carol: 10 messages found: INBOX(10)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.results.ExitStatusCode(); got != tt.wantState {
+				t.Errorf("want state %d, got %d", tt.wantState, got)
+			}
+
+			if got := tt.results.Summary(); got != tt.wantSummary {
+				t.Errorf("\nwant summary %q\ngot %q", tt.wantSummary, got)
+			}
+		})
+	}
+}
+
+// TestAccountResultsReport asserts that the report for multiple accounts
+// lists the state of every account without exposing account settings.
+func TestAccountResultsReport(t *testing.T) {
+	t.Parallel()
+
+	results := AccountResults{
+		{
+			Name:           "alice",
+			Summary:        "alice: 10 messages found: INBOX(10)",
+			Report:         "Threshold evaluation:",
+			ExitStatusCode: nagios.StateWARNINGExitCode,
+		},
+		{
+			Name:           "bob",
+			Summary:        "Login error occurred",
+			ExitStatusCode: nagios.StateCRITICALExitCode,
+			Err:            errors.New("login failed"),
+		},
+	}
+
+	report := results.Report()
+
+	for _, want := range []string{
+		"* WARNING: alice: 10 messages found: INBOX(10)",
+		"* CRITICAL: bob: Login error occurred",
+		"Account alice:",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("want report to contain %q, got:\n%s", want, report)
+		}
+	}
+
+	if strings.Contains(report, "Account bob:") {
+		t.Errorf("want no detailed report for account without one, got:\n%s", report)
+	}
+}
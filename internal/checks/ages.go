@@ -0,0 +1,153 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// AgeThresholdResult is the result of evaluating the age of the oldest
+// message in a folder against warning and critical age thresholds.
+type AgeThresholdResult struct {
+	// Label is the name of the evaluated folder.
+	Label string
+
+	// OldestAge is the age of the oldest message found in the folder.
+	OldestAge time.Duration
+
+	// NewestAge is the age of the newest message found in the folder.
+	NewestAge time.Duration
+
+	// Warning is the maximum age permitted before a WARNING state is
+	// indicated. A zero value disables this threshold.
+	Warning time.Duration
+
+	// Critical is the maximum age permitted before a CRITICAL state is
+	// indicated. A zero value disables this threshold.
+	Critical time.Duration
+
+	// ExitStatusCode is the Nagios state resulting from the evaluation.
+	ExitStatusCode int
+}
+
+// EvaluateAgeThresholds evaluates the age of the oldest message in each
+// folder with messages against the given warning and critical thresholds.
+// Ages are relative to the given time. Evaluation is skipped (nil is
+// returned) if neither threshold is set.
+func EvaluateAgeThresholds(
+	results mbxs.MailboxCheckResults,
+	warning time.Duration,
+	critical time.Duration,
+	now time.Time,
+) []AgeThresholdResult {
+
+	if warning <= 0 && critical <= 0 {
+		return nil
+	}
+
+	ageResults := make([]AgeThresholdResult, 0, len(results))
+	for _, result := range results {
+		if result.OldestMessageDate.IsZero() {
+			continue
+		}
+
+		ageResult := AgeThresholdResult{
+			Label:          result.MailboxName,
+			OldestAge:      result.OldestMessageAge(now),
+			NewestAge:      result.NewestMessageAge(now),
+			Warning:        warning,
+			Critical:       critical,
+			ExitStatusCode: nagios.StateOKExitCode,
+		}
+
+		switch {
+		case critical > 0 && ageResult.OldestAge > critical:
+			ageResult.ExitStatusCode = nagios.StateCRITICALExitCode
+		case warning > 0 && ageResult.OldestAge > warning:
+			ageResult.ExitStatusCode = nagios.StateWARNINGExitCode
+		}
+
+		ageResults = append(ageResults, ageResult)
+	}
+
+	return ageResults
+}
+
+// AgePerfData returns performance data for the age (in seconds) of the
+// oldest and newest messages found across all checked folders. The given
+// warning and critical thresholds are applied to the oldest message age
+// metric.
+func AgePerfData(
+	results mbxs.MailboxCheckResults,
+	warning time.Duration,
+	critical time.Duration,
+	now time.Time,
+) []nagios.PerformanceData {
+
+	return []nagios.PerformanceData{
+		{
+			Label:             "oldest_message_age",
+			Value:             durationSeconds(results.OldestMessageAge(now)),
+			UnitOfMeasurement: "s",
+			Warn:              thresholdSeconds(warning),
+			Crit:              thresholdSeconds(critical),
+			Min:               "0",
+		},
+		{
+			Label:             "newest_message_age",
+			Value:             durationSeconds(results.NewestMessageAge(now)),
+			UnitOfMeasurement: "s",
+			Min:               "0",
+		},
+	}
+}
+
+// durationSeconds returns the given duration as whole seconds.
+func durationSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// thresholdSeconds returns the given threshold as whole seconds or an empty
+// string if the threshold is not set.
+func thresholdSeconds(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+
+	return durationSeconds(d)
+}
+
+// ageReportLine returns a single LongServiceOutput line summarizing an age
+// threshold evaluation result.
+func ageReportLine(result AgeThresholdResult) string {
+	return fmt.Sprintf(
+		"* %s: oldest message %s, newest message %s (warning: %s, critical: %s): %s%s",
+		result.Label,
+		mbxs.FormatMessageAge(result.OldestAge),
+		mbxs.FormatMessageAge(result.NewestAge),
+		displayAgeThreshold(result.Warning),
+		displayAgeThreshold(result.Critical),
+		nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+		nagios.CheckOutputEOL,
+	)
+}
+
+// displayAgeThreshold returns the given age threshold or a placeholder value
+// if not set.
+func displayAgeThreshold(d time.Duration) string {
+	if d <= 0 {
+		return thresholdNotSet
+	}
+
+	return d.String()
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestEvaluateAgeThresholds asserts that the age of the oldest message in
+// each folder is evaluated against the age thresholds.
+func TestEvaluateAgeThresholds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX", ItemsFound: 1, OldestMessageDate: now.Add(-5 * time.Hour), NewestMessageDate: now.Add(-5 * time.Hour)},
+		{MailboxName: "Tickets", ItemsFound: 2, OldestMessageDate: now.Add(-25 * time.Hour), NewestMessageDate: now.Add(-time.Hour)},
+		{MailboxName: "Junk", ItemsFound: 0},
+	}
+
+	ages := EvaluateAgeThresholds(results, 4*time.Hour, 24*time.Hour, now)
+	if len(ages) != 2 {
+		t.Fatalf("want 2 age results, got %d", len(ages))
+	}
+
+	if want, got := nagios.StateWARNINGExitCode, ages[0].ExitStatusCode; want != got {
+		t.Errorf("INBOX: want state %d, got %d", want, got)
+	}
+
+	if want, got := nagios.StateCRITICALExitCode, ages[1].ExitStatusCode; want != got {
+		t.Errorf("Tickets: want state %d, got %d", want, got)
+	}
+
+	evaluation := ThresholdEvaluation{Ages: ages}
+	if want, got := nagios.StateCRITICALExitCode, evaluation.ExitStatusCode(); want != got {
+		t.Errorf("want overall state %d, got %d", want, got)
+	}
+
+	if got := EvaluateAgeThresholds(results, 0, 0, now); got != nil {
+		t.Errorf("want no age results when thresholds are not set, got %v", got)
+	}
+
+	perfData := AgePerfData(results, 4*time.Hour, 24*time.Hour, now)
+	if want, got := "90000", perfData[0].Value; want != got {
+		t.Errorf("want oldest message age perfdata %s, got %s", want, got)
+	}
+	if want, got := "14400", perfData[0].Warn; want != got {
+		t.Errorf("want oldest message age warning threshold %s, got %s", want, got)
+	}
+	if want, got := "3600", perfData[1].Value; want != got {
+		t.Errorf("want newest message age perfdata %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package checks provides mailbox check evaluation logic shared by the Nagios
+// plugins provided by this project.
+package checks
@@ -0,0 +1,57 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"errors"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// errorStates maps error categories to the Nagios state used when a mail
+// check fails with an error of that category. Entries are evaluated in
+// order; the first matching entry is used.
+var errorStates = []struct {
+	err      error
+	exitCode int
+}{
+	// The server could not be queried as intended; the mailbox state is not
+	// known.
+	{err: mbxs.ErrQuotaUnsupported, exitCode: nagios.StateUNKNOWNExitCode},
+	{err: mbxs.ErrProtocol, exitCode: nagios.StateUNKNOWNExitCode},
+
+	// Results could not be evaluated against the specified thresholds.
+	{err: nagios.ErrInvalidRangeThreshold, exitCode: nagios.StateUNKNOWNExitCode},
+
+	// The server or mailbox is not reachable or usable as configured.
+	{err: mbxs.ErrDNSLookupFailed, exitCode: nagios.StateCRITICALExitCode},
+	{err: mbxs.ErrTimeout, exitCode: nagios.StateCRITICALExitCode},
+	{err: mbxs.ErrTLSHandshakeFailed, exitCode: nagios.StateCRITICALExitCode},
+	{err: mbxs.ErrConnectFailed, exitCode: nagios.StateCRITICALExitCode},
+	{err: mbxs.ErrAuthRejected, exitCode: nagios.StateCRITICALExitCode},
+	{err: mbxs.ErrRequiredAuthMechanismUnsupported, exitCode: nagios.StateCRITICALExitCode},
+	{err: mbxs.ErrMailboxNotFound, exitCode: nagios.StateCRITICALExitCode},
+}
+
+// ErrorExitCode returns the Nagios state exit code for the given error based
+// on its category (e.g., mbxs.ErrAuthRejected). A CRITICAL state is used for
+// errors without a known category and an OK state if the error is nil.
+func ErrorExitCode(err error) int {
+	if err == nil {
+		return nagios.StateOKExitCode
+	}
+
+	for _, state := range errorStates {
+		if errors.Is(err, state.err) {
+			return state.exitCode
+		}
+	}
+
+	return nagios.StateCRITICALExitCode
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestErrorExitCode asserts that error categories are mapped to the expected
+// Nagios states, including when wrapped.
+func TestErrorExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"nil":                {err: nil, want: nagios.StateOKExitCode},
+		"uncategorized":      {err: errors.New("something failed"), want: nagios.StateCRITICALExitCode},
+		"dns":                {err: mbxs.ErrDNSLookupFailed, want: nagios.StateCRITICALExitCode},
+		"auth rejected":      {err: fmt.Errorf("login error occurred: %w: %w", mbxs.ErrAuthRejected, errors.New("NO")), want: nagios.StateCRITICALExitCode},
+		"mechanism missing":  {err: mbxs.ErrRequiredAuthMechanismUnsupported, want: nagios.StateCRITICALExitCode},
+		"missing mailboxes":  {err: &mbxs.MissingMailboxesError{Mailboxes: []string{"Reports"}}, want: nagios.StateCRITICALExitCode},
+		"protocol error":     {err: fmt.Errorf("error occurred searching mailbox: %w", mbxs.ErrProtocol), want: nagios.StateUNKNOWNExitCode},
+		"quota unsupported":  {err: mbxs.ErrQuotaUnsupported, want: nagios.StateUNKNOWNExitCode},
+		"invalid threshold":  {err: fmt.Errorf("failed to parse range string: %w", nagios.ErrInvalidRangeThreshold), want: nagios.StateUNKNOWNExitCode},
+		"timeout":            {err: mbxs.ErrTimeout, want: nagios.StateCRITICALExitCode},
+		"tls handshake":      {err: mbxs.ErrTLSHandshakeFailed, want: nagios.StateCRITICALExitCode},
+		"connection failure": {err: mbxs.ErrConnectFailed, want: nagios.StateCRITICALExitCode},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ErrorExitCode(tt.err); got != tt.want {
+				t.Errorf("want %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// FolderErrorResult records a folder which could not be checked; either
+// because it was not found or because an error occurred checking it.
+type FolderErrorResult struct {
+	// Label is the name of the folder (or special-use role).
+	Label string
+
+	// Missing indicates whether the folder was not found.
+	Missing bool
+
+	// Err is the error encountered checking the folder. This is nil for
+	// missing folders.
+	Err error
+
+	// ExitStatusCode is the Nagios state resulting from the evaluation.
+	ExitStatusCode int
+}
+
+// EvaluateFolderErrors returns a result for each of the given missing
+// folders using the given Nagios state and a result for each folder which
+// could not be checked due to an error using the state mapped to the error
+// by ErrorExitCode.
+func EvaluateFolderErrors(
+	results mbxs.MailboxCheckResults,
+	missing []string,
+	missingState int,
+) []FolderErrorResult {
+
+	folderErrors := make([]FolderErrorResult, 0, len(missing))
+	for _, folder := range missing {
+		folderErrors = append(folderErrors, FolderErrorResult{
+			Label:          folder,
+			Missing:        true,
+			ExitStatusCode: missingState,
+		})
+	}
+
+	for _, result := range results.Failed() {
+		folderErrors = append(folderErrors, FolderErrorResult{
+			Label:          result.MailboxName,
+			Err:            result.Err,
+			ExitStatusCode: ErrorExitCode(result.Err),
+		})
+	}
+
+	return folderErrors
+}
+
+// FolderErrorsSummary returns a brief summary of the folders which could not
+// be checked suitable for use in ServiceOutput. An empty string is returned
+// if all folders were checked.
+func FolderErrorsSummary(folderErrors []FolderErrorResult) string {
+	var missing []string
+	var failed []string
+
+	for _, result := range folderErrors {
+		switch {
+		case result.Missing:
+			missing = append(missing, result.Label)
+		default:
+			failed = append(failed, result.Label)
+		}
+	}
+
+	summaries := make([]string, 0, 2)
+	if len(missing) > 0 {
+		summaries = append(summaries, fmt.Sprintf("folders not found: %s", strings.Join(missing, ", ")))
+	}
+
+	if len(failed) > 0 {
+		summaries = append(summaries, fmt.Sprintf("folders not checked: %s", strings.Join(failed, ", ")))
+	}
+
+	return strings.Join(summaries, "; ")
+}
+
+// folderErrorReportLine returns a single LongServiceOutput line summarizing
+// a folder which could not be checked.
+func folderErrorReportLine(result FolderErrorResult) string {
+	if result.Missing {
+		return fmt.Sprintf(
+			"* %s: folder not found: %s%s",
+			result.Label,
+			nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	return fmt.Sprintf(
+		"* %s: error checking folder (%v): %s%s",
+		result.Label,
+		result.Err,
+		nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+		nagios.CheckOutputEOL,
+	)
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestEvaluateFolderErrors asserts that missing folders are reported using
+// the specified state, folders which could not be checked are reported as
+// CRITICAL and that neither affects the evaluation of healthy folders.
+func TestEvaluateFolderErrors(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{
+		{
+			MailboxName:       "INBOX",
+			ItemsFound:        1,
+			NewestMessageDate: now.Add(-time.Hour),
+			Messages: []mbxs.Message{
+				{OriginalSubject: "heartbeat", InternalDate: now.Add(-time.Hour)},
+			},
+		},
+		{MailboxName: "Broken", Err: errors.New("mailbox is corrupt")},
+	}
+
+	missing := []string{"Reports", "\\Sent"}
+
+	folderErrors := EvaluateFolderErrors(results, missing, nagios.StateWARNINGExitCode)
+
+	want := []struct {
+		label   string
+		missing bool
+		state   int
+	}{
+		{label: "Reports", missing: true, state: nagios.StateWARNINGExitCode},
+		{label: "\\Sent", missing: true, state: nagios.StateWARNINGExitCode},
+		{label: "Broken", state: nagios.StateCRITICALExitCode},
+	}
+
+	if len(folderErrors) != len(want) {
+		t.Fatalf("want %d folder errors, got %d: %+v", len(want), len(folderErrors), folderErrors)
+	}
+
+	for i, w := range want {
+		got := folderErrors[i]
+		if got.Label != w.label || got.Missing != w.missing || got.ExitStatusCode != w.state {
+			t.Errorf("want %+v, got %+v", w, got)
+		}
+	}
+
+	if want, got := "folders not found: Reports, \\Sent; folders not checked: Broken", FolderErrorsSummary(folderErrors); want != got {
+		t.Errorf("want summary %q, got %q", want, got)
+	}
+
+	evaluation, err := EvaluateThresholds(results, "", "", config.FolderThresholds{
+		{Folder: "Broken", Warning: 0, Critical: 0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(evaluation.Folders) != 0 {
+		t.Errorf("want no per-folder threshold results for failed folder, got %+v", evaluation.Folders)
+	}
+
+	evaluation.Freshness = EvaluateFreshness(results, 2*time.Hour, 0, now)
+	if len(evaluation.Freshness) != 1 || evaluation.Freshness[0].Label != "INBOX" {
+		t.Errorf("want freshness evaluated for INBOX only, got %+v", evaluation.Freshness)
+	}
+
+	if evaluation.ExitStatusCode() != nagios.StateOKExitCode {
+		t.Errorf("want OK state without folder errors, got %d", evaluation.ExitStatusCode())
+	}
+
+	evaluation.FolderErrors = folderErrors
+	if evaluation.ExitStatusCode() != nagios.StateCRITICALExitCode {
+		t.Errorf("want CRITICAL state with folder errors, got %d", evaluation.ExitStatusCode())
+	}
+
+	report := evaluation.Report()
+	for _, want := range []string{"Reports: folder not found: WARNING", "mailbox is corrupt"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("want report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
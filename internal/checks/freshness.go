@@ -0,0 +1,151 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// FreshnessResult is the result of evaluating the age of the newest message
+// in a folder against warning and critical age thresholds. This is used to
+// monitor "heartbeat" folders where the absence of recent mail is a problem.
+type FreshnessResult struct {
+	// Label is the name of the evaluated folder.
+	Label string
+
+	// Found indicates whether any messages were found in the folder.
+	Found bool
+
+	// NewestAge is the age of the newest message found in the folder.
+	NewestAge time.Duration
+
+	// NewestSubject is the subject of the newest message found in the
+	// folder.
+	NewestSubject string
+
+	// NewestDate is the date of the newest message found in the folder.
+	NewestDate time.Time
+
+	// Warning is the maximum age permitted before a WARNING state is
+	// indicated. A zero value disables this threshold.
+	Warning time.Duration
+
+	// Critical is the maximum age permitted before a CRITICAL state is
+	// indicated. A zero value disables this threshold.
+	Critical time.Duration
+
+	// ExitStatusCode is the Nagios state resulting from the evaluation.
+	ExitStatusCode int
+}
+
+// EvaluateFreshness evaluates the age of the newest message in each folder
+// against the given warning and critical thresholds. Ages are relative to
+// the given time. A folder without messages is considered to have exceeded
+// the most severe threshold set. Folders which could not be checked are
+// skipped. Evaluation is skipped (nil is returned) if neither threshold is
+// set.
+func EvaluateFreshness(
+	results mbxs.MailboxCheckResults,
+	warning time.Duration,
+	critical time.Duration,
+	now time.Time,
+) []FreshnessResult {
+
+	if warning <= 0 && critical <= 0 {
+		return nil
+	}
+
+	freshnessResults := make([]FreshnessResult, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		freshnessResult := FreshnessResult{
+			Label:          result.MailboxName,
+			Warning:        warning,
+			Critical:       critical,
+			ExitStatusCode: nagios.StateOKExitCode,
+		}
+
+		newest, ok := result.NewestMessage()
+		if !ok {
+			freshnessResult.ExitStatusCode = nagios.StateWARNINGExitCode
+			if critical > 0 {
+				freshnessResult.ExitStatusCode = nagios.StateCRITICALExitCode
+			}
+
+			freshnessResults = append(freshnessResults, freshnessResult)
+
+			continue
+		}
+
+		freshnessResult.Found = true
+		freshnessResult.NewestAge = result.NewestMessageAge(now)
+		freshnessResult.NewestSubject = newest.OriginalSubject
+		freshnessResult.NewestDate = newest.ArrivalDate()
+
+		switch {
+		case critical > 0 && freshnessResult.NewestAge > critical:
+			freshnessResult.ExitStatusCode = nagios.StateCRITICALExitCode
+		case warning > 0 && freshnessResult.NewestAge > warning:
+			freshnessResult.ExitStatusCode = nagios.StateWARNINGExitCode
+		}
+
+		freshnessResults = append(freshnessResults, freshnessResult)
+	}
+
+	return freshnessResults
+}
+
+// NewestMessageSummary returns a brief summary of the newest message found
+// across all checked folders suitable for use in ServiceOutput. An empty
+// string is returned if no messages were found.
+func NewestMessageSummary(results mbxs.MailboxCheckResults) string {
+	folder, msg, ok := results.NewestMessage()
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"newest message in %s: %q (%s)",
+		folder,
+		textEscaper.Replace(msg.OriginalSubject),
+		msg.ArrivalDate().Format(time.RFC3339),
+	)
+}
+
+// freshnessReportLine returns a single LongServiceOutput line summarizing a
+// freshness evaluation result.
+func freshnessReportLine(result FreshnessResult) string {
+	if !result.Found {
+		return fmt.Sprintf(
+			"* %s: no messages found (warning: %s, critical: %s): %s%s",
+			result.Label,
+			displayAgeThreshold(result.Warning),
+			displayAgeThreshold(result.Critical),
+			nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	return fmt.Sprintf(
+		"* %s: newest message %q received %s ago (warning: %s, critical: %s): %s%s",
+		result.Label,
+		textEscaper.Replace(result.NewestSubject),
+		mbxs.FormatMessageAge(result.NewestAge),
+		displayAgeThreshold(result.Warning),
+		displayAgeThreshold(result.Critical),
+		nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+		nagios.CheckOutputEOL,
+	)
+}
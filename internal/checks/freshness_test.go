@@ -0,0 +1,157 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestEvaluateFreshness asserts that the age of the newest message in each
+// folder is evaluated against the newest message age thresholds and that
+// folders without messages are flagged.
+func TestEvaluateFreshness(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{
+		{
+			MailboxName:       "INBOX",
+			ItemsFound:        1,
+			NewestMessageDate: now.Add(-time.Hour),
+			Messages: []mbxs.Message{
+				{OriginalSubject: "heartbeat", InternalDate: now.Add(-time.Hour)},
+			},
+		},
+		{
+			MailboxName:       "Backups",
+			ItemsFound:        1,
+			NewestMessageDate: now.Add(-30 * time.Hour),
+			Messages: []mbxs.Message{
+				{OriginalSubject: "backup complete", InternalDate: now.Add(-30 * time.Hour)},
+			},
+		},
+		{MailboxName: "Alerts"},
+	}
+
+	tests := map[string]struct {
+		warning  time.Duration
+		critical time.Duration
+		want     []int
+	}{
+		"warning and critical": {
+			warning:  2 * time.Hour,
+			critical: 48 * time.Hour,
+			want: []int{
+				nagios.StateOKExitCode,
+				nagios.StateWARNINGExitCode,
+				nagios.StateCRITICALExitCode,
+			},
+		},
+		"warning only": {
+			warning: 26 * time.Hour,
+			want: []int{
+				nagios.StateOKExitCode,
+				nagios.StateWARNINGExitCode,
+				nagios.StateWARNINGExitCode,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			freshness := EvaluateFreshness(results, tt.warning, tt.critical, now)
+			if len(freshness) != len(tt.want) {
+				t.Fatalf("want %d results, got %d", len(tt.want), len(freshness))
+			}
+
+			for i, want := range tt.want {
+				if got := freshness[i].ExitStatusCode; want != got {
+					t.Errorf("%s: want state %d, got %d", freshness[i].Label, want, got)
+				}
+			}
+		})
+	}
+
+	if got := EvaluateFreshness(results, 0, 0, now); got != nil {
+		t.Errorf("want no results when thresholds are not set, got %v", got)
+	}
+
+	summary := NewestMessageSummary(results)
+	if !strings.Contains(summary, `"heartbeat"`) || !strings.Contains(summary, "INBOX") {
+		t.Errorf("want newest message summary to reference heartbeat message in INBOX, got %q", summary)
+	}
+}
+
+// TestFreshnessReportLineEscapesSubject asserts that pipe characters in the
+// subject of the newest message do not end the extended plugin output.
+func TestFreshnessReportLineEscapesSubject(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{
+		{
+			MailboxName:       "INBOX",
+			ItemsFound:        1,
+			NewestMessageDate: now.Add(-time.Hour),
+			Messages: []mbxs.Message{
+				{OriginalSubject: "backup | status=ok", InternalDate: now.Add(-time.Hour)},
+			},
+		},
+	}
+
+	freshness := EvaluateFreshness(results, 2*time.Hour, 0, now)
+	if len(freshness) != 1 {
+		t.Fatalf("want 1 result, got %d", len(freshness))
+	}
+
+	line := freshnessReportLine(freshness[0])
+	if strings.Contains(line, "|") {
+		t.Errorf("want pipe characters replaced, got %q", line)
+	}
+
+	if want := `"backup ¦ status=ok"`; !strings.Contains(line, want) {
+		t.Errorf("want report line to contain %s, got %q", want, line)
+	}
+}
+
+// TestEvaluateThresholdsExpectMail asserts that a minimum message count
+// range alerts when fewer messages are found.
+func TestEvaluateThresholdsExpectMail(t *testing.T) {
+	t.Parallel()
+
+	results := mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX", ItemsFound: 1},
+	}
+
+	evaluation, err := EvaluateThresholds(results, "3:", "1:", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want, got := nagios.StateWARNINGExitCode, evaluation.ExitStatusCode(); want != got {
+		t.Errorf("want state %d, got %d", want, got)
+	}
+
+	evaluation, err = EvaluateThresholds(mbxs.MailboxCheckResults{{MailboxName: "INBOX"}}, "", "1:", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want, got := nagios.StateCRITICALExitCode, evaluation.ExitStatusCode(); want != got {
+		t.Errorf("want state %d, got %d", want, got)
+	}
+}
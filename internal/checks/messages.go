@@ -0,0 +1,187 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/check-mail/internal/textutils"
+	"github.com/atc0005/go-nagios"
+)
+
+// messageDateLayout is the format used for message arrival dates listed in
+// the extended plugin output.
+const messageDateLayout string = "2006-01-02 15:04 MST"
+
+// textEscaper replaces pipe characters in plain text plugin output; a pipe
+// marks the start of performance data.
+var textEscaper = strings.NewReplacer("|", "¦")
+
+// htmlEscaper escapes characters with special meaning in HTML, including
+// ampersands, along with pipe characters (see textEscaper).
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&#34;",
+	"'", "&#39;",
+	"|", "&#124;",
+)
+
+// MessageListOptions controls how messages are listed in the extended
+// plugin output.
+type MessageListOptions struct {
+	// Limit is the maximum number of messages listed for each folder. A zero
+	// value disables the listing.
+	Limit int
+
+	// OldestFirst lists the oldest messages first instead of the newest.
+	OldestFirst bool
+
+	// HTML formats the listing as HTML for display in the Nagios web UI
+	// instead of as plain text.
+	HTML bool
+}
+
+// MessagesReport returns a LongServiceOutput section listing the arrival
+// date, sender and subject of up to the configured number of messages for
+// each folder with retrieved messages. An empty string is returned if
+// listing is disabled or no messages were retrieved.
+func MessagesReport(results mbxs.MailboxCheckResults, opts MessageListOptions) string {
+	if opts.Limit <= 0 {
+		return ""
+	}
+
+	var report strings.Builder
+
+	for _, result := range results {
+		if len(result.Messages) == 0 {
+			continue
+		}
+
+		messages := sortedMessages(result.Messages, opts.OldestFirst)
+		if len(messages) > opts.Limit {
+			messages = messages[:opts.Limit]
+		}
+
+		heading := fmt.Sprintf(
+			"Messages in %s (%d of %d):",
+			result.MailboxName,
+			len(messages),
+			result.ItemsFound,
+		)
+
+		switch {
+		case opts.HTML:
+			writeHTMLMessages(&report, heading, messages)
+		default:
+			writeTextMessages(&report, heading, messages)
+		}
+	}
+
+	return report.String()
+}
+
+// sortedMessages returns a copy of the given messages sorted by arrival
+// date. Messages without a known arrival date are listed last.
+func sortedMessages(messages []mbxs.Message, oldestFirst bool) []mbxs.Message {
+	sorted := append([]mbxs.Message(nil), messages...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		di, dj := sorted[i].ArrivalDate(), sorted[j].ArrivalDate()
+		switch {
+		case di.IsZero() || dj.IsZero():
+			return !di.IsZero() && dj.IsZero()
+		case oldestFirst:
+			return di.Before(dj)
+		default:
+			return di.After(dj)
+		}
+	})
+
+	return sorted
+}
+
+// writeTextMessages writes the given messages to the report as a plain text
+// list.
+func writeTextMessages(report *strings.Builder, heading string, messages []mbxs.Message) {
+	fmt.Fprintf(report, "%s%s%s", nagios.CheckOutputEOL, textEscaper.Replace(heading), nagios.CheckOutputEOL)
+
+	for _, msg := range messages {
+		fmt.Fprintf(
+			report,
+			"* %s, %s: %s%s",
+			messageDate(msg),
+			textEscaper.Replace(messageSender(msg)),
+			textEscaper.Replace(messageSubject(msg)),
+			nagios.CheckOutputEOL,
+		)
+	}
+}
+
+// writeHTMLMessages writes the given messages to the report as an HTML
+// table.
+func writeHTMLMessages(report *strings.Builder, heading string, messages []mbxs.Message) {
+	fmt.Fprintf(
+		report,
+		"%s<p><b>%s</b></p><table><tr><th>Date</th><th>From</th><th>Subject</th></tr>",
+		nagios.CheckOutputEOL,
+		htmlEscaper.Replace(heading),
+	)
+
+	for _, msg := range messages {
+		fmt.Fprintf(
+			report,
+			"<tr><td>%s</td><td>%s</td><td>%s</td></tr>",
+			htmlEscaper.Replace(messageDate(msg)),
+			htmlEscaper.Replace(messageSender(msg)),
+			htmlEscaper.Replace(messageSubject(msg)),
+		)
+	}
+
+	fmt.Fprintf(report, "</table>%s", nagios.CheckOutputEOL)
+}
+
+// messageDate returns the arrival date of the message for display.
+func messageDate(msg mbxs.Message) string {
+	date := msg.ArrivalDate()
+	if date.IsZero() {
+		return "unknown date"
+	}
+
+	return date.Format(messageDateLayout)
+}
+
+// messageSender returns the sender of the message for display. The value is
+// not escaped for the output format.
+func messageSender(msg mbxs.Message) string {
+	if msg.From == "" {
+		return "unknown sender"
+	}
+
+	return sanitizeMessageField(msg.From)
+}
+
+// messageSubject returns the original subject of the message for display.
+// The value is not escaped for the output format.
+func messageSubject(msg mbxs.Message) string {
+	if strings.TrimSpace(msg.OriginalSubject) == "" {
+		return "(no subject)"
+	}
+
+	return sanitizeMessageField(msg.OriginalSubject)
+}
+
+// sanitizeMessageField replaces control characters (e.g., line breaks) which
+// would break up plugin output lines.
+func sanitizeMessageField(s string) string {
+	return strings.TrimSpace(textutils.ReplaceControlCharacters(s, " "))
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+)
+
+// TestMessagesReport asserts that messages are listed in the requested order
+// and format and that message details are sanitized.
+func TestMessagesReport(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{
+		{
+			MailboxName: "INBOX",
+			ItemsFound:  3,
+			Messages: []mbxs.Message{
+				{InternalDate: base, From: "alerts@example.com", OriginalSubject: "Ticket #42: Disk space low & rising"},
+				{InternalDate: base.Add(2 * time.Hour), From: "Ops <ops@example.com>", OriginalSubject: "Backup failed\r\n on host1"},
+				{InternalDate: base.Add(time.Hour), From: "", OriginalSubject: "a | b <script>"},
+			},
+		},
+		{
+			MailboxName: "Junk",
+		},
+	}
+
+	tests := map[string]struct {
+		opts    MessageListOptions
+		want    []string
+		notWant []string
+	}{
+		"disabled": {
+			opts:    MessageListOptions{},
+			notWant: []string{"Messages in"},
+		},
+		"newest first": {
+			opts: MessageListOptions{Limit: 2},
+			want: []string{
+				"Messages in INBOX (2 of 3):",
+				"* 2025-03-10 13:00 UTC, Ops <ops@example.com>: Backup failed   on host1",
+				"* 2025-03-10 12:00 UTC, unknown sender: a ¦ b <script>",
+			},
+			notWant: []string{"Disk space low", "Junk", "\r", "a | b"},
+		},
+		"oldest first": {
+			opts: MessageListOptions{Limit: 1, OldestFirst: true},
+			want: []string{
+				"Messages in INBOX (1 of 3):",
+				"* 2025-03-10 11:00 UTC, alerts@example.com: Ticket #42: Disk space low & rising",
+			},
+			notWant: []string{"Backup failed", "&#35;", "&amp;"},
+		},
+		"html": {
+			opts: MessageListOptions{Limit: 3, HTML: true},
+			want: []string{
+				"<p><b>Messages in INBOX (3 of 3):</b></p><table>",
+				"<td>Ops &lt;ops@example.com&gt;</td>",
+				"<td>a &#124; b &lt;script&gt;</td>",
+				"<td>Ticket #42: Disk space low &amp; rising</td>",
+			},
+			notWant: []string{"<script>", "&#35;", "a | b"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			report := MessagesReport(results, tt.opts)
+
+			for _, want := range tt.want {
+				if !strings.Contains(report, want) {
+					t.Errorf("want report to contain %q, got:\n%s", want, report)
+				}
+			}
+
+			for _, notWant := range tt.notWant {
+				if strings.Contains(report, notWant) {
+					t.Errorf("want report without %q, got:\n%s", notWant, report)
+				}
+			}
+		})
+	}
+}
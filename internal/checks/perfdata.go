@@ -0,0 +1,130 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// MailPerfData returns performance data for the number of messages, unseen
+// messages (among those counted) and (if includeAges is set) the oldest
+// message age for each checked folder along with the total number of
+// messages and unseen messages. Message count thresholds from the given
+// evaluation and the given message age thresholds are attached. Labels are
+// prefixed with the given prefix (e.g., an account name) if specified.
+// Folders which could not be checked are skipped.
+func MailPerfData(
+	prefix string,
+	results mbxs.MailboxCheckResults,
+	evaluation ThresholdEvaluation,
+	includeAges bool,
+	ageWarning time.Duration,
+	ageCritical time.Duration,
+	now time.Time,
+) []nagios.PerformanceData {
+
+	folderThresholds := make(map[string]ThresholdResult, len(evaluation.Folders))
+	for _, folder := range evaluation.Folders {
+		folderThresholds[folder.Label] = folder
+	}
+
+	perfData := make([]nagios.PerformanceData, 0, len(results)*3+2)
+
+	var unseen int
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		unseen += result.UnseenFound
+		thresholds := folderThresholds[result.MailboxName]
+
+		perfData = append(perfData,
+			nagios.PerformanceData{
+				Label: perfDataLabel(prefix, result.MailboxName, "messages"),
+				Value: strconv.Itoa(result.ItemsFound),
+				Warn:  thresholds.Warning,
+				Crit:  thresholds.Critical,
+				Min:   "0",
+			},
+			nagios.PerformanceData{
+				Label: perfDataLabel(prefix, result.MailboxName, "unseen"),
+				Value: strconv.Itoa(result.UnseenFound),
+				Min:   "0",
+			},
+		)
+
+		if includeAges {
+			perfData = append(perfData, nagios.PerformanceData{
+				Label:             perfDataLabel(prefix, result.MailboxName, "oldest_message_age"),
+				Value:             durationSeconds(result.OldestMessageAge(now)),
+				UnitOfMeasurement: "s",
+				Warn:              thresholdSeconds(ageWarning),
+				Crit:              thresholdSeconds(ageCritical),
+				Min:               "0",
+			})
+		}
+	}
+
+	perfData = append(perfData,
+		nagios.PerformanceData{
+			Label: perfDataLabel(prefix, "total_messages"),
+			Value: strconv.Itoa(evaluation.Total.Count),
+			Warn:  evaluation.Total.Warning,
+			Crit:  evaluation.Total.Critical,
+			Min:   "0",
+		},
+		nagios.PerformanceData{
+			Label: perfDataLabel(prefix, "total_unseen"),
+			Value: strconv.Itoa(unseen),
+			Min:   "0",
+		},
+	)
+
+	return perfData
+}
+
+// perfDataLabelReplacedCharacters are the characters which cannot appear in
+// a (single quoted) performance data label or which would end the
+// performance data section of the plugin output.
+const perfDataLabelReplacedCharacters string = `='|`
+
+// perfDataLabel returns the given non-empty words joined by underscores as a
+// performance data label. Labels are emitted single quoted, so folder and
+// account names are kept as-is (including case, spaces and punctuation) in
+// order for the labels of different folders to remain distinct. Only
+// characters which cannot appear in a label (equals signs, single quotes,
+// pipes and control characters) are replaced by their Unicode code point
+// (e.g., "u003d").
+func perfDataLabel(words ...string) string {
+	nonEmpty := make([]string, 0, len(words))
+	for _, word := range words {
+		if word != "" {
+			nonEmpty = append(nonEmpty, word)
+		}
+	}
+
+	var label strings.Builder
+	for _, r := range strings.Join(nonEmpty, "_") {
+		switch {
+		case strings.ContainsRune(perfDataLabelReplacedCharacters, r), unicode.IsControl(r):
+			fmt.Fprintf(&label, "u%04x", r)
+		default:
+			label.WriteRune(r)
+		}
+	}
+
+	return label.String()
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestPerfDataLabel asserts that folder names are converted to valid
+// performance data labels.
+func TestPerfDataLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		words []string
+		want  string
+	}{
+		"simple":         {words: []string{"INBOX", "messages"}, want: "INBOX_messages"},
+		"spaces":         {words: []string{"Sent Items", "unseen"}, want: "Sent Items_unseen"},
+		"quotes":         {words: []string{`Bob's "Stuff"`, "messages"}, want: `Bobu0027s "Stuff"_messages`},
+		"equals sign":    {words: []string{"a=b"}, want: "au003db"},
+		"pipe":           {words: []string{"a|b"}, want: "au007cb"},
+		"control":        {words: []string{"a\tb"}, want: "au0009b"},
+		"hierarchy":      {words: []string{"INBOX/Projects/2025"}, want: "INBOX/Projects/2025"},
+		"special-use":    {words: []string{`\Junk`}, want: `\Junk`},
+		"non-ASCII":      {words: []string{"Entwürfe"}, want: "Entwürfe"},
+		"empty prefix":   {words: []string{"", "total_messages"}, want: "total_messages"},
+		"account prefix": {words: []string{"user@example.com", "INBOX", "messages"}, want: "user@example.com_INBOX_messages"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := perfDataLabel(tt.words...)
+			if got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+
+			pd := nagios.PerformanceData{Label: got, Value: "0"}
+			if err := pd.Validate(); err != nil {
+				t.Errorf("label %q fails validation: %v", got, err)
+			}
+		})
+	}
+}
+
+// TestPerfDataLabelDistinct asserts that folder names which differ only in
+// case or punctuation are given distinct performance data labels.
+func TestPerfDataLabelDistinct(t *testing.T) {
+	t.Parallel()
+
+	folders := []string{"Archive", "archive", "ARCHIVE", "Foo Bar", "Foo/Bar", "Foo.Bar", "Foo_Bar", "a=b", "a'b", "a|b"}
+
+	seen := make(map[string]string, len(folders))
+	for _, folder := range folders {
+		label := perfDataLabel("user@example.com", folder, "messages")
+		if previous, ok := seen[label]; ok {
+			t.Errorf("folders %q and %q share label %q", previous, folder, label)
+		}
+		seen[label] = folder
+	}
+
+	results := make(mbxs.MailboxCheckResults, 0, len(folders))
+	for _, folder := range folders {
+		results = append(results, mbxs.MailboxCheckResult{MailboxName: folder, ItemsFound: 1})
+	}
+
+	perfData := MailPerfData("", results, ThresholdEvaluation{}, false, 0, 0, time.Now())
+
+	labels := make(map[string]bool, len(perfData))
+	for _, pd := range perfData {
+		if labels[pd.Label] {
+			t.Errorf("duplicate performance data label %q", pd.Label)
+		}
+		labels[pd.Label] = true
+	}
+}
+
+// TestMailPerfData asserts that per-folder and total metrics are emitted with
+// thresholds attached and that folders which could not be checked are
+// skipped.
+func TestMailPerfData(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{
+		{
+			MailboxName:       "INBOX",
+			ItemsFound:        12,
+			UnseenFound:       4,
+			OldestMessageDate: now.Add(-2 * time.Hour),
+		},
+		{
+			MailboxName: "Junk E-mail",
+			ItemsFound:  3,
+			UnseenFound: 3,
+		},
+		{
+			MailboxName: "Archive",
+			Err:         mbxs.ErrProtocol,
+		},
+	}
+
+	evaluation := ThresholdEvaluation{
+		Total: ThresholdResult{Label: "total", Count: 15, Warning: "10", Critical: "20"},
+		Folders: []ThresholdResult{
+			{Label: "INBOX", Count: 12, Warning: "5", Critical: "25"},
+		},
+	}
+
+	got := MailPerfData("", results, evaluation, true, time.Hour, 0, now)
+
+	want := []nagios.PerformanceData{
+		{Label: "INBOX_messages", Value: "12", Warn: "5", Crit: "25", Min: "0"},
+		{Label: "INBOX_unseen", Value: "4", Min: "0"},
+		{Label: "INBOX_oldest_message_age", Value: "7200", UnitOfMeasurement: "s", Warn: "3600", Min: "0"},
+		{Label: "Junk E-mail_messages", Value: "3", Min: "0"},
+		{Label: "Junk E-mail_unseen", Value: "3", Min: "0"},
+		{Label: "Junk E-mail_oldest_message_age", Value: "0", UnitOfMeasurement: "s", Warn: "3600", Min: "0"},
+		{Label: "total_messages", Value: "15", Warn: "10", Crit: "20", Min: "0"},
+		{Label: "total_unseen", Value: "7", Min: "0"},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	for _, pd := range got {
+		if err := pd.Validate(); err != nil {
+			t.Errorf("performance data %q fails validation: %v", pd.Label, err)
+		}
+	}
+
+	withoutAges := MailPerfData("user", results, evaluation, false, time.Hour, 0, now)
+	if len(withoutAges) != 6 {
+		t.Errorf("want 6 metrics without message ages, got %d", len(withoutAges))
+	}
+
+	if withoutAges[0].Label != "user_INBOX_messages" {
+		t.Errorf("want prefixed label, got %q", withoutAges[0].Label)
+	}
+}
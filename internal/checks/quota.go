@@ -0,0 +1,242 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// QuotaResult is the result of evaluating the usage of a quota resource
+// against warning and critical percentage thresholds.
+type QuotaResult struct {
+	// Root is the name of the quota root. This is often an empty string.
+	Root string
+
+	// Resource is the evaluated quota resource.
+	Resource mbxs.QuotaResource
+
+	// Warning is the percentage of the limit above which a WARNING
+	// state is indicated. A zero value disables this threshold.
+	Warning int
+
+	// Critical is the percentage of the limit above which a CRITICAL
+	// state is indicated. A zero value disables this threshold.
+	Critical int
+
+	// ExitStatusCode is the Nagios state resulting from the evaluation.
+	ExitStatusCode int
+}
+
+// QuotaEvaluation is the collection of quota evaluation results for all
+// limited resources of all quota roots.
+type QuotaEvaluation []QuotaResult
+
+// EvaluateQuotas evaluates the usage of each limited quota resource against
+// the given warning and critical percentage thresholds. As with the Nagios
+// ranges emitted as performance data, a threshold is only exceeded by usage
+// above (not at) the threshold. Resources without a limit are skipped.
+func EvaluateQuotas(quotas []mbxs.Quota, warning int, critical int) QuotaEvaluation {
+	var evaluation QuotaEvaluation
+
+	for _, quota := range quotas {
+		for _, resource := range quota.Resources {
+			if resource.Limit == 0 {
+				continue
+			}
+
+			result := QuotaResult{
+				Root:           quota.Root,
+				Resource:       resource,
+				Warning:        warning,
+				Critical:       critical,
+				ExitStatusCode: nagios.StateOKExitCode,
+			}
+
+			used := resource.PercentUsed()
+			switch {
+			case critical > 0 && used > float64(critical):
+				result.ExitStatusCode = nagios.StateCRITICALExitCode
+			case warning > 0 && used > float64(warning):
+				result.ExitStatusCode = nagios.StateWARNINGExitCode
+			}
+
+			evaluation = append(evaluation, result)
+		}
+	}
+
+	return evaluation
+}
+
+// ExitStatusCode returns the most severe Nagios state from all evaluated
+// quota resources.
+func (qe QuotaEvaluation) ExitStatusCode() int {
+	state := nagios.StateOKExitCode
+	for _, result := range qe {
+		if result.ExitStatusCode > state {
+			state = result.ExitStatusCode
+		}
+	}
+
+	return state
+}
+
+// Summary returns a brief summary of quota usage suitable for use in
+// ServiceOutput.
+func (qe QuotaEvaluation) Summary() string {
+	if len(qe) == 0 {
+		return "no quota limits set"
+	}
+
+	summaries := make([]string, 0, len(qe))
+	for _, result := range qe {
+		summaries = append(summaries, fmt.Sprintf(
+			"%s %.1f%% used",
+			result.label(),
+			result.Resource.PercentUsed(),
+		))
+	}
+
+	return strings.Join(summaries, ", ")
+}
+
+// Report returns a summary of the quota evaluation results suitable for use
+// as LongServiceOutput.
+func (qe QuotaEvaluation) Report() string {
+	var report strings.Builder
+
+	fmt.Fprintf(&report, "Quota evaluation:%s%s", nagios.CheckOutputEOL, nagios.CheckOutputEOL)
+
+	if len(qe) == 0 {
+		fmt.Fprintf(&report, "* no quota limits set%s", nagios.CheckOutputEOL)
+	}
+
+	for _, result := range qe {
+		fmt.Fprintf(
+			&report,
+			"* %s: %s of %s (%.1f%%) (warning: %s, critical: %s): %s%s",
+			result.label(),
+			formatQuotaValue(result.Resource.Name, result.Resource.Usage),
+			formatQuotaValue(result.Resource.Name, result.Resource.Limit),
+			result.Resource.PercentUsed(),
+			displayPercentThreshold(result.Warning),
+			displayPercentThreshold(result.Critical),
+			nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	return report.String()
+}
+
+// QuotaPerfData returns performance data for the usage of each evaluated
+// quota resource. The warning and critical thresholds are converted from
+// percentages of the limit to resource units. Labels are prefixed with the
+// given prefix (e.g., an account name) if specified.
+func QuotaPerfData(prefix string, qe QuotaEvaluation) []nagios.PerformanceData {
+	perfData := make([]nagios.PerformanceData, 0, len(qe)*2)
+
+	for _, result := range qe {
+		label := perfDataLabel(prefix, result.perfDataLabel())
+
+		var uom string
+		if strings.EqualFold(result.Resource.Name, mbxs.QuotaResourceStorage) {
+			// STORAGE is measured in units of 1024 octets.
+			uom = "KB"
+		}
+
+		perfData = append(perfData,
+			nagios.PerformanceData{
+				Label:             label,
+				Value:             strconv.FormatUint(result.Resource.Usage, 10),
+				UnitOfMeasurement: uom,
+				Warn:              percentOfLimit(result.Warning, result.Resource.Limit),
+				Crit:              percentOfLimit(result.Critical, result.Resource.Limit),
+				Min:               "0",
+				Max:               strconv.FormatUint(result.Resource.Limit, 10),
+			},
+			nagios.PerformanceData{
+				Label:             label + "_percent",
+				Value:             strconv.FormatFloat(result.Resource.PercentUsed(), 'f', 1, 64),
+				UnitOfMeasurement: "%",
+				Warn:              percentThreshold(result.Warning),
+				Crit:              percentThreshold(result.Critical),
+				Min:               "0",
+				Max:               "100",
+			},
+		)
+	}
+
+	return perfData
+}
+
+// label returns the quota resource name, prefixed with the quota root name
+// if set.
+func (qr QuotaResult) label() string {
+	if qr.Root == "" {
+		return qr.Resource.Name
+	}
+
+	return qr.Root + " " + qr.Resource.Name
+}
+
+// perfDataLabel returns a performance data label for the quota resource.
+// Resource names are case-insensitive and reported in lowercase; quota root
+// names are kept as-is.
+func (qr QuotaResult) perfDataLabel() string {
+	return perfDataLabel("quota", strings.ToLower(qr.Resource.Name), qr.Root)
+}
+
+// formatQuotaValue returns a quota resource value for display. STORAGE
+// values (in units of 1024 octets) are formatted as MiB or GiB.
+func formatQuotaValue(resource string, value uint64) string {
+	if !strings.EqualFold(resource, mbxs.QuotaResourceStorage) {
+		return strconv.FormatUint(value, 10)
+	}
+
+	const kibPerGiB = 1 << 20
+	if value >= kibPerGiB {
+		return fmt.Sprintf("%.1f GiB", float64(value)/kibPerGiB)
+	}
+
+	return fmt.Sprintf("%.1f MiB", float64(value)/(1<<10))
+}
+
+// percentOfLimit returns the given percentage threshold as a value in
+// resource units or an empty string if the threshold is not set.
+func percentOfLimit(percent int, limit uint64) string {
+	if percent <= 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(limit*uint64(percent)/100, 10)
+}
+
+// percentThreshold returns the given percentage threshold or an empty string
+// if the threshold is not set.
+func percentThreshold(percent int) string {
+	if percent <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(percent)
+}
+
+// displayPercentThreshold returns the given percentage threshold or a
+// placeholder value if not set.
+func displayPercentThreshold(percent int) string {
+	if percent <= 0 {
+		return thresholdNotSet
+	}
+
+	return strconv.Itoa(percent) + "%"
+}
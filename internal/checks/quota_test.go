@@ -0,0 +1,208 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestEvaluateQuotas asserts that the usage of each limited quota resource is
+// evaluated against percentage thresholds and that unlimited resources are
+// skipped.
+func TestEvaluateQuotas(t *testing.T) {
+	t.Parallel()
+
+	quotas := []mbxs.Quota{
+		{
+			Root: "User quota",
+			Resources: []mbxs.QuotaResource{
+				{Name: mbxs.QuotaResourceStorage, Usage: 870400, Limit: 1048576},
+				{Name: mbxs.QuotaResourceMessage, Usage: 1200, Limit: 10000},
+			},
+		},
+		{
+			Root: "",
+			Resources: []mbxs.QuotaResource{
+				{Name: mbxs.QuotaResourceStorage, Usage: 990, Limit: 1000},
+				{Name: mbxs.QuotaResourceMessage, Usage: 50, Limit: 0},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		warning  int
+		critical int
+		want     []int
+	}{
+		"warning and critical": {
+			warning:  80,
+			critical: 90,
+			want: []int{
+				nagios.StateWARNINGExitCode,
+				nagios.StateOKExitCode,
+				nagios.StateCRITICALExitCode,
+			},
+		},
+		"thresholds disabled": {
+			want: []int{
+				nagios.StateOKExitCode,
+				nagios.StateOKExitCode,
+				nagios.StateOKExitCode,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluation := EvaluateQuotas(quotas, tt.warning, tt.critical)
+			if len(evaluation) != len(tt.want) {
+				t.Fatalf("want %d results, got %d", len(tt.want), len(evaluation))
+			}
+
+			for i, want := range tt.want {
+				if got := evaluation[i].ExitStatusCode; got != want {
+					t.Errorf(
+						"%s: want state %d, got %d",
+						evaluation[i].label(),
+						want,
+						got,
+					)
+				}
+			}
+		})
+	}
+
+	evaluation := EvaluateQuotas(quotas, 80, 90)
+	if got := evaluation.ExitStatusCode(); got != nagios.StateCRITICALExitCode {
+		t.Errorf("want overall state %d, got %d", nagios.StateCRITICALExitCode, got)
+	}
+
+	if want := "User quota STORAGE 83.0% used"; !strings.HasPrefix(evaluation.Summary(), want) {
+		t.Errorf("want summary prefix %q, got %q", want, evaluation.Summary())
+	}
+}
+
+// TestEvaluateQuotasThresholdBoundary asserts that usage exactly at a
+// threshold does not exceed it and that the state matches the evaluation of
+// the performance data ranges.
+func TestEvaluateQuotasThresholdBoundary(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		usage uint64
+		want  int
+	}{
+		"below warning":  {usage: 799, want: nagios.StateOKExitCode},
+		"at warning":     {usage: 800, want: nagios.StateOKExitCode},
+		"above warning":  {usage: 801, want: nagios.StateWARNINGExitCode},
+		"at critical":    {usage: 900, want: nagios.StateWARNINGExitCode},
+		"above critical": {usage: 901, want: nagios.StateCRITICALExitCode},
+		"at limit":       {usage: 1000, want: nagios.StateCRITICALExitCode},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluation := EvaluateQuotas([]mbxs.Quota{
+				{
+					Root: "User quota",
+					Resources: []mbxs.QuotaResource{
+						{Name: mbxs.QuotaResourceMessage, Usage: tt.usage, Limit: 1000},
+					},
+				},
+			}, 80, 90)
+
+			if got := evaluation.ExitStatusCode(); got != tt.want {
+				t.Errorf("want state %d, got %d", tt.want, got)
+			}
+
+			for _, pd := range QuotaPerfData("", evaluation) {
+				rangeState := nagios.StateOKExitCode
+				switch {
+				case nagios.ParseRangeString(pd.Crit).CheckRange(pd.Value):
+					rangeState = nagios.StateCRITICALExitCode
+				case nagios.ParseRangeString(pd.Warn).CheckRange(pd.Value):
+					rangeState = nagios.StateWARNINGExitCode
+				}
+
+				if rangeState != tt.want {
+					t.Errorf(
+						"%s: want range state %d matching evaluation, got %d (value: %s, warning: %s, critical: %s)",
+						pd.Label, tt.want, rangeState, pd.Value, pd.Warn, pd.Crit,
+					)
+				}
+			}
+		})
+	}
+}
+
+// TestQuotaPerfData asserts that quota usage performance data uses resource
+// units with thresholds converted from percentages of the limit.
+func TestQuotaPerfData(t *testing.T) {
+	t.Parallel()
+
+	evaluation := EvaluateQuotas([]mbxs.Quota{
+		{
+			Root: "User quota",
+			Resources: []mbxs.QuotaResource{
+				{Name: mbxs.QuotaResourceStorage, Usage: 512000, Limit: 1024000},
+			},
+		},
+	}, 80, 90)
+
+	perfData := QuotaPerfData("", evaluation)
+	if len(perfData) != 2 {
+		t.Fatalf("want 2 perfdata entries, got %d", len(perfData))
+	}
+
+	usage, percent := perfData[0], perfData[1]
+
+	if usage.Label != "quota_storage_User quota" ||
+		usage.Value != "512000" ||
+		usage.UnitOfMeasurement != "KB" ||
+		usage.Warn != "819200" ||
+		usage.Crit != "921600" ||
+		usage.Max != "1024000" {
+		t.Errorf("unexpected usage perfdata: %+v", usage)
+	}
+
+	if percent.Label != "quota_storage_User quota_percent" ||
+		percent.Value != "50.0" ||
+		percent.Warn != "80" ||
+		percent.Crit != "90" {
+		t.Errorf("unexpected percent perfdata: %+v", percent)
+	}
+
+	for _, pd := range perfData {
+		if err := pd.Validate(); err != nil {
+			t.Errorf("invalid perfdata %q: %v", pd.Label, err)
+		}
+	}
+}
+
+// TestQuotaEvaluationNoLimits asserts that quota roots without limits are
+// reported as such.
+func TestQuotaEvaluationNoLimits(t *testing.T) {
+	t.Parallel()
+
+	evaluation := EvaluateQuotas(nil, 80, 90)
+	if got := evaluation.ExitStatusCode(); got != nagios.StateOKExitCode {
+		t.Errorf("want state %d, got %d", nagios.StateOKExitCode, got)
+	}
+
+	if got := evaluation.Summary(); got != "no quota limits set" {
+		t.Errorf("unexpected summary %q", got)
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// RateResult is the result of evaluating the rates (in messages per hour) at
+// which messages arrived in, were removed from (drained) and accumulated in
+// (growth) a folder over a time window against rate thresholds.
+type RateResult struct {
+	// Label is the name of the evaluated folder.
+	Label string
+
+	// Window is the evaluated time window.
+	Window time.Duration
+
+	// Elapsed is the time between the check history sample used as the
+	// start of the window and the current check. This is at least as long
+	// as the window.
+	Elapsed time.Duration
+
+	// Samples is the number of check history samples recorded for the
+	// folder.
+	Samples int
+
+	// Pending indicates that the recorded check history does not yet cover
+	// the window and that rates were not evaluated.
+	Pending bool
+
+	// Arrived is the number of messages which arrived in the folder. This is
+	// an upper bound; see EvaluateRates.
+	Arrived int
+
+	// Drained is the number of messages removed from the folder. This is an
+	// upper bound; see EvaluateRates.
+	Drained int
+
+	// Growth is the net change in the number of messages in the folder.
+	Growth int
+
+	// ArrivalRate is the number of messages which arrived per hour.
+	ArrivalRate float64
+
+	// DrainRate is the number of messages removed per hour.
+	DrainRate float64
+
+	// GrowthRate is the net change in the number of messages per hour.
+	GrowthRate float64
+
+	// Thresholds is the collection of Nagios ranges evaluated against the
+	// rates.
+	Thresholds config.RateThresholds
+
+	// ExitStatusCode is the Nagios state resulting from the evaluation.
+	ExitStatusCode int
+}
+
+// EvaluateRates evaluates the message arrival, drain and growth rates for
+// each checked folder over each of the given time windows using the given
+// check history (oldest sample first, including the current check) against
+// the given rate thresholds. Arrivals are determined from the highest UID
+// assigned in the folder and removals from the change in the number of
+// messages. UIDs are not necessarily assigned without gaps (e.g., a message
+// delivered and then moved before the next check still uses a UID), so
+// arrivals and removals are upper bounds; growth is exact. Folders which
+// could not be checked are skipped and rates are not evaluated for windows
+// not yet covered by the check history.
+func EvaluateRates(
+	results mbxs.MailboxCheckResults,
+	history map[string][]mbxs.MailboxSample,
+	windows []time.Duration,
+	thresholds config.RateThresholds,
+) ([]RateResult, error) {
+
+	rateResults := make([]RateResult, 0, len(results)*len(windows))
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		samples := history[result.MailboxName]
+		if len(samples) == 0 {
+			continue
+		}
+
+		for _, window := range windows {
+			rateResult, err := evaluateRate(result.MailboxName, samples, window, thresholds)
+			if err != nil {
+				return nil, err
+			}
+
+			rateResults = append(rateResults, rateResult)
+		}
+	}
+
+	return rateResults, nil
+}
+
+// evaluateRate evaluates the message rates for a single folder over the
+// given time window.
+func evaluateRate(
+	label string,
+	samples []mbxs.MailboxSample,
+	window time.Duration,
+	thresholds config.RateThresholds,
+) (RateResult, error) {
+
+	result := RateResult{
+		Label:          label,
+		Window:         window,
+		Samples:        len(samples),
+		Thresholds:     thresholds,
+		ExitStatusCode: nagios.StateOKExitCode,
+	}
+
+	current := samples[len(samples)-1]
+
+	// Use the newest sample recorded at least one window before the
+	// current check so that the full window is covered.
+	base := -1
+	for i := len(samples) - 2; i >= 0; i-- {
+		if samples[i].UIDValidity != current.UIDValidity {
+			break
+		}
+
+		if !samples[i].Time.After(current.Time.Add(-window)) {
+			base = i
+			break
+		}
+	}
+
+	if base < 0 {
+		result.Pending = true
+		return result, nil
+	}
+
+	start := samples[base]
+	result.Elapsed = current.Time.Sub(start.Time)
+
+	if current.HighestUID > start.HighestUID {
+		result.Arrived = int(current.HighestUID - start.HighestUID)
+	}
+	result.Growth = current.Messages - start.Messages
+	result.Drained = max(0, result.Arrived-result.Growth)
+
+	hours := result.Elapsed.Hours()
+	result.ArrivalRate = float64(result.Arrived) / hours
+	result.DrainRate = float64(result.Drained) / hours
+	result.GrowthRate = float64(result.Growth) / hours
+
+	rateChecks := []struct {
+		rate     float64
+		warning  string
+		critical string
+	}{
+		{rate: result.ArrivalRate, warning: thresholds.ArrivalWarning, critical: thresholds.ArrivalCritical},
+		{rate: result.DrainRate, warning: thresholds.DrainWarning, critical: thresholds.DrainCritical},
+		{rate: result.GrowthRate, warning: thresholds.GrowthWarning, critical: thresholds.GrowthCritical},
+	}
+
+	for _, check := range rateChecks {
+		value := formatRate(check.rate)
+
+		inCritical, err := thresholdExceeded(check.critical, value)
+		if err != nil {
+			return RateResult{}, err
+		}
+
+		inWarning, err := thresholdExceeded(check.warning, value)
+		if err != nil {
+			return RateResult{}, err
+		}
+
+		switch {
+		case inCritical:
+			result.ExitStatusCode = nagios.StateCRITICALExitCode
+		case inWarning && result.ExitStatusCode == nagios.StateOKExitCode:
+			result.ExitStatusCode = nagios.StateWARNINGExitCode
+		}
+	}
+
+	return result, nil
+}
+
+// RatesSummary returns a brief summary of the folders with message rates
+// outside of the rate thresholds suitable for use in ServiceOutput. An empty
+// string is returned if all rates are within thresholds.
+func RatesSummary(rates []RateResult) string {
+	var summaries []string
+	for _, result := range rates {
+		if result.ExitStatusCode == nagios.StateOKExitCode {
+			continue
+		}
+
+		summaries = append(summaries, fmt.Sprintf(
+			"%s: %s arrived/h, %s drained/h over %s",
+			result.Label,
+			formatRate(result.ArrivalRate),
+			formatRate(result.DrainRate),
+			windowLabel(result.Window),
+		))
+	}
+
+	if len(summaries) == 0 {
+		return ""
+	}
+
+	return "message rates: " + strings.Join(summaries, "; ")
+}
+
+// RatePerfData returns performance data for the message arrival, drain and
+// growth rates (in messages per hour) for each evaluated folder and time
+// window along with the rate thresholds. Labels are prefixed with the given
+// prefix (e.g., an account name) if specified. Windows not yet covered by
+// the check history are skipped.
+func RatePerfData(prefix string, rates []RateResult) []nagios.PerformanceData {
+	perfData := make([]nagios.PerformanceData, 0, len(rates)*3)
+	for _, result := range rates {
+		if result.Pending {
+			continue
+		}
+
+		window := windowLabel(result.Window)
+
+		perfData = append(perfData,
+			nagios.PerformanceData{
+				Label: perfDataLabel(prefix, result.Label, "arrival_rate", window),
+				Value: formatRate(result.ArrivalRate),
+				Warn:  result.Thresholds.ArrivalWarning,
+				Crit:  result.Thresholds.ArrivalCritical,
+				Min:   "0",
+			},
+			nagios.PerformanceData{
+				Label: perfDataLabel(prefix, result.Label, "drain_rate", window),
+				Value: formatRate(result.DrainRate),
+				Warn:  result.Thresholds.DrainWarning,
+				Crit:  result.Thresholds.DrainCritical,
+				Min:   "0",
+			},
+			nagios.PerformanceData{
+				Label: perfDataLabel(prefix, result.Label, "growth_rate", window),
+				Value: formatRate(result.GrowthRate),
+				Warn:  result.Thresholds.GrowthWarning,
+				Crit:  result.Thresholds.GrowthCritical,
+			},
+		)
+	}
+
+	return perfData
+}
+
+// rateReportLine returns a single LongServiceOutput line summarizing a rate
+// evaluation result.
+func rateReportLine(result RateResult) string {
+	if result.Pending {
+		return fmt.Sprintf(
+			"* %s over %s: rates pending (%d checks recorded; history does not yet cover window): %s%s",
+			result.Label,
+			windowLabel(result.Window),
+			result.Samples,
+			nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	return fmt.Sprintf(
+		"* %s over %s: %d arrived (%s/h, warning: %s, critical: %s), "+
+			"%d drained (%s/h, warning: %s, critical: %s), "+
+			"%+d growth (%s/h, warning: %s, critical: %s): %s%s",
+		result.Label,
+		windowLabel(result.Window),
+		result.Arrived,
+		formatRate(result.ArrivalRate),
+		displayThreshold(result.Thresholds.ArrivalWarning),
+		displayThreshold(result.Thresholds.ArrivalCritical),
+		result.Drained,
+		formatRate(result.DrainRate),
+		displayThreshold(result.Thresholds.DrainWarning),
+		displayThreshold(result.Thresholds.DrainCritical),
+		result.Growth,
+		formatRate(result.GrowthRate),
+		displayThreshold(result.Thresholds.GrowthWarning),
+		displayThreshold(result.Thresholds.GrowthCritical),
+		nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+		nagios.CheckOutputEOL,
+	)
+}
+
+// formatRate returns the given rate with two decimal places.
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 2, 64)
+}
+
+// windowLabel returns a compact representation of the given time window
+// (e.g., "1h" or "90m") suitable for use in performance data labels.
+func windowLabel(window time.Duration) string {
+	switch {
+	case window%time.Hour == 0:
+		return fmt.Sprintf("%dh", window/time.Hour)
+	case window%time.Minute == 0:
+		return fmt.Sprintf("%dm", window/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", window/time.Second)
+	}
+}
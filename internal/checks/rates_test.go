@@ -0,0 +1,174 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestEvaluateRates asserts that message arrival, drain and growth rates are
+// determined from the check history over each window and evaluated against
+// the rate thresholds.
+func TestEvaluateRates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX"},
+		{MailboxName: "Queue"},
+		{MailboxName: "Broken", Err: errors.New("error occurred examining mailbox")},
+	}
+
+	history := map[string][]mbxs.MailboxSample{
+		"INBOX": {
+			{Time: now.Add(-10 * time.Minute), Messages: 10, UIDValidity: 7, HighestUID: 100},
+			{Time: now, Messages: 11, UIDValidity: 7, HighestUID: 101},
+		},
+		"Queue": {
+			{Time: now.Add(-65 * time.Minute), Messages: 20, UIDValidity: 3, HighestUID: 400},
+			{Time: now.Add(-30 * time.Minute), Messages: 40, UIDValidity: 3, HighestUID: 430},
+			{Time: now, Messages: 65, UIDValidity: 3, HighestUID: 465},
+		},
+		"Broken": {
+			{Time: now.Add(-2 * time.Hour), Messages: 1, UIDValidity: 1, HighestUID: 1},
+			{Time: now, Messages: 1, UIDValidity: 1, HighestUID: 1},
+		},
+	}
+
+	thresholds := config.RateThresholds{
+		ArrivalWarning:  "50",
+		ArrivalCritical: "100",
+		GrowthWarning:   "30",
+	}
+
+	rates, err := EvaluateRates(results, history, []time.Duration{time.Hour}, thresholds)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rates) != 2 {
+		t.Fatalf("want 2 rate results, got %d", len(rates))
+	}
+
+	inbox := rates[0]
+	if !inbox.Pending || inbox.ExitStatusCode != nagios.StateOKExitCode {
+		t.Errorf("INBOX: want pending OK result, got %+v", inbox)
+	}
+
+	queue := rates[1]
+	if queue.Pending {
+		t.Fatalf("Queue: want evaluated result, got pending")
+	}
+
+	if want, got := 65*time.Minute, queue.Elapsed; want != got {
+		t.Errorf("Queue: want elapsed %v, got %v", want, got)
+	}
+
+	if queue.Arrived != 65 || queue.Growth != 45 || queue.Drained != 20 {
+		t.Errorf(
+			"Queue: want 65 arrived, 45 growth, 20 drained; got %d arrived, %d growth, %d drained",
+			queue.Arrived, queue.Growth, queue.Drained,
+		)
+	}
+
+	if want, got := "60.00", formatRate(queue.ArrivalRate); want != got {
+		t.Errorf("Queue: want arrival rate %s, got %s", want, got)
+	}
+
+	if want, got := nagios.StateWARNINGExitCode, queue.ExitStatusCode; want != got {
+		t.Errorf("Queue: want state %d, got %d", want, got)
+	}
+
+	evaluation := ThresholdEvaluation{Rates: rates}
+	if want, got := nagios.StateWARNINGExitCode, evaluation.ExitStatusCode(); want != got {
+		t.Errorf("want overall state %d, got %d", want, got)
+	}
+
+	if want, got := "message rates: Queue: 60.00 arrived/h, 18.46 drained/h over 1h", RatesSummary(rates); want != got {
+		t.Errorf("want summary %q, got %q", want, got)
+	}
+
+	report := evaluation.Report()
+	for _, want := range []string{
+		"* INBOX over 1h: rates pending (2 checks recorded",
+		"* Queue over 1h: 65 arrived (60.00/h, warning: 50, critical: 100)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("want report to contain %q, got:\n%s", want, report)
+		}
+	}
+
+	perfData := RatePerfData("", rates)
+	if len(perfData) != 3 {
+		t.Fatalf("want 3 rate perfdata entries, got %d", len(perfData))
+	}
+
+	if want, got := "Queue_arrival_rate_1h", perfData[0].Label; want != got {
+		t.Errorf("want arrival rate label %q, got %q", want, got)
+	}
+	if want, got := "50", perfData[0].Warn; want != got {
+		t.Errorf("want arrival rate warning threshold %q, got %q", want, got)
+	}
+	if want, got := "Queue_growth_rate_1h", perfData[2].Label; want != got {
+		t.Errorf("want growth rate label %q, got %q", want, got)
+	}
+	if want, got := "41.54", perfData[2].Value; want != got {
+		t.Errorf("want growth rate %s, got %s", want, got)
+	}
+}
+
+// TestEvaluateRatesUIDValidityChanged asserts that check history recorded
+// for a previous UIDVALIDITY value is not used to evaluate rates.
+func TestEvaluateRatesUIDValidityChanged(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	results := mbxs.MailboxCheckResults{{MailboxName: "INBOX"}}
+	history := map[string][]mbxs.MailboxSample{
+		"INBOX": {
+			{Time: now.Add(-2 * time.Hour), Messages: 10, UIDValidity: 6, HighestUID: 900},
+			{Time: now, Messages: 11, UIDValidity: 7, HighestUID: 11},
+		},
+	}
+
+	rates, err := EvaluateRates(results, history, []time.Duration{time.Hour}, config.RateThresholds{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rates) != 1 || !rates[0].Pending {
+		t.Errorf("want single pending rate result, got %+v", rates)
+	}
+}
+
+// TestWindowLabel asserts that rate windows are labeled compactly.
+func TestWindowLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[time.Duration]string{
+		time.Hour:        "1h",
+		24 * time.Hour:   "24h",
+		90 * time.Minute: "90m",
+		45 * time.Second: "45s",
+	}
+
+	for window, want := range tests {
+		if got := windowLabel(window); got != want {
+			t.Errorf("want label %q for window %v, got %q", want, window, got)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// RetryPerfData returns performance data for the number of retries performed
+// after transient errors. The maximum is the total number of retries
+// permitted for all checked accounts.
+func RetryPerfData(retries int, maxRetries int) nagios.PerformanceData {
+	return nagios.PerformanceData{
+		Label: "retries",
+		Value: strconv.Itoa(retries),
+		Min:   "0",
+		Max:   strconv.Itoa(maxRetries),
+	}
+}
+
+// RetriesReport returns a LongServiceOutput section noting the number of
+// retries performed after transient errors.
+func RetriesReport(retries int) string {
+	return fmt.Sprintf(
+		"%sRetries after transient errors: %d%s",
+		nagios.CheckOutputEOL,
+		retries,
+		nagios.CheckOutputEOL,
+	)
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// thresholdNotSet is used in place of an empty threshold value when
+// reporting threshold evaluation results.
+const thresholdNotSet string = "none"
+
+// ThresholdResult is the result of evaluating a message count against
+// warning and critical threshold ranges.
+type ThresholdResult struct {
+	// Label is the name of the evaluated item; a folder name or "total".
+	Label string
+
+	// Count is the number of messages found.
+	Count int
+
+	// Warning is the Nagios range string used for the WARNING threshold.
+	Warning string
+
+	// Critical is the Nagios range string used for the CRITICAL threshold.
+	Critical string
+
+	// ExitStatusCode is the Nagios state resulting from the evaluation.
+	ExitStatusCode int
+}
+
+// ThresholdEvaluation is the collection of threshold evaluation results for
+// the total number of messages found and for any folders with specific
+// thresholds.
+type ThresholdEvaluation struct {
+	Total   ThresholdResult
+	Folders []ThresholdResult
+
+	// Ages is the collection of message age threshold evaluation results.
+	// This is empty unless message age thresholds are specified.
+	Ages []AgeThresholdResult
+
+	// Freshness is the collection of newest message age evaluation results.
+	// This is empty unless expect mail mode is used with newest message age
+	// thresholds.
+	Freshness []FreshnessResult
+
+	// FolderErrors is the collection of folders which were not found or
+	// could not be checked.
+	FolderErrors []FolderErrorResult
+
+	// Rates is the collection of message rate evaluation results. This is
+	// empty unless rate evaluation is enabled.
+	Rates []RateResult
+}
+
+// EvaluateThresholds evaluates the given mailbox check results against the
+// total message count thresholds and any per-folder thresholds. Folders
+// without specific thresholds are only considered as part of the total.
+// Folders which could not be checked are not evaluated against per-folder
+// thresholds.
+func EvaluateThresholds(
+	results mbxs.MailboxCheckResults,
+	warning string,
+	critical string,
+	folderThresholds config.FolderThresholds,
+) (ThresholdEvaluation, error) {
+
+	total, err := evaluateCount("total", results.TotalMessagesFound(), warning, critical)
+	if err != nil {
+		return ThresholdEvaluation{}, err
+	}
+
+	evaluation := ThresholdEvaluation{
+		Total: total,
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		ft, ok := folderThresholds.Lookup(result.MailboxName)
+		if !ok {
+			continue
+		}
+
+		folderResult, err := evaluateCount(
+			result.MailboxName,
+			result.ItemsFound,
+			ft.WarningRange(),
+			ft.CriticalRange(),
+		)
+		if err != nil {
+			return ThresholdEvaluation{}, err
+		}
+
+		evaluation.Folders = append(evaluation.Folders, folderResult)
+	}
+
+	return evaluation, nil
+}
+
+// ExitStatusCode returns the most severe Nagios state from all evaluated
+// thresholds.
+func (te ThresholdEvaluation) ExitStatusCode() int {
+	state := te.Total.ExitStatusCode
+	for _, folder := range te.Folders {
+		if folder.ExitStatusCode > state {
+			state = folder.ExitStatusCode
+		}
+	}
+
+	for _, age := range te.Ages {
+		if age.ExitStatusCode > state {
+			state = age.ExitStatusCode
+		}
+	}
+
+	for _, freshness := range te.Freshness {
+		if freshness.ExitStatusCode > state {
+			state = freshness.ExitStatusCode
+		}
+	}
+
+	for _, folderErr := range te.FolderErrors {
+		if folderErr.ExitStatusCode > state {
+			state = folderErr.ExitStatusCode
+		}
+	}
+
+	for _, rate := range te.Rates {
+		if rate.ExitStatusCode > state {
+			state = rate.ExitStatusCode
+		}
+	}
+
+	return state
+}
+
+// Report returns a summary of the threshold evaluation results suitable for
+// use as LongServiceOutput.
+func (te ThresholdEvaluation) Report() string {
+	var report strings.Builder
+
+	fmt.Fprintf(&report, "Threshold evaluation:%s%s", nagios.CheckOutputEOL, nagios.CheckOutputEOL)
+
+	for _, result := range append([]ThresholdResult{te.Total}, te.Folders...) {
+		fmt.Fprintf(
+			&report,
+			"* %s: %d messages (warning: %s, critical: %s): %s%s",
+			result.Label,
+			result.Count,
+			displayThreshold(result.Warning),
+			displayThreshold(result.Critical),
+			nagios.ExitCodeToStateLabel(result.ExitStatusCode),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	for _, age := range te.Ages {
+		report.WriteString(ageReportLine(age))
+	}
+
+	for _, freshness := range te.Freshness {
+		report.WriteString(freshnessReportLine(freshness))
+	}
+
+	for _, folderErr := range te.FolderErrors {
+		report.WriteString(folderErrorReportLine(folderErr))
+	}
+
+	for _, rate := range te.Rates {
+		report.WriteString(rateReportLine(rate))
+	}
+
+	return report.String()
+}
+
+// evaluateCount evaluates a single message count against the given warning
+// and critical Nagios range strings.
+func evaluateCount(label string, count int, warning string, critical string) (ThresholdResult, error) {
+	result := ThresholdResult{
+		Label:          label,
+		Count:          count,
+		Warning:        warning,
+		Critical:       critical,
+		ExitStatusCode: nagios.StateOKExitCode,
+	}
+
+	value := strconv.Itoa(count)
+
+	inCritical, err := thresholdExceeded(critical, value)
+	if err != nil {
+		return ThresholdResult{}, err
+	}
+
+	inWarning, err := thresholdExceeded(warning, value)
+	if err != nil {
+		return ThresholdResult{}, err
+	}
+
+	switch {
+	case inCritical:
+		result.ExitStatusCode = nagios.StateCRITICALExitCode
+	case inWarning:
+		result.ExitStatusCode = nagios.StateWARNINGExitCode
+	}
+
+	return result, nil
+}
+
+// thresholdExceeded indicates whether the given value falls outside of (or
+// inside of for inverted ranges) the given Nagios range. An empty range is
+// never exceeded.
+func thresholdExceeded(rangeStr string, value string) (bool, error) {
+	if rangeStr == "" {
+		return false, nil
+	}
+
+	r := nagios.ParseRangeString(rangeStr)
+	if r == nil {
+		return false, fmt.Errorf(
+			"failed to parse range string %q: %w",
+			rangeStr,
+			nagios.ErrInvalidRangeThreshold,
+		)
+	}
+
+	return r.CheckRange(value), nil
+}
+
+// displayThreshold returns the given range string or a placeholder value if
+// empty.
+func displayThreshold(rangeStr string) string {
+	if rangeStr == "" {
+		return thresholdNotSet
+	}
+
+	return rangeStr
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/check-mail/internal/config"
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestEvaluateThresholds asserts that the most severe state from the total
+// and per-folder thresholds is reported.
+func TestEvaluateThresholds(t *testing.T) {
+	t.Parallel()
+
+	folderThresholds := config.FolderThresholds{
+		{Folder: "INBOX", Warning: 5, Critical: 20},
+		{Folder: "Junk", Warning: 100, Critical: 500},
+	}
+
+	tests := map[string]struct {
+		results  mbxs.MailboxCheckResults
+		warning  string
+		critical string
+		folders  config.FolderThresholds
+		want     int
+	}{
+		"legacy any mail is warning": {
+			results: mbxs.MailboxCheckResults{{MailboxName: "INBOX", ItemsFound: 1}},
+			warning: "0",
+			want:    nagios.StateWARNINGExitCode,
+		},
+		"legacy no mail is ok": {
+			results: mbxs.MailboxCheckResults{{MailboxName: "INBOX", ItemsFound: 0}},
+			warning: "0",
+			want:    nagios.StateOKExitCode,
+		},
+		"total critical": {
+			results:  mbxs.MailboxCheckResults{{MailboxName: "INBOX", ItemsFound: 11}},
+			warning:  "5",
+			critical: "10",
+			want:     nagios.StateCRITICALExitCode,
+		},
+		"folders within thresholds": {
+			results: mbxs.MailboxCheckResults{
+				{MailboxName: "Inbox", ItemsFound: 5},
+				{MailboxName: "Junk", ItemsFound: 90},
+			},
+			folders: folderThresholds,
+			want:    nagios.StateOKExitCode,
+		},
+		"folder over warning": {
+			results: mbxs.MailboxCheckResults{
+				{MailboxName: "INBOX", ItemsFound: 6},
+				{MailboxName: "Junk", ItemsFound: 90},
+			},
+			folders: folderThresholds,
+			want:    nagios.StateWARNINGExitCode,
+		},
+		"folder over critical escalates": {
+			results: mbxs.MailboxCheckResults{
+				{MailboxName: "INBOX", ItemsFound: 1},
+				{MailboxName: "Junk", ItemsFound: 501},
+			},
+			warning: "1000",
+			folders: folderThresholds,
+			want:    nagios.StateCRITICALExitCode,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluation, err := EvaluateThresholds(tt.results, tt.warning, tt.critical, tt.folders)
+			if err != nil {
+				t.Fatalf("failed to evaluate thresholds: %v", err)
+			}
+
+			if got := evaluation.ExitStatusCode(); got != tt.want {
+				t.Errorf(
+					"want state %s, got %s\n%s",
+					nagios.ExitCodeToStateLabel(tt.want),
+					nagios.ExitCodeToStateLabel(got),
+					evaluation.Report(),
+				)
+			}
+		})
+	}
+}
+
+// TestThresholdEvaluationReport asserts that the long output lists each
+// evaluated threshold.
+func TestThresholdEvaluationReport(t *testing.T) {
+	t.Parallel()
+
+	results := mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX", ItemsFound: 21},
+		{MailboxName: "Trash", ItemsFound: 3},
+	}
+
+	evaluation, err := EvaluateThresholds(
+		results,
+		"",
+		"",
+		config.FolderThresholds{{Folder: "INBOX", Warning: 5, Critical: 20}},
+	)
+	if err != nil {
+		t.Fatalf("failed to evaluate thresholds: %v", err)
+	}
+
+	report := evaluation.Report()
+	for _, want := range []string{
+		"* total: 24 messages (warning: none, critical: none): OK",
+		"* INBOX: 21 messages (warning: 5, critical: 20): CRITICAL",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+
+	if strings.Contains(report, "Trash") {
+		t.Errorf("report includes folder without thresholds:\n%s", report)
+	}
+}
+
+// TestEvaluateThresholdsInvalidRange asserts that an invalid range string is
+// reported as an error.
+func TestEvaluateThresholdsInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	_, err := EvaluateThresholds(nil, "10:5", "", nil)
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
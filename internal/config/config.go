@@ -14,6 +14,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -43,6 +44,18 @@ var (
 	// authentication type was specified.
 	ErrInvalidAuthType = errors.New("invalid auth type")
 
+	// ErrInvalidOAuth2Provider indicates that an invalid or unsupported
+	// OAuth2 provider was specified.
+	ErrInvalidOAuth2Provider = errors.New("invalid OAuth2 provider")
+
+	// ErrInvalidOAuth2GrantType indicates that an invalid or unsupported
+	// OAuth2 grant type was specified.
+	ErrInvalidOAuth2GrantType = errors.New("invalid OAuth2 grant type")
+
+	// ErrNoAccountsFound indicates that a configuration file did not list
+	// any accounts.
+	ErrNoAccountsFound = errors.New("no accounts found")
+
 	// ErrConfigNotInitialized indicates that the configuration is not in a
 	// usable state and application execution can not successfully proceed.
 	ErrConfigNotInitialized = errors.New("configuration not initialized")
@@ -67,6 +80,12 @@ type AppType struct {
 	// An OAuth2 flow is used to login.
 	PluginIMAPMailboxOAuth2 bool
 
+	// PluginIMAPQuota represents an application used as a monitoring plugin
+	// for evaluating IMAP mailbox quota usage.
+	//
+	// Either Basic Authentication or an OAuth2 flow is used to login.
+	PluginIMAPQuota bool
+
 	// FetcherOAuth2TokenFromCache represents an application used to obtain an
 	// OAuth2 token via Client Credentials flow from local storage/cache.
 	FetcherOAuth2TokenFromCache bool
@@ -113,6 +132,10 @@ type OAuth2ClientCredentialsFlow struct {
 	// ClientSecret is a secret known only to the application and the
 	// authorization server. It can be considered the application's own
 	// password. This value is provided upon application authorization.
+	//
+	// A reference to the value (file:, env:, credential: or cmd:) may be
+	// specified instead; the reference is resolved when the configuration
+	// is loaded.
 	ClientSecret string
 
 	// Scopes is the collection of permissions or "scopes" requested by an
@@ -146,6 +169,33 @@ type OAuth2ClientCredentialsFlow struct {
 	// TokenURL is the authority endpoint for token retrieval.
 	TokenURL string
 
+	// Provider is an optional keyword indicating a known OAuth2 provider. If
+	// specified, the TokenURL and Scopes values are filled in using
+	// provider-specific defaults unless explicitly specified.
+	Provider string
+
+	// TenantID is the directory (tenant) ID used to construct the token
+	// endpoint URL for the Office 365 provider.
+	TenantID string
+
+	// IssuerURL is the OpenID Connect issuer URL used to discover the token
+	// endpoint URL for the generic OIDC provider.
+	IssuerURL string
+
+	// GrantType is the OAuth2 grant used to obtain a token. The Client
+	// Credentials grant is used if not specified. The JWT bearer grant uses
+	// a service account key in place of the ClientID and ClientSecret values.
+	GrantType string
+
+	// ServiceAccountKeyFile is the path to a (Google Cloud) service account
+	// key file in JSON format. This is used with the JWT bearer grant.
+	ServiceAccountKeyFile string
+
+	// Subject is the user impersonated by a service account using
+	// domain-wide delegation. This is used with the JWT bearer grant. If not
+	// specified, the SharedMailbox value is used.
+	Subject string
+
 	// RetrievalAttempts indicates how many attempts should be made to
 	// retrieve a token.
 	RetrievalAttempts int
@@ -162,6 +212,12 @@ type FetcherOAuth2TokenSettings struct {
 	// EmitTokenAsJSON indicates whether the retrieved token is saved in
 	// the original JSON payload format or as just the access token itself.
 	EmitTokenAsJSON bool
+
+	// EncryptionKey is the optional base64 encoded 256-bit key used to
+	// encrypt the token file written by the token fetcher and to decrypt
+	// the token file read from the cache. A reference to the key (file:,
+	// env:, credential: or cmd:) may be specified instead.
+	EncryptionKey string
 }
 
 // MailAccount represents an email account. The values are provided via
@@ -184,13 +240,43 @@ type MailAccount struct {
 	AuthType string
 
 	// Folders is a collection of paths associated with an account. This
-	// includes paths such as "Inbox", "Junk EMail" or "Trash".
+	// includes paths such as "Inbox", "Junk EMail" or "Trash". Patterns
+	// using the IMAP LIST wildcards "*" and "%" (e.g., "Projects/*") are
+	// expanded against the folders present on the server. Special-use roles
+	// (e.g., "\Junk") are resolved to the folder having that role.
 	Folders multiValueFlag
 
+	// RecurseFolders indicates whether all subfolders of each specified
+	// folder are also checked.
+	RecurseFolders bool
+
+	// ExcludeFolders is a collection of folder names or patterns excluded
+	// from the list of folders to check.
+	ExcludeFolders multiValueFlag
+
+	// Criteria is a collection of message criteria (e.g., "unseen",
+	// "flagged") used to limit the messages counted and listed. All criteria
+	// must match for a message to be included. All messages are included if
+	// no criteria are specified.
+	Criteria multiValueFlag
+
+	// IncludeFilters is a collection of FIELD:VALUE search filter
+	// expressions (e.g., "from:alerts@example.com"). All expressions must
+	// match for a message to be included.
+	IncludeFilters SearchFilters
+
+	// ExcludeFilters is a collection of FIELD:VALUE search filter
+	// expressions. Messages matching any of these expressions are excluded.
+	ExcludeFilters SearchFilters
+
 	// Username is usually the full email address associated with an account.
 	Username string
 
 	// Password is the plaintext password for the email account.
+	//
+	// A reference to the value (file:, env:, credential: or cmd:) may be
+	// specified instead; the reference is resolved when the configuration
+	// is loaded.
 	Password string
 
 	// OAuth2Settings is a collection of settings specific to OAuth2
@@ -268,8 +354,8 @@ type Config struct {
 	ConfigFileLoaded bool
 
 	// ConfigFile is the path to the user-provided config file. This config
-	// file is not currently used by the check_imap_mailbox plugin provided by
-	// this project.
+	// file is optionally used by the check_imap_mailbox plugins provided by
+	// this project to specify the accounts to check.
 	ConfigFile string
 
 	// ConfigFileUsed is an internal field indicating *what* config file was
@@ -305,6 +391,119 @@ type Config struct {
 	// applications provided by this project.
 	Accounts []MailAccount
 
+	// WarningThreshold is the Nagios range string applied to the total
+	// number of messages found. Used by the Nagios plugins.
+	WarningThreshold string
+
+	// CriticalThreshold is the Nagios range string applied to the total
+	// number of messages found. Used by the Nagios plugins.
+	CriticalThreshold string
+
+	// FolderThresholds is the collection of message count thresholds for
+	// specific folders. Used by the Nagios plugins.
+	FolderThresholds FolderThresholds
+
+	// AgeWarningThreshold is the maximum age of the oldest message in any
+	// checked folder before a WARNING state is indicated. A zero value
+	// disables this threshold. Used by the Nagios plugins.
+	AgeWarningThreshold time.Duration
+
+	// AgeCriticalThreshold is the maximum age of the oldest message in any
+	// checked folder before a CRITICAL state is indicated. A zero value
+	// disables this threshold. Used by the Nagios plugins.
+	AgeCriticalThreshold time.Duration
+
+	// ExpectMail indicates whether the Nagios plugins should evaluate
+	// checked folders as "heartbeat" folders where the absence of (recent)
+	// mail is a problem.
+	ExpectMail bool
+
+	// NewestAgeWarningThreshold is the maximum age of the newest message in
+	// each checked folder before a WARNING state is indicated. A zero value
+	// disables this threshold. Used in ExpectMail mode.
+	NewestAgeWarningThreshold time.Duration
+
+	// NewestAgeCriticalThreshold is the maximum age of the newest message in
+	// each checked folder before a CRITICAL state is indicated. A zero value
+	// disables this threshold. Used in ExpectMail mode.
+	NewestAgeCriticalThreshold time.Duration
+
+	// MessageWindow limits the messages counted against the WARNING and
+	// CRITICAL thresholds to those received within the specified time
+	// window. A zero value counts all messages. Used in ExpectMail mode.
+	MessageWindow time.Duration
+
+	// MissingFolderState is the keyword for the plugin state used when a
+	// specified folder is not found. Used by the Nagios plugins.
+	MissingFolderState string
+
+	// ListMessages is the number of messages listed for each folder in the
+	// extended plugin output. A zero value disables the listing. Used by the
+	// Nagios plugins.
+	ListMessages int
+
+	// ListOrder is the keyword for the order of messages listed in the
+	// extended plugin output. Used by the Nagios plugins.
+	ListOrder string
+
+	// ListFormat is the keyword for the format of messages listed in the
+	// extended plugin output. Used by the Nagios plugins.
+	ListFormat string
+
+	// Delta indicates whether only messages which arrived since the previous
+	// check are counted (or listed). Used by the Nagios plugins and the
+	// Reporter app.
+	Delta bool
+
+	// StateDir is the full path to the directory where state files recording
+	// the position reached in each folder by the previous check (and the
+	// history of previous checks) are stored.
+	StateDir string
+
+	// Rates indicates whether the history of each check is recorded and
+	// used to evaluate the rates at which messages arrive in and are
+	// removed from each folder. Used by the Nagios plugins.
+	Rates bool
+
+	// RateWindows is the collection of time windows over which message
+	// rates are evaluated. Used by the Nagios plugins.
+	RateWindows RateWindows
+
+	// RateThresholds is the collection of Nagios ranges evaluated against
+	// message rates. Used by the Nagios plugins.
+	RateThresholds RateThresholds
+
+	// QuotaWarningThreshold is the percentage of a quota resource limit at
+	// or above which a WARNING state is indicated. A zero value disables
+	// this threshold. Used by the quota plugin.
+	QuotaWarningThreshold int
+
+	// QuotaCriticalThreshold is the percentage of a quota resource limit at
+	// or above which a CRITICAL state is indicated. A zero value disables
+	// this threshold. Used by the quota plugin.
+	QuotaCriticalThreshold int
+
+	// Retries is the maximum number of times the connect, login and mail
+	// check sequence is retried after a transient error.
+	Retries int
+
+	// RetryDelay is the time waited before the first retry after a
+	// transient error. The delay is doubled for each subsequent retry.
+	RetryDelay time.Duration
+
+	// Timeout is the maximum time permitted for a plugin to complete. Used
+	// by the Nagios plugins.
+	Timeout time.Duration
+
+	// FetchBatchSize is the number of messages retrieved per FETCH command
+	// when message details are retrieved.
+	FetchBatchSize int
+
+	// MaxMessagesPerFolder is the maximum number of (most recent) messages
+	// listed per folder. A zero value lists all messages. Used by the
+	// Reporter application.
+	MaxMessagesPerFolder int
+
 	// FetcherOAuth2TokenSettings is the collection of OAuth2 token "fetcher"
 	// settings.
 	FetcherOAuth2TokenSettings FetcherOAuth2TokenSettings
@@ -431,7 +630,27 @@ func New(appType AppType) (*Config, error) {
 		)
 	}
 
-	if appType.ReporterIMAPMailbox {
+	// Accounts are loaded from a configuration file by the Reporter app and
+	// (optionally) by the mailbox plugins.
+	loadAccounts := appType.ReporterIMAPMailbox || config.accountsFromFile(appType)
+
+	if !loadAccounts {
+		if err := config.resolveSecrets(); err != nil {
+			return nil, fmt.Errorf(
+				"failed to resolve secret values: %w",
+				err,
+			)
+		}
+
+		if err := config.resolveOAuth2Providers(); err != nil {
+			return nil, fmt.Errorf(
+				"failed to resolve OAuth2 provider settings: %w",
+				err,
+			)
+		}
+	}
+
+	if loadAccounts {
 		if err := config.load(); err != nil {
 
 			// We log this message in an effort to populate the log file with
@@ -444,6 +663,13 @@ func New(appType AppType) (*Config, error) {
 			return nil, fmt.Errorf("%s: %w", errMsg, err)
 		}
 
+		if len(config.Accounts) == 0 {
+			errMsg := "no accounts found in configuration file"
+			config.Log.Error().Str("config_file", config.ConfigFileUsed).Msg(errMsg)
+
+			return nil, fmt.Errorf("%s %q: %w", errMsg, config.ConfigFileUsed, ErrNoAccountsFound)
+		}
+
 		// Final validation pass using flag AND config file values.
 		if err := config.validate(appType); err != nil {
 			return nil, fmt.Errorf(
@@ -451,12 +677,34 @@ func New(appType AppType) (*Config, error) {
 				err,
 			)
 		}
+
+		if err := config.resolveSecrets(); err != nil {
+			errMsg := "failed to resolve secret values"
+			config.Log.Error().Err(err).Msg(errMsg)
+
+			return nil, fmt.Errorf("%s: %w", errMsg, err)
+		}
+
+		if err := config.resolveOAuth2Providers(); err != nil {
+			errMsg := "failed to resolve OAuth2 provider settings"
+			config.Log.Error().Err(err).Msg(errMsg)
+
+			return nil, fmt.Errorf("%s: %w", errMsg, err)
+		}
 	}
 
 	return &config, nil
 
 }
 
+// accountsFromFile indicates whether the accounts checked by a mailbox
+// plugin are loaded from a user-specified configuration file instead of
+// being provided via flags.
+func (c Config) accountsFromFile(appType AppType) bool {
+	return (appType.PluginIMAPMailboxBasicAuth || appType.PluginIMAPMailboxOAuth2) &&
+		c.ConfigFile != ""
+}
+
 // load is a helper function to handle the bulk of the configuration loading
 // work for the New constructor function.
 func (c *Config) load() error {
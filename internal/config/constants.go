@@ -16,50 +16,147 @@ import (
 const (
 	HelpFlagLong  string = "help"
 	HelpFlagShort string = "h"
+
+	WarningThresholdFlagLong   string = "warning"
+	WarningThresholdFlagShort  string = "w"
+	CriticalThresholdFlagLong  string = "critical"
+	CriticalThresholdFlagShort string = "c"
+	FolderThresholdsFlag       string = "folder-thresholds"
+	AgeWarningFlag             string = "age-warning"
+	AgeCriticalFlag            string = "age-critical"
+	ExpectMailFlag             string = "expect-mail"
+	NewestAgeWarningFlag       string = "newest-age-warning"
+	NewestAgeCriticalFlag      string = "newest-age-critical"
+	MessageWindowFlag          string = "window"
+	FetchBatchSizeFlag         string = "fetch-batch-size"
+	MaxMessagesFlag            string = "max-messages"
+	MissingFolderStateFlag     string = "missing-folder-state"
+	RetriesFlag                string = "retries"
+	RetryDelayFlag             string = "retry-delay"
+	TimeoutFlag                string = "timeout"
+	ListMessagesFlag           string = "list-messages"
+	ListOrderFlag              string = "list-order"
+	ListFormatFlag             string = "list-format"
+	DeltaFlag                  string = "delta"
+	StateDirFlag               string = "state-dir"
+	RatesFlag                  string = "rates"
+	RateWindowsFlag            string = "rate-windows"
+	ArrivalRateWarningFlag     string = "arrival-rate-warning"
+	ArrivalRateCriticalFlag    string = "arrival-rate-critical"
+	DrainRateWarningFlag       string = "drain-rate-warning"
+	DrainRateCriticalFlag      string = "drain-rate-critical"
+	GrowthRateWarningFlag      string = "growth-rate-warning"
+	GrowthRateCriticalFlag     string = "growth-rate-critical"
 )
 
+// expectMailCriticalThreshold is the Nagios range applied to the total
+// number of messages found in expect mail mode if no thresholds are
+// specified. A CRITICAL state is indicated if no messages are found.
+const expectMailCriticalThreshold string = "1:"
+
+// legacyWarningThreshold is the Nagios range applied to the total number of
+// messages found if no thresholds are specified. This preserves the original
+// behavior of indicating a WARNING state if any messages are found.
+const legacyWarningThreshold string = "0"
+
 // Shared flag help text
 const (
-	foldersFlagHelp       string = "Folders or IMAP \"mailboxes\" to check for mail. This value is provided as a comma-separated list."
-	serverFlagHelp        string = "The fully-qualified domain name of the remote mail server."
-	portFlagHelp          string = "TCP port used to connect to the remote mail server. This is usually the same port used for TLS encrypted IMAP connections."
-	networkTypeFlagHelp   string = "Limits network connections to remote mail servers to one of tcp4 (IPv4-only), tcp6 (IPv6-only) or auto (either)."
-	minTLSVersionFlagHelp string = "Limits version of TLS used for connections to remote mail servers to one of tls10 (TLS v1.0), tls11, tls12 or tls13 (TLS v1.3)."
-	loggingLevelFlagHelp  string = "Sets log level to one of disabled, panic, fatal, error, warn, info, debug or trace."
-	emitBrandingFlagHelp  string = "Toggles emission of branding details with plugin status details. This output is disabled by default."
-	helpFlagHelp          string = "Emit this help text"
-	versionFlagHelp       string = "Whether to display application version and then immediately exit application."
+	foldersFlagHelp        string = "Folders or IMAP \"mailboxes\" to check for mail. This value is provided as a comma-separated list. Patterns using the * (any characters, including subfolders) and % (any characters within a single folder level) wildcards are expanded against the folders present on the server (e.g., \"Projects/*\"). Folders may also be specified by special-use role: \\All, \\Archive, \\Drafts, \\Flagged, \\Junk, \\Sent or \\Trash."
+	recurseFoldersFlagHelp string = "Whether all subfolders of each specified folder are also checked."
+	excludeFoldersFlagHelp string = "Folders, folder patterns or special-use roles to exclude from the expanded list of folders to check. This value is provided as a comma-separated list."
+	criteriaFlagHelp       string = "Only count and list messages matching all of the specified criteria. This value is provided as a comma-separated list. Supported criteria: unseen, flagged, unanswered, deleted (not yet expunged)."
+	includeFlagHelp        string = "Only count and list messages matching all of the specified FIELD:VALUE search filters. This value is provided as a comma-separated list; double-quote values containing commas. Supported fields: from, to, cc, subject, header (header:NAME=VALUE), larger, smaller (bytes with optional K, M or G suffix), since, before (YYYY-MM-DD or a duration such as 36h or 7d)."
+	excludeFlagHelp        string = "Do not count or list messages matching any of the specified FIELD:VALUE search filters. Uses the same syntax as the include flag."
+	serverFlagHelp         string = "The fully-qualified domain name of the remote mail server."
+	portFlagHelp           string = "TCP port used to connect to the remote mail server. This is usually the same port used for TLS encrypted IMAP connections."
+	networkTypeFlagHelp    string = "Limits network connections to remote mail servers to one of tcp4 (IPv4-only), tcp6 (IPv6-only) or auto (either)."
+	minTLSVersionFlagHelp  string = "Limits version of TLS used for connections to remote mail servers to one of tls10 (TLS v1.0), tls11, tls12 or tls13 (TLS v1.3)."
+	loggingLevelFlagHelp   string = "Sets log level to one of disabled, panic, fatal, error, warn, info, debug or trace."
+	emitBrandingFlagHelp   string = "Toggles emission of branding details with plugin status details. This output is disabled by default."
+	retriesFlagHelp        string = "Maximum number of times the connect, login and mail check sequence is retried after a transient error (e.g., a dropped connection, throttling or a \"User is authenticated but not connected\" response). A value of 0 disables retries."
+	retryDelayFlagHelp     string = "Time waited before the first retry after a transient error. The delay is doubled for each subsequent retry."
+	timeoutFlagHelp        string = "Maximum time permitted for the plugin to complete. Transient errors are not retried if the next attempt would exceed this limit."
+	helpFlagHelp           string = "Emit this help text"
+	versionFlagHelp        string = "Whether to display application version and then immediately exit application."
 )
 
 // PluginIMAPMailboxBasicAuth flag help text
 const (
 	usernameFlagHelp string = "The account used to login to the remote mail server using Basic Auth. This is often in the form of an email address."
-	passwordFlagHelp string = "The remote mail server account password. Used for Basic Auth."
+	passwordFlagHelp string = "The remote mail server account password. Used for Basic Auth. May be a reference to the value instead: file:/path, env:VAR, credential:NAME (systemd credential) or cmd:COMMAND (arguments separated by spaces; quoted arguments are not supported). Use the plain: prefix for a literal value which starts with one of these prefixes."
 )
 
 // PluginIMAPMailboxOauth2 flag help text
 const (
 	clientIDFlagHelp      string = "Application (client) ID created during app registration."
-	clientSecretFlagHelp  string = "Client secret (aka, \"app\" password)."
+	clientSecretFlagHelp  string = "Client secret (aka, \"app\" password). May be a reference to the value instead: file:/path, env:VAR, credential:NAME (systemd credential) or cmd:COMMAND (arguments separated by spaces; quoted arguments are not supported)."
 	scopesFlagHelp        string = "One or more scopes requested from the authorization server. E.g., \"https://outlook.office365.com/.default\" for O365."
 	sharedMailboxFlagHelp string = "Email account that is to be accessed using client ID & secret values. Usually a shared mailbox among a team."
 
 	// False-positive gosec linter warning
 	//nolint
 	tokenURLFlagHelp string = "The OAuth2 provider's token endpoint URL. E.g., \"https://accounts.google.com/o/oauth2/token\" for Google. See example INI file for O365 example."
+
+	providerFlagHelp  string = "Optional OAuth2 provider preset used to fill in token URL and default scopes. One of office365, google or generic-oidc. Explicitly specified token URL and scopes values take precedence."
+	tenantIDFlagHelp  string = "Directory (tenant) ID used to construct the token URL for the office365 provider."
+	issuerURLFlagHelp string = "OpenID Connect issuer URL used to discover the token URL for the generic-oidc provider. E.g., \"https://idp.example.com/realms/mail\"."
+
+	grantTypeFlagHelp          string = "OAuth2 grant used to obtain a token. One of client-credentials or jwt-bearer. The jwt-bearer grant uses a service account key with domain-wide delegation in place of client ID & secret values."
+	serviceAccountKeyFlagHelp  string = "Path to a service account key file (JSON format). Required for the jwt-bearer grant."
+	impersonateSubjectFlagHelp string = "The user impersonated by the service account when using the jwt-bearer grant. Defaults to the shared mailbox value if not specified."
+)
+
+// Plugin threshold flag help text
+const (
+	warningThresholdFlagHelp    string = "Nagios range for the total number of messages found which results in a WARNING state. E.g., \"10\" (more than 10 messages). If no thresholds are specified, finding any messages results in a WARNING state."
+	criticalThresholdFlagHelp   string = "Nagios range for the total number of messages found which results in a CRITICAL state. E.g., \"20\" (more than 20 messages)."
+	folderThresholdsFlagHelp    string = "Per-folder message count thresholds in the form FOLDER:WARNING:CRITICAL provided as a comma-separated list. E.g., \"INBOX:5:20,Junk:100:500\". A folder with more messages than the WARNING or CRITICAL value results in a WARNING or CRITICAL state."
+	ageWarningFlagHelp          string = "Maximum age of the oldest message in any checked folder before a WARNING state is indicated. E.g., \"4h\". Message age is based on the date the message was received by the server, falling back to the envelope date. Disabled by default."
+	ageCriticalFlagHelp         string = "Maximum age of the oldest message in any checked folder before a CRITICAL state is indicated. E.g., \"24h\". Disabled by default."
+	expectMailFlagHelp          string = "Evaluate checked folders as \"heartbeat\" folders where the absence of recent mail is a problem. In this mode the warning and critical Nagios ranges apply to the number of messages found (optionally limited by the window flag); e.g., \"1:\" for fewer than 1 message. If no thresholds are specified, a folder without messages results in a CRITICAL state."
+	newestAgeWarningFlagHelp    string = "Used with the expect-mail flag. Maximum age of the newest message in each checked folder before a WARNING state is indicated. E.g., \"26h\"."
+	newestAgeCriticalFlagHelp   string = "Used with the expect-mail flag. Maximum age of the newest message in each checked folder before a CRITICAL state is indicated. E.g., \"48h\"."
+	messageWindowFlagHelp       string = "Used with the expect-mail flag. Only messages received within this time window (e.g., \"24h\") are counted against the warning and critical thresholds."
+	missingFolderStateFlagHelp  string = "Plugin state used when a specified folder is not found. One of ok, warning, critical or unknown. Messages in the folders which are found are still counted and evaluated."
+	listMessagesFlagHelp        string = "Number of messages listed for each folder in the extended plugin output (date, sender and subject). A value of 0 disables the listing."
+	listOrderFlagHelp           string = "Order of messages listed in the extended plugin output. One of newest (newest messages first) or oldest (oldest messages first)."
+	listFormatFlagHelp          string = "Format of messages listed in the extended plugin output. One of text or html (for display in the Nagios web UI)."
+	deltaFlagHelp               string = "Only count messages which arrived since the previous check (delta mode). The UIDVALIDITY value and highest UID seen for each folder is recorded in a state file for each account within the directory specified by the state-dir flag. All messages are counted for the first check and for folders whose UIDVALIDITY value has changed. The recorded position advances with every check, including Nagios soft state rechecks; new messages are only reported by the first check which sees them. Set max_check_attempts to 1 for the service so that this result is a hard state."
+	ratesFlagHelp               string = "Record the number of messages and highest UID seen for each folder in the state file for each account (within the directory specified by the state-dir flag) and evaluate the rates at which messages arrive in, are removed from (drain) and accumulate in (growth) each folder. Rates are emitted as performance data. Arrivals are counted from the highest UID assigned in each folder and removals from arrivals less growth; UIDs assigned to messages which were never visible (e.g., moved or deleted before the check) are counted, so arrival and drain rates are upper bounds. Growth is exact."
+	rateWindowsFlagHelp         string = "One or more time windows (e.g., \"1h,24h\") over which rates are evaluated. Rates are not evaluated for a window until check history covering the full window is recorded. Defaults to 1h."
+	arrivalRateWarningFlagHelp  string = "Nagios range for the number of messages arriving in a folder per hour (an upper bound) which results in a WARNING state. E.g., \"50\" (more than 50 messages per hour)."
+	arrivalRateCriticalFlagHelp string = "Nagios range for the number of messages arriving in a folder per hour which results in a CRITICAL state."
+	drainRateWarningFlagHelp    string = "Nagios range for the number of messages removed from a folder per hour (an upper bound) which results in a WARNING state. E.g., \"10:\" (fewer than 10 messages per hour)."
+	drainRateCriticalFlagHelp   string = "Nagios range for the number of messages removed from a folder per hour which results in a CRITICAL state."
+	growthRateWarningFlagHelp   string = "Nagios range for the net change in the number of messages in a folder per hour (arrivals less removals) which results in a WARNING state. E.g., \"20\" (filling by more than 20 messages per hour)."
+	growthRateCriticalFlagHelp  string = "Nagios range for the net change in the number of messages in a folder per hour which results in a CRITICAL state."
+	stateDirFlagHelp            string = "Full path to the directory where state files are recorded. Required for delta mode and rate evaluation. The user account running this plugin requires write permission to this directory. State files are kept separate for each application, account, folder list and message filter."
+	accountsFileFlagHelp        string = "Full path to an INI-formatted configuration file listing the accounts to check (see the accounts.example.ini files under the contrib/list-emails directory). If specified, all accounts listed in the file are checked and account details provided via flags (e.g., server, username, folders) are ignored. The authentication type used by the file must match this plugin."
+)
+
+// Quota plugin flag help text
+const (
+	authTypeFlagHelp               string = "Authentication type used to login. One of basic or oauth2."
+	quotaWarningThresholdFlagHelp  string = "Percentage of a quota resource limit (e.g., STORAGE or MESSAGE) used above which a WARNING state results. A value of 0 disables this threshold."
+	quotaCriticalThresholdFlagHelp string = "Percentage of a quota resource limit (e.g., STORAGE or MESSAGE) used above which a CRITICAL state results. A value of 0 disables this threshold."
 )
 
 // Reporter flag help text
 const (
 	iniConfigFileFlagHelp       string = "Full path to the INI-formatted configuration file used by this application. See the accounts.example.ini files under contrib/list-emails directory for a starter template. Copy to accounts.ini, update with applicable information and place in a directory of your choice. If this file is found in your current working directory you need not use this flag."
 	reportFileOutputDirFlagHelp string = "Full path to the directory where email summary report files will be created. The user account running this application requires write permission to this directory. If not specified, a default directory will be created in your current working directory if it does not already exist."
+	fetchBatchSizeFlagHelp      string = "Number of messages retrieved per IMAP FETCH command when message details are needed. Smaller values limit the size of individual server responses for very large folders."
+	maxMessagesFlagHelp         string = "Maximum number of (most recent) messages listed per folder. All messages are still counted. A value of 0 lists all messages."
 	logFileOutputDirFlagHelp    string = "Full path to the directory where log files will be created. The user account running this application requires write permission to this directory. If not specified, a default directory will be created in your current working directory if it does not already exist."
+	reportDeltaFlagHelp         string = "Only list messages which arrived since the previous report (incremental reports). The UIDVALIDITY value and highest UID seen for each folder is recorded in a state file for each account within the directory specified by the state-dir flag. All messages are listed for the first report and for folders whose UIDVALIDITY value has changed."
+	reportStateDirFlagHelp      string = "Full path to the directory where state files used for incremental reports are recorded. The user account running this application requires write permission to this directory. If not specified, a default directory will be created in your current working directory if it does not already exist."
 )
 
 // Fetcher flag help text
 const (
-	emitTokenAsJSONFlagHelp string = "Emit retrieved token in JSON format. Defaults to emitting the access token field from retrieved payload."
-	tokenFilenameFlagHelp   string = "Save retrieved token to specified file. Emitted to standard out (stdout) if not specified."
+	emitTokenAsJSONFlagHelp    string = "Emit retrieved token in JSON format. Defaults to emitting the access token field from retrieved payload."
+	tokenFilenameFlagHelp      string = "Save retrieved token to specified file. Emitted to standard out (stdout) if not specified."
+	tokenEncryptionKeyFlagHelp string = "Optional base64 encoded 256-bit key (e.g., from \"openssl rand -base64 32\") used to encrypt (AES-256-GCM) the token file when saving and to decrypt it when reading. Unencrypted token files are still read as-is. A reference to the key may be specified instead: file:/path, env:VAR, credential:NAME (systemd credential) or cmd:COMMAND (arguments separated by spaces; quoted arguments are not supported)."
 
 	// False-positive gosec linter warning
 	//nolint
@@ -72,22 +169,57 @@ const shorthandFlagSuffix = " (shorthand)"
 
 // Default flag settings if not overridden by user input
 const (
-	defaultHelp                  bool   = false
-	defaultLoggingLevel          string = "info"
-	defaultEmitBranding          bool   = false
-	defaultPort                  int    = 993
-	defaultServer                string = ""
-	defaultPassword              string = ""
-	defaultUsername              string = ""
-	defaultClientID              string = ""
-	defaultClientSecret          string = ""
-	defaultSharedMailbox         string = ""
-	defaultTokenURL              string = ""
-	defaultNetworkType           string = netTypeTCPAuto
-	defaultMinTLSVersion         string = minTLSVersion12
-	defaultDisplayVersionAndExit bool   = false
-	defaultEmitTokenAsJSON       bool   = false
-	defaultTokenFilename         string = ""
+	defaultHelp                  bool          = false
+	defaultLoggingLevel          string        = "info"
+	defaultEmitBranding          bool          = false
+	defaultPort                  int           = 993
+	defaultServer                string        = ""
+	defaultPassword              string        = ""
+	defaultUsername              string        = ""
+	defaultClientID              string        = ""
+	defaultClientSecret          string        = ""
+	defaultSharedMailbox         string        = ""
+	defaultTokenURL              string        = ""
+	defaultProvider              string        = ""
+	defaultTenantID              string        = ""
+	defaultIssuerURL             string        = ""
+	defaultGrantType             string        = OAuth2GrantTypeClientCredentials
+	defaultServiceAccountKeyFile string        = ""
+	defaultSubject               string        = ""
+	defaultNetworkType           string        = netTypeTCPAuto
+	defaultMinTLSVersion         string        = minTLSVersion12
+	defaultDisplayVersionAndExit bool          = false
+	defaultEmitTokenAsJSON       bool          = false
+	defaultTokenFilename         string        = ""
+	defaultTokenEncryptionKey    string        = ""
+	defaultWarningThreshold      string        = ""
+	defaultCriticalThreshold     string        = ""
+	defaultAgeWarningThreshold   time.Duration = 0
+	defaultAgeCriticalThreshold  time.Duration = 0
+	defaultExpectMail            bool          = false
+	defaultRecurseFolders        bool          = false
+	defaultNewestAgeWarning      time.Duration = 0
+	defaultNewestAgeCritical     time.Duration = 0
+	defaultMessageWindow         time.Duration = 0
+	defaultAuthType              string        = AuthTypeBasic
+	defaultQuotaWarning          int           = 80
+	defaultQuotaCritical         int           = 90
+	defaultMissingFolderState    string        = stateKeywordCritical
+	defaultRetries               int           = 2
+	defaultRetryDelay            time.Duration = 2 * time.Second
+	defaultAccountsFile          string        = ""
+	defaultListMessages          int           = 0
+	defaultListOrder             string        = ListOrderNewest
+	defaultListFormat            string        = ListFormatText
+	defaultDelta                 bool          = false
+	defaultStateDir              string        = ""
+	defaultRates                 bool          = false
+	defaultRateThreshold         string        = ""
+
+	// defaultTimeout is the maximum time permitted for a plugin to complete.
+	// This is less than the default Nagios service check timeout of 60s so
+	// that results are reported before the plugin is terminated.
+	defaultTimeout time.Duration = 50 * time.Second
 
 	// By default these directories are created/used in the user's current
 	// working directory. The workflow for the older, Python-based list-emails
@@ -95,7 +227,10 @@ const (
 	// script itself. If the application happens to be in the current working
 	// directory, the resulting behavior will be the same.
 	defaultReportFileOutputDir string = "output"
+	defaultFetchBatchSize      int    = 500
+	defaultMaxMessages         int    = 0
 	defaultLogFileOutputDir    string = "log"
+	defaultReportStateDir      string = "state"
 
 	// defaultINIConfigFileName is the "bare" or "non-qualified" configuration
 	// filename. If not specified explicitly via CLI flag, this file will be
@@ -120,6 +255,16 @@ const (
 	defaultAccountProcessDelay time.Duration = time.Second * 5
 
 	defaultTokenRetrievalAttempts int = 3
+
+	// defaultOIDCDiscoveryTimeout is the maximum time permitted for
+	// retrieving the OpenID Provider Configuration document for the
+	// generic-oidc provider.
+	defaultOIDCDiscoveryTimeout time.Duration = time.Second * 10
+
+	// defaultSecretCommandTimeout is the maximum time permitted for a
+	// command used to retrieve a secret value (e.g., cmd:pass show mail) to
+	// complete.
+	defaultSecretCommandTimeout time.Duration = time.Second * 10
 )
 
 const (
@@ -134,6 +279,38 @@ const (
 	netTypeTCP6 string = "tcp6"
 )
 
+// Nagios state keywords used to specify the plugin state for a condition
+// (e.g., a folder which is not found).
+const (
+	stateKeywordOK       string = "ok"
+	stateKeywordWarning  string = "warning"
+	stateKeywordCritical string = "critical"
+	stateKeywordUnknown  string = "unknown"
+)
+
+// Supported keywords used to control the order of messages listed in the
+// extended plugin output.
+const (
+	ListOrderNewest string = "newest"
+	ListOrderOldest string = "oldest"
+)
+
+// Supported keywords used to control the format of messages listed in the
+// extended plugin output.
+const (
+	ListFormatText string = "text"
+	ListFormatHTML string = "html"
+)
+
+// Supported message criteria used to limit the messages counted and listed
+// within a mailbox.
+const (
+	messageCriterionUnseen     string = "unseen"
+	messageCriterionFlagged    string = "flagged"
+	messageCriterionUnanswered string = "unanswered"
+	messageCriterionDeleted    string = "deleted"
+)
+
 // TLS keywords used to map to TLS versions in the tls stdlib package.
 // https://golang.org/pkg/crypto/tls/#pkg-constants
 const (
@@ -157,15 +334,26 @@ const (
 	iniDefaultClientSecretKeyName     string = "client_secret"
 	iniDefaultScopesKeyName           string = "scopes"
 	iniDefaultEndpointTokenURLKeyName string = "endpoint_token_url"
+	iniDefaultProviderKeyName         string = "provider"
+	iniDefaultTenantIDKeyName         string = "tenant_id"
+	iniDefaultIssuerURLKeyName        string = "issuer_url"
+	iniDefaultGrantTypeKeyName        string = "grant_type"
+	iniDefaultServiceAccountKeyName   string = "service_account_key_file"
 )
 
 // These keys are found in the other (unique) sections in the INI file. If
 // account is specified then username will not be.
 const (
-	iniUsernameKeyName      string = "username"
-	iniSharedMailboxKeyName string = "shared_mailbox"
-	iniPasswordKeyName      string = "password"
-	iniFoldersKeyName       string = "folders"
+	iniUsernameKeyName       string = "username"
+	iniSharedMailboxKeyName  string = "shared_mailbox"
+	iniPasswordKeyName       string = "password"
+	iniFoldersKeyName        string = "folders"
+	iniSubjectKeyName        string = "subject"
+	iniCriteriaKeyName       string = "criteria"
+	iniIncludeKeyName        string = "include"
+	iniExcludeKeyName        string = "exclude"
+	iniRecurseFoldersKeyName string = "recurse_folders"
+	iniExcludeFoldersKeyName string = "exclude_folders"
 )
 
 // Supported authentication types used by applications in this project.
@@ -180,6 +368,55 @@ const (
 	AuthTypeOAuth2ClientCreds string = "oauth2"
 )
 
+// Supported OAuth2 provider presets. These are also the only valid values for
+// the provider INI config file key.
+const (
+
+	// OAuth2ProviderOffice365 indicates Microsoft Office 365 (Entra ID). A
+	// tenant ID is required to construct the token endpoint URL.
+	OAuth2ProviderOffice365 string = "office365"
+
+	// OAuth2ProviderGoogle indicates Google (Gmail / Workspace).
+	OAuth2ProviderGoogle string = "google"
+
+	// OAuth2ProviderGenericOIDC indicates an OpenID Connect provider whose
+	// token endpoint is discovered from the issuer's
+	// .well-known/openid-configuration document.
+	OAuth2ProviderGenericOIDC string = "generic-oidc"
+)
+
+// Supported OAuth2 grant types used to obtain a token for XOAUTH2
+// authentication. These are also the only valid values for the grant_type
+// INI config file key.
+const (
+
+	// OAuth2GrantTypeClientCredentials indicates the OAuth2 Client
+	// Credentials grant (client ID/secret).
+	OAuth2GrantTypeClientCredentials string = "client-credentials"
+
+	// OAuth2GrantTypeJWTBearer indicates the OAuth2 JWT bearer grant using a
+	// service account key to impersonate a user via domain-wide delegation.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc7523
+	OAuth2GrantTypeJWTBearer string = "jwt-bearer"
+)
+
+// Token endpoint URLs and default scopes for supported OAuth2 provider
+// presets.
+const (
+	// oauth2Office365TokenURLTemplate is populated with the tenant ID.
+	//
+	// False-positive gosec linter warning
+	//nolint
+	oauth2Office365TokenURLTemplate string = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+	oauth2Office365DefaultScope     string = "https://outlook.office365.com/.default"
+
+	// False-positive gosec linter warning
+	//nolint
+	oauth2GoogleTokenURL     string = "https://oauth2.googleapis.com/token"
+	oauth2GoogleDefaultScope string = "https://mail.google.com/"
+)
+
 // default permissions granting owner full access, deny access to all others
 const (
 	defaultDirectoryPerms os.FileMode = 0700
@@ -260,53 +260,78 @@ func (c *Config) parseConfigFile(file []byte) error {
 	var clientSecret string
 	var scopes []string
 	var tokenURL string
+	var provider string
+	var tenantID string
+	var issuerURL string
+	var grantType string
+	var serviceAccountKeyFile string
 	switch authType {
 	case AuthTypeOAuth2ClientCreds:
-		clientIDKey, lookupErr := defaultSection.GetKey(iniDefaultClientIDKeyName)
-		if lookupErr != nil {
+
+		// The grant type and service account key file keys are optional. The
+		// client ID and secret keys are required unless the JWT bearer grant
+		// is used.
+		grantType = strings.Trim(defaultSection.Key(iniDefaultGrantTypeKeyName).String(), `" `)
+		serviceAccountKeyFile = strings.Trim(defaultSection.Key(iniDefaultServiceAccountKeyName).String(), `" `)
+		jwtBearerGrant := strings.ToLower(grantType) == OAuth2GrantTypeJWTBearer
+
+		switch {
+		case defaultSection.HasKey(iniDefaultClientIDKeyName):
+			clientID = defaultSection.Key(iniDefaultClientIDKeyName).Value()
+
+		case !jwtBearerGrant:
 			return fmt.Errorf(
-				"failed to retrieve value from key %s: %w",
+				"failed to retrieve value from key %s: key not present",
 				iniDefaultClientIDKeyName,
-				lookupErr,
 			)
 		}
-		clientID = clientIDKey.Value()
 
-		clientSecretKey, lookupErr := defaultSection.GetKey(iniDefaultClientSecretKeyName)
-		if lookupErr != nil {
+		switch {
+		case defaultSection.HasKey(iniDefaultClientSecretKeyName):
+			clientSecret = defaultSection.Key(iniDefaultClientSecretKeyName).Value()
+
+		case !jwtBearerGrant:
 			return fmt.Errorf(
-				"failed to retrieve value from key %s: %w",
+				"failed to retrieve value from key %s: key not present",
 				iniDefaultClientSecretKeyName,
-				lookupErr,
 			)
 		}
-		clientSecret = clientSecretKey.Value()
 
-		tokenURLKey, lookupErr := defaultSection.GetKey(iniDefaultEndpointTokenURLKeyName)
-		if lookupErr != nil {
+		// The provider, tenant ID and issuer URL keys are optional. If a
+		// provider is specified the token URL and scopes keys are also
+		// optional; provider defaults are used in their place.
+		provider = strings.Trim(defaultSection.Key(iniDefaultProviderKeyName).String(), `" `)
+		tenantID = strings.Trim(defaultSection.Key(iniDefaultTenantIDKeyName).String(), `" `)
+		issuerURL = strings.Trim(defaultSection.Key(iniDefaultIssuerURLKeyName).String(), `" `)
+
+		switch {
+		case defaultSection.HasKey(iniDefaultEndpointTokenURLKeyName):
+			tokenURL = defaultSection.Key(iniDefaultEndpointTokenURLKeyName).Value()
+
+		case provider == "":
 			return fmt.Errorf(
-				"failed to retrieve value from key %s: %w",
+				"failed to retrieve value from key %s: key not present and %s not specified",
 				iniDefaultEndpointTokenURLKeyName,
-				lookupErr,
+				iniDefaultProviderKeyName,
 			)
 		}
-		tokenURL = tokenURLKey.Value()
 
-		scopesKey, lookupErr := defaultSection.GetKey(iniDefaultScopesKeyName)
-		if lookupErr != nil {
+		switch {
+		case defaultSection.HasKey(iniDefaultScopesKeyName):
+			// split and trim folders list provided as single string in INI file.
+			scopes = strings.Split(defaultSection.Key(iniDefaultScopesKeyName).Value(), ",")
+			for i, scope := range scopes {
+				scopes[i] = strings.Trim(scope, `" `)
+			}
+
+		case provider == "":
 			return fmt.Errorf(
-				"failed to retrieve value from key %s: %w",
+				"failed to retrieve value from key %s: key not present and %s not specified",
 				iniDefaultScopesKeyName,
-				lookupErr,
+				iniDefaultProviderKeyName,
 			)
 		}
 
-		// split and trim folders list provided as single string in INI file.
-		scopes = strings.Split(scopesKey.Value(), ",")
-		for i, scope := range scopes {
-			scopes[i] = strings.Trim(scope, `" `)
-		}
-
 	default:
 	}
 
@@ -338,9 +363,51 @@ func (c *Config) parseConfigFile(file []byte) error {
 			folders[i] = strings.Trim(folder, `" `)
 		}
 
+		// Optional; subfolders are only checked if requested.
+		var recurseFolders bool
+		if section.HasKey(iniRecurseFoldersKeyName) {
+			var parseErr error
+			recurseFolders, parseErr = section.Key(iniRecurseFoldersKeyName).Bool()
+			if parseErr != nil {
+				return fmt.Errorf(
+					"failed to parse value from key %s: %w",
+					iniRecurseFoldersKeyName,
+					parseErr,
+				)
+			}
+		}
+
+		var excludeFolders multiValueFlag
+		if section.HasKey(iniExcludeFoldersKeyName) {
+			for _, folder := range strings.Split(section.Key(iniExcludeFoldersKeyName).Value(), ",") {
+				excludeFolders = append(excludeFolders, strings.Trim(folder, `" `))
+			}
+		}
+
+		// Optional; all messages are included if not specified.
+		var criteria multiValueFlag
+		if section.HasKey(iniCriteriaKeyName) {
+			for _, criterion := range strings.Split(section.Key(iniCriteriaKeyName).Value(), ",") {
+				criteria = append(criteria, strings.Trim(criterion, `" `))
+			}
+		}
+
+		// Optional; search filters are double-quoted values which may
+		// contain commas.
+		var includeFilters SearchFilters
+		if section.HasKey(iniIncludeKeyName) {
+			includeFilters = splitSearchFilters(section.Key(iniIncludeKeyName).Value())
+		}
+
+		var excludeFilters SearchFilters
+		if section.HasKey(iniExcludeKeyName) {
+			excludeFilters = splitSearchFilters(section.Key(iniExcludeKeyName).Value())
+		}
+
 		var username string
 		var password string
 		var sharedMailbox string
+		var subject string
 
 		switch authType {
 
@@ -377,6 +444,9 @@ func (c *Config) parseConfigFile(file []byte) error {
 			}
 			sharedMailbox = mailboxKey.Value()
 
+			// Optional; only used with the JWT bearer grant.
+			subject = strings.Trim(section.Key(iniSubjectKeyName).String(), `" `)
+
 		default:
 			return fmt.Errorf(
 				"unexpected authentication type %q: %w",
@@ -397,10 +467,28 @@ func (c *Config) parseConfigFile(file []byte) error {
 				Scopes:        scopes,
 				SharedMailbox: sharedMailbox,
 				TokenURL:      tokenURL,
+				Provider:      provider,
+				TenantID:      tenantID,
+				IssuerURL:     issuerURL,
+
+				GrantType:             grantType,
+				ServiceAccountKeyFile: serviceAccountKeyFile,
+				Subject:               subject,
 			},
-			Port:    serverPort,
-			Name:    accountName,
-			Folders: folders,
+			Port:     serverPort,
+			Name:     accountName,
+			Folders:  folders,
+			Criteria: criteria,
+
+			RecurseFolders: recurseFolders,
+			ExcludeFolders: excludeFolders,
+
+			IncludeFilters: includeFilters,
+			ExcludeFilters: excludeFilters,
+		}
+
+		if authType == AuthTypeOAuth2ClientCreds {
+			account.OAuth2Settings.applyProviderPresets()
 		}
 
 		c.Accounts = append(c.Accounts, account)
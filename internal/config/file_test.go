@@ -334,3 +334,81 @@ func TestParseExampleConfigFiles(t *testing.T) {
 	}
 
 }
+
+func TestPluginAccountsFromConfigFile(t *testing.T) {
+	// NOTE: Not sure if running in parallel would work well
+	// with os.Args manipulation.
+	//
+	// t.Parallel()
+
+	basicAuthFile := filepath.Join("../../", "contrib", "list-emails", "basic-auth", "accounts.example.ini")
+	oauth2File := filepath.Join("../../", "contrib", "list-emails", "oauth2", "accounts.example.ini")
+
+	tests := map[string]struct {
+		appType      AppType
+		args         []string
+		wantAccounts int
+		wantErr      error
+	}{
+		"basic auth plugin": {
+			appType:      AppType{PluginIMAPMailboxBasicAuth: true},
+			args:         []string{"--config-file", basicAuthFile},
+			wantAccounts: 2,
+		},
+		"basic auth plugin ignores account flags": {
+			appType:      AppType{PluginIMAPMailboxBasicAuth: true},
+			args:         []string{"--config-file", basicAuthFile, "--username", "ignored", "--folders", "INBOX"},
+			wantAccounts: 2,
+		},
+		"oauth2 plugin": {
+			appType:      AppType{PluginIMAPMailboxOAuth2: true},
+			args:         []string{"--config-file", oauth2File},
+			wantAccounts: 2,
+		},
+		"oauth2 plugin with basic auth accounts": {
+			appType: AppType{PluginIMAPMailboxOAuth2: true},
+			args:    []string{"--config-file", basicAuthFile},
+			wantErr: ErrInvalidAuthType,
+		},
+	}
+
+	for testName, testCase := range tests {
+
+		t.Run(testName, func(t *testing.T) {
+
+			// Save old command-line arguments so that we can restore them later
+			// https://stackoverflow.com/questions/33723300/how-to-test-the-passing-of-arguments-in-golang
+			oldArgs := os.Args
+
+			defer func() {
+				t.Log("Restoring os.Args to original value")
+				os.Args = oldArgs
+			}()
+
+			os.Args = append([]string{"/usr/local/bin/check_imap_mailbox"}, testCase.args...)
+
+			cfg, err := New(testCase.appType)
+			switch {
+			case testCase.wantErr != nil:
+				if !errors.Is(err, testCase.wantErr) {
+					t.Fatalf("want error %v, got %v", testCase.wantErr, err)
+				}
+
+				return
+
+			case err != nil:
+				t.Fatalf("Error initializing application: %v", err)
+			}
+
+			if want, got := testCase.wantAccounts, len(cfg.Accounts); want != got {
+				t.Errorf("want %d accounts, got %d accounts", want, got)
+			}
+
+			for _, account := range cfg.Accounts {
+				if account.Name == "" || account.Username == "ignored" {
+					t.Errorf("want account from config file, got %+v", account.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"strings"
+)
+
+// SearchFilters is a custom type that satisfies the flag.Value interface in
+// order to accept multiple FIELD:VALUE search filter expressions such as
+// `from:tickets@example.com,subject:"Daily report, part 1"`. Expressions are
+// comma-separated; double-quoted values may contain commas.
+type SearchFilters []string
+
+// String returns a comma separated string consisting of all filter
+// expressions.
+func (sf *SearchFilters) String() string {
+
+	// From the `flag` package docs:
+	// "The flag package may call the String method with a zero-valued
+	// receiver, such as a nil pointer."
+	if sf == nil {
+		return ""
+	}
+
+	return strings.Join(*sf, ", ")
+}
+
+// Set is called once by the flag package, in command line order, for each
+// flag present.
+func (sf *SearchFilters) Set(value string) error {
+	*sf = append(*sf, splitSearchFilters(value)...)
+
+	return nil
+}
+
+// splitSearchFilters splits a comma-separated list of filter expressions.
+// Commas within double quotes do not separate expressions. Double quotes are
+// removed and surrounding whitespace is discarded for each expression.
+func splitSearchFilters(value string) []string {
+	var filters []string
+	var current strings.Builder
+	var quoted bool
+
+	flush := func() {
+		if expr := strings.TrimSpace(current.String()); expr != "" {
+			filters = append(filters, expr)
+		}
+		current.Reset()
+	}
+
+	for _, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return filters
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSearchFiltersSet asserts that comma-separated filter expressions are
+// split, with commas in double-quoted values retained.
+func TestSearchFiltersSet(t *testing.T) {
+	t.Parallel()
+
+	var filters SearchFilters
+	if err := filters.Set(`from:tickets@example.com, subject:"Daily report, part 1"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := filters.Set(`larger:1M`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := SearchFilters{
+		"from:tickets@example.com",
+		"subject:Daily report, part 1",
+		"larger:1M",
+	}
+
+	if d := cmp.Diff(want, filters); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+}
+
+// TestValidateMessageCriteriaSearchFilters asserts that invalid search
+// filters are rejected.
+func TestValidateMessageCriteriaSearchFilters(t *testing.T) {
+	t.Parallel()
+
+	valid := MailAccount{
+		Criteria:       multiValueFlag{"unseen"},
+		IncludeFilters: SearchFilters{"from:alerts@example.com"},
+		ExcludeFilters: SearchFilters{"header:X-Ticket-System=yes"},
+	}
+	if err := validateMessageCriteria(valid); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+
+	invalid := MailAccount{
+		ExcludeFilters: SearchFilters{"sender:tickets@example.com"},
+	}
+	if err := validateMessageCriteria(invalid); err == nil {
+		t.Error("want error for unsupported filter field, got nil")
+	}
+}
@@ -38,9 +38,15 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 	if appType.ReporterIMAPMailbox {
 		c.flagSet.StringVar(&c.minTLSVersion, "min-tls", defaultMinTLSVersion, minTLSVersionFlagHelp)
 		c.flagSet.StringVar(&c.NetworkType, "net-type", defaultNetworkType, networkTypeFlagHelp)
+		c.flagSet.IntVar(&c.Retries, RetriesFlag, defaultRetries, retriesFlagHelp)
+		c.flagSet.DurationVar(&c.RetryDelay, RetryDelayFlag, defaultRetryDelay, retryDelayFlagHelp)
 		c.flagSet.StringVar(&c.ConfigFile, "config-file", defaultINIConfigFileName, iniConfigFileFlagHelp)
 		c.flagSet.StringVar(&c.ReportFileOutputDir, "report-file-dir", defaultReportFileOutputDir, reportFileOutputDirFlagHelp)
 		c.flagSet.StringVar(&c.LogFileOutputDir, "log-file-dir", defaultLogFileOutputDir, logFileOutputDirFlagHelp)
+		c.flagSet.IntVar(&c.FetchBatchSize, FetchBatchSizeFlag, defaultFetchBatchSize, fetchBatchSizeFlagHelp)
+		c.flagSet.IntVar(&c.MaxMessagesPerFolder, MaxMessagesFlag, defaultMaxMessages, maxMessagesFlagHelp)
+		c.flagSet.BoolVar(&c.Delta, DeltaFlag, defaultDelta, reportDeltaFlagHelp)
+		c.flagSet.StringVar(&c.StateDir, StateDirFlag, defaultReportStateDir, reportStateDirFlagHelp)
 	}
 
 	if appType.InspectorIMAPCaps {
@@ -55,13 +61,21 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.ClientID, "client-id", defaultClientID, clientIDFlagHelp)
 		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.ClientSecret, "client-secret", defaultClientSecret, clientSecretFlagHelp)
 		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.TokenURL, "token-url", defaultTokenURL, tokenURLFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.Provider, "provider", defaultProvider, providerFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.TenantID, "tenant-id", defaultTenantID, tenantIDFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.IssuerURL, "issuer-url", defaultIssuerURL, issuerURLFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.GrantType, "grant-type", defaultGrantType, grantTypeFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.ServiceAccountKeyFile, "service-account-key", defaultServiceAccountKeyFile, serviceAccountKeyFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.Subject, "subject", defaultSubject, impersonateSubjectFlagHelp)
 		c.flagSet.BoolVar(&c.FetcherOAuth2TokenSettings.EmitTokenAsJSON, "json-output", defaultEmitTokenAsJSON, emitTokenAsJSONFlagHelp)
 		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.Filename, "filename", defaultTokenFilename, tokenFilenameFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.EncryptionKey, "encryption-key", defaultTokenEncryptionKey, tokenEncryptionKeyFlagHelp)
 		c.flagSet.IntVar(&c.FetcherOAuth2TokenSettings.RetrievalAttempts, "max-attempts", defaultTokenRetrievalAttempts, tokenRetrievalAttemptsFlagHelp)
 	}
 
 	if appType.FetcherOAuth2TokenFromCache {
 		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.Filename, "filename", defaultTokenFilename, tokenFilenameFlagHelp)
+		c.flagSet.StringVar(&c.FetcherOAuth2TokenSettings.EncryptionKey, "encryption-key", defaultTokenEncryptionKey, tokenEncryptionKeyFlagHelp)
 	}
 
 	if appType.PluginIMAPMailboxBasicAuth {
@@ -71,6 +85,11 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		account.AuthType = AuthTypeBasic
 
 		c.flagSet.Var(&account.Folders, "folders", foldersFlagHelp)
+		c.flagSet.BoolVar(&account.RecurseFolders, "recurse-folders", defaultRecurseFolders, recurseFoldersFlagHelp)
+		c.flagSet.Var(&account.ExcludeFolders, "exclude-folders", excludeFoldersFlagHelp)
+		c.flagSet.Var(&account.Criteria, "criteria", criteriaFlagHelp)
+		c.flagSet.Var(&account.IncludeFilters, "include", includeFlagHelp)
+		c.flagSet.Var(&account.ExcludeFilters, "exclude", excludeFlagHelp)
 		c.flagSet.StringVar(&account.Username, "username", defaultUsername, usernameFlagHelp)
 		c.flagSet.StringVar(&account.Password, "password", defaultPassword, passwordFlagHelp)
 		c.flagSet.StringVar(&account.Server, "server", defaultServer, serverFlagHelp)
@@ -78,6 +97,38 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		c.flagSet.BoolVar(&c.EmitBranding, "branding", defaultEmitBranding, emitBrandingFlagHelp)
 		c.flagSet.StringVar(&c.minTLSVersion, "min-tls", defaultMinTLSVersion, minTLSVersionFlagHelp)
 		c.flagSet.StringVar(&c.NetworkType, "net-type", defaultNetworkType, networkTypeFlagHelp)
+		c.flagSet.IntVar(&c.Retries, RetriesFlag, defaultRetries, retriesFlagHelp)
+		c.flagSet.DurationVar(&c.RetryDelay, RetryDelayFlag, defaultRetryDelay, retryDelayFlagHelp)
+		c.flagSet.DurationVar(&c.Timeout, TimeoutFlag, defaultTimeout, timeoutFlagHelp)
+
+		// Threshold flags
+		c.flagSet.StringVar(&c.WarningThreshold, WarningThresholdFlagShort, defaultWarningThreshold, warningThresholdFlagHelp+shorthandFlagSuffix)
+		c.flagSet.StringVar(&c.WarningThreshold, WarningThresholdFlagLong, defaultWarningThreshold, warningThresholdFlagHelp)
+		c.flagSet.StringVar(&c.CriticalThreshold, CriticalThresholdFlagShort, defaultCriticalThreshold, criticalThresholdFlagHelp+shorthandFlagSuffix)
+		c.flagSet.StringVar(&c.CriticalThreshold, CriticalThresholdFlagLong, defaultCriticalThreshold, criticalThresholdFlagHelp)
+		c.flagSet.Var(&c.FolderThresholds, FolderThresholdsFlag, folderThresholdsFlagHelp)
+		c.flagSet.DurationVar(&c.AgeWarningThreshold, AgeWarningFlag, defaultAgeWarningThreshold, ageWarningFlagHelp)
+		c.flagSet.DurationVar(&c.AgeCriticalThreshold, AgeCriticalFlag, defaultAgeCriticalThreshold, ageCriticalFlagHelp)
+		c.flagSet.BoolVar(&c.ExpectMail, ExpectMailFlag, defaultExpectMail, expectMailFlagHelp)
+		c.flagSet.DurationVar(&c.NewestAgeWarningThreshold, NewestAgeWarningFlag, defaultNewestAgeWarning, newestAgeWarningFlagHelp)
+		c.flagSet.DurationVar(&c.NewestAgeCriticalThreshold, NewestAgeCriticalFlag, defaultNewestAgeCritical, newestAgeCriticalFlagHelp)
+		c.flagSet.DurationVar(&c.MessageWindow, MessageWindowFlag, defaultMessageWindow, messageWindowFlagHelp)
+		c.flagSet.StringVar(&c.MissingFolderState, MissingFolderStateFlag, defaultMissingFolderState, missingFolderStateFlagHelp)
+		c.flagSet.IntVar(&c.FetchBatchSize, FetchBatchSizeFlag, defaultFetchBatchSize, fetchBatchSizeFlagHelp)
+		c.flagSet.StringVar(&c.ConfigFile, "config-file", defaultAccountsFile, accountsFileFlagHelp)
+		c.flagSet.IntVar(&c.ListMessages, ListMessagesFlag, defaultListMessages, listMessagesFlagHelp)
+		c.flagSet.StringVar(&c.ListOrder, ListOrderFlag, defaultListOrder, listOrderFlagHelp)
+		c.flagSet.StringVar(&c.ListFormat, ListFormatFlag, defaultListFormat, listFormatFlagHelp)
+		c.flagSet.BoolVar(&c.Delta, DeltaFlag, defaultDelta, deltaFlagHelp)
+		c.flagSet.StringVar(&c.StateDir, StateDirFlag, defaultStateDir, stateDirFlagHelp)
+		c.flagSet.BoolVar(&c.Rates, RatesFlag, defaultRates, ratesFlagHelp)
+		c.flagSet.Var(&c.RateWindows, RateWindowsFlag, rateWindowsFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.ArrivalWarning, ArrivalRateWarningFlag, defaultRateThreshold, arrivalRateWarningFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.ArrivalCritical, ArrivalRateCriticalFlag, defaultRateThreshold, arrivalRateCriticalFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.DrainWarning, DrainRateWarningFlag, defaultRateThreshold, drainRateWarningFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.DrainCritical, DrainRateCriticalFlag, defaultRateThreshold, drainRateCriticalFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.GrowthWarning, GrowthRateWarningFlag, defaultRateThreshold, growthRateWarningFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.GrowthCritical, GrowthRateCriticalFlag, defaultRateThreshold, growthRateCriticalFlagHelp)
 	}
 
 	if appType.PluginIMAPMailboxOAuth2 {
@@ -88,11 +139,48 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 
 		// Common plugin flags
 		c.flagSet.Var(&account.Folders, "folders", foldersFlagHelp)
+		c.flagSet.BoolVar(&account.RecurseFolders, "recurse-folders", defaultRecurseFolders, recurseFoldersFlagHelp)
+		c.flagSet.Var(&account.ExcludeFolders, "exclude-folders", excludeFoldersFlagHelp)
+		c.flagSet.Var(&account.Criteria, "criteria", criteriaFlagHelp)
+		c.flagSet.Var(&account.IncludeFilters, "include", includeFlagHelp)
+		c.flagSet.Var(&account.ExcludeFilters, "exclude", excludeFlagHelp)
 		c.flagSet.StringVar(&account.Server, "server", defaultServer, serverFlagHelp)
 		c.flagSet.IntVar(&account.Port, "port", defaultPort, portFlagHelp)
 		c.flagSet.BoolVar(&c.EmitBranding, "branding", defaultEmitBranding, emitBrandingFlagHelp)
 		c.flagSet.StringVar(&c.minTLSVersion, "min-tls", defaultMinTLSVersion, minTLSVersionFlagHelp)
 		c.flagSet.StringVar(&c.NetworkType, "net-type", defaultNetworkType, networkTypeFlagHelp)
+		c.flagSet.IntVar(&c.Retries, RetriesFlag, defaultRetries, retriesFlagHelp)
+		c.flagSet.DurationVar(&c.RetryDelay, RetryDelayFlag, defaultRetryDelay, retryDelayFlagHelp)
+		c.flagSet.DurationVar(&c.Timeout, TimeoutFlag, defaultTimeout, timeoutFlagHelp)
+
+		// Threshold flags
+		c.flagSet.StringVar(&c.WarningThreshold, WarningThresholdFlagShort, defaultWarningThreshold, warningThresholdFlagHelp+shorthandFlagSuffix)
+		c.flagSet.StringVar(&c.WarningThreshold, WarningThresholdFlagLong, defaultWarningThreshold, warningThresholdFlagHelp)
+		c.flagSet.StringVar(&c.CriticalThreshold, CriticalThresholdFlagShort, defaultCriticalThreshold, criticalThresholdFlagHelp+shorthandFlagSuffix)
+		c.flagSet.StringVar(&c.CriticalThreshold, CriticalThresholdFlagLong, defaultCriticalThreshold, criticalThresholdFlagHelp)
+		c.flagSet.Var(&c.FolderThresholds, FolderThresholdsFlag, folderThresholdsFlagHelp)
+		c.flagSet.DurationVar(&c.AgeWarningThreshold, AgeWarningFlag, defaultAgeWarningThreshold, ageWarningFlagHelp)
+		c.flagSet.DurationVar(&c.AgeCriticalThreshold, AgeCriticalFlag, defaultAgeCriticalThreshold, ageCriticalFlagHelp)
+		c.flagSet.BoolVar(&c.ExpectMail, ExpectMailFlag, defaultExpectMail, expectMailFlagHelp)
+		c.flagSet.DurationVar(&c.NewestAgeWarningThreshold, NewestAgeWarningFlag, defaultNewestAgeWarning, newestAgeWarningFlagHelp)
+		c.flagSet.DurationVar(&c.NewestAgeCriticalThreshold, NewestAgeCriticalFlag, defaultNewestAgeCritical, newestAgeCriticalFlagHelp)
+		c.flagSet.DurationVar(&c.MessageWindow, MessageWindowFlag, defaultMessageWindow, messageWindowFlagHelp)
+		c.flagSet.StringVar(&c.MissingFolderState, MissingFolderStateFlag, defaultMissingFolderState, missingFolderStateFlagHelp)
+		c.flagSet.IntVar(&c.FetchBatchSize, FetchBatchSizeFlag, defaultFetchBatchSize, fetchBatchSizeFlagHelp)
+		c.flagSet.StringVar(&c.ConfigFile, "config-file", defaultAccountsFile, accountsFileFlagHelp)
+		c.flagSet.IntVar(&c.ListMessages, ListMessagesFlag, defaultListMessages, listMessagesFlagHelp)
+		c.flagSet.StringVar(&c.ListOrder, ListOrderFlag, defaultListOrder, listOrderFlagHelp)
+		c.flagSet.StringVar(&c.ListFormat, ListFormatFlag, defaultListFormat, listFormatFlagHelp)
+		c.flagSet.BoolVar(&c.Delta, DeltaFlag, defaultDelta, deltaFlagHelp)
+		c.flagSet.StringVar(&c.StateDir, StateDirFlag, defaultStateDir, stateDirFlagHelp)
+		c.flagSet.BoolVar(&c.Rates, RatesFlag, defaultRates, ratesFlagHelp)
+		c.flagSet.Var(&c.RateWindows, RateWindowsFlag, rateWindowsFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.ArrivalWarning, ArrivalRateWarningFlag, defaultRateThreshold, arrivalRateWarningFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.ArrivalCritical, ArrivalRateCriticalFlag, defaultRateThreshold, arrivalRateCriticalFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.DrainWarning, DrainRateWarningFlag, defaultRateThreshold, drainRateWarningFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.DrainCritical, DrainRateCriticalFlag, defaultRateThreshold, drainRateCriticalFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.GrowthWarning, GrowthRateWarningFlag, defaultRateThreshold, growthRateWarningFlagHelp)
+		c.flagSet.StringVar(&c.RateThresholds.GrowthCritical, GrowthRateCriticalFlag, defaultRateThreshold, growthRateCriticalFlagHelp)
 
 		// OAuth2 flags
 		c.flagSet.Var(&account.OAuth2Settings.Scopes, "scopes", scopesFlagHelp)
@@ -100,7 +188,46 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		c.flagSet.StringVar(&account.OAuth2Settings.ClientSecret, "client-secret", defaultClientSecret, clientSecretFlagHelp)
 		c.flagSet.StringVar(&account.OAuth2Settings.SharedMailbox, "shared-mailbox", defaultSharedMailbox, sharedMailboxFlagHelp)
 		c.flagSet.StringVar(&account.OAuth2Settings.TokenURL, "token-url", defaultTokenURL, tokenURLFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.Provider, "provider", defaultProvider, providerFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.TenantID, "tenant-id", defaultTenantID, tenantIDFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.IssuerURL, "issuer-url", defaultIssuerURL, issuerURLFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.GrantType, "grant-type", defaultGrantType, grantTypeFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.ServiceAccountKeyFile, "service-account-key", defaultServiceAccountKeyFile, serviceAccountKeyFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.Subject, "subject", defaultSubject, impersonateSubjectFlagHelp)
+
+	}
+
+	if appType.PluginIMAPQuota {
+		c.flagSet.StringVar(&account.AuthType, "auth-type", defaultAuthType, authTypeFlagHelp)
+		c.flagSet.StringVar(&account.Username, "username", defaultUsername, usernameFlagHelp)
+		c.flagSet.StringVar(&account.Password, "password", defaultPassword, passwordFlagHelp)
+		c.flagSet.StringVar(&account.Server, "server", defaultServer, serverFlagHelp)
+		c.flagSet.IntVar(&account.Port, "port", defaultPort, portFlagHelp)
+		c.flagSet.BoolVar(&c.EmitBranding, "branding", defaultEmitBranding, emitBrandingFlagHelp)
+		c.flagSet.StringVar(&c.minTLSVersion, "min-tls", defaultMinTLSVersion, minTLSVersionFlagHelp)
+		c.flagSet.StringVar(&c.NetworkType, "net-type", defaultNetworkType, networkTypeFlagHelp)
+		c.flagSet.IntVar(&c.Retries, RetriesFlag, defaultRetries, retriesFlagHelp)
+		c.flagSet.DurationVar(&c.RetryDelay, RetryDelayFlag, defaultRetryDelay, retryDelayFlagHelp)
+		c.flagSet.DurationVar(&c.Timeout, TimeoutFlag, defaultTimeout, timeoutFlagHelp)
+
+		// Threshold flags
+		c.flagSet.IntVar(&c.QuotaWarningThreshold, WarningThresholdFlagShort, defaultQuotaWarning, quotaWarningThresholdFlagHelp+shorthandFlagSuffix)
+		c.flagSet.IntVar(&c.QuotaWarningThreshold, WarningThresholdFlagLong, defaultQuotaWarning, quotaWarningThresholdFlagHelp)
+		c.flagSet.IntVar(&c.QuotaCriticalThreshold, CriticalThresholdFlagShort, defaultQuotaCritical, quotaCriticalThresholdFlagHelp+shorthandFlagSuffix)
+		c.flagSet.IntVar(&c.QuotaCriticalThreshold, CriticalThresholdFlagLong, defaultQuotaCritical, quotaCriticalThresholdFlagHelp)
 
+		// OAuth2 flags
+		c.flagSet.Var(&account.OAuth2Settings.Scopes, "scopes", scopesFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.ClientID, "client-id", defaultClientID, clientIDFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.ClientSecret, "client-secret", defaultClientSecret, clientSecretFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.SharedMailbox, "shared-mailbox", defaultSharedMailbox, sharedMailboxFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.TokenURL, "token-url", defaultTokenURL, tokenURLFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.Provider, "provider", defaultProvider, providerFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.TenantID, "tenant-id", defaultTenantID, tenantIDFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.IssuerURL, "issuer-url", defaultIssuerURL, issuerURLFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.GrantType, "grant-type", defaultGrantType, grantTypeFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.ServiceAccountKeyFile, "service-account-key", defaultServiceAccountKeyFile, serviceAccountKeyFlagHelp)
+		c.flagSet.StringVar(&account.OAuth2Settings.Subject, "subject", defaultSubject, impersonateSubjectFlagHelp)
 	}
 
 	// Allow our function to override the default Help output.
@@ -115,10 +242,16 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		return err
 	}
 
+	// Fill in token URL and scopes for any specified OAuth2 provider preset
+	// before validation is performed.
+	account.OAuth2Settings.applyProviderPresets()
+	c.FetcherOAuth2TokenSettings.applyProviderPresets()
+
 	// For all app types other than the Reporter app we need to save any
 	// configured account details provided via CLI; the Reporter app receives
-	// all account details via configuration file.
-	if !appType.ReporterIMAPMailbox {
+	// all account details via configuration file. The mailbox plugins
+	// optionally do the same.
+	if !appType.ReporterIMAPMailbox && !c.accountsFromFile(appType) {
 		c.Accounts = append(c.Accounts, account)
 	}
 
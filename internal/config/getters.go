@@ -9,8 +9,12 @@ package config
 
 import (
 	"crypto/tls"
+	"fmt"
 	"strings"
 	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/go-nagios"
 )
 
 // MinTLSVersion returns the applicable `tls.VersionTLS*` numeric constant
@@ -119,3 +123,141 @@ func (c Config) RetrievalAttempts() int {
 	}
 	return c.FetcherOAuth2TokenSettings.RetrievalAttempts
 }
+
+// UsesJWTBearerGrant indicates whether the OAuth2 JWT bearer grant (service
+// account key) is used to obtain a token instead of the Client Credentials
+// grant.
+func (o OAuth2ClientCredentialsFlow) UsesJWTBearerGrant() bool {
+	return strings.ToLower(o.GrantType) == OAuth2GrantTypeJWTBearer
+}
+
+// ImpersonatedSubject returns the user impersonated by a service account
+// when using the JWT bearer grant. The SharedMailbox value is returned if a
+// subject was not explicitly specified.
+func (o OAuth2ClientCredentialsFlow) ImpersonatedSubject() string {
+	if o.Subject != "" {
+		return o.Subject
+	}
+
+	return o.SharedMailbox
+}
+
+// TotalThresholds returns the Nagios range strings applied to the total
+// number of messages found. If no message count, message age or message rate
+// thresholds were specified a WARNING threshold is returned which is exceeded
+// if any messages are found.
+//
+// In expect mail mode a CRITICAL threshold which is exceeded if no messages
+// are found is returned if no message count thresholds were specified.
+func (c Config) TotalThresholds() (warning string, critical string) {
+	if c.ExpectMail {
+		if c.WarningThreshold == "" && c.CriticalThreshold == "" {
+			return "", expectMailCriticalThreshold
+		}
+
+		return c.WarningThreshold, c.CriticalThreshold
+	}
+
+	if c.WarningThreshold == "" &&
+		c.CriticalThreshold == "" &&
+		len(c.FolderThresholds) == 0 &&
+		c.AgeWarningThreshold <= 0 &&
+		c.AgeCriticalThreshold <= 0 &&
+		!c.RateThresholds.IsSet() {
+		return legacyWarningThreshold, ""
+	}
+
+	return c.WarningThreshold, c.CriticalThreshold
+}
+
+// FetchMessageDetails indicates whether message details (e.g., envelope and
+// arrival date) are required to evaluate the specified thresholds or to list
+// messages. If not, the Nagios plugins only retrieve message counts.
+func (c Config) FetchMessageDetails() bool {
+	return c.AgeWarningThreshold > 0 ||
+		c.AgeCriticalThreshold > 0 ||
+		c.ExpectMail ||
+		c.ListMessages > 0
+}
+
+// ListOldestFirst indicates whether messages listed in the extended plugin
+// output are ordered oldest first instead of newest first.
+func (c Config) ListOldestFirst() bool {
+	return strings.EqualFold(c.ListOrder, ListOrderOldest)
+}
+
+// ListHTML indicates whether messages listed in the extended plugin output
+// are formatted as HTML for display in the Nagios web UI.
+func (c Config) ListHTML() bool {
+	return strings.EqualFold(c.ListFormat, ListFormatHTML)
+}
+
+// MissingFolderExitCode returns the Nagios state exit code used when a
+// specified folder is not found. A CRITICAL state is used if not specified.
+func (c Config) MissingFolderExitCode() int {
+	switch strings.ToLower(c.MissingFolderState) {
+	case stateKeywordOK:
+		return nagios.StateOKExitCode
+	case stateKeywordWarning:
+		return nagios.StateWARNINGExitCode
+	case stateKeywordUnknown:
+		return nagios.StateUNKNOWNExitCode
+	default:
+		return nagios.StateCRITICALExitCode
+	}
+}
+
+// RetryPolicy returns the policy used to retry the connect, login and mail
+// check sequence after a transient error.
+func (c Config) RetryPolicy() mbxs.RetryPolicy {
+	return mbxs.RetryPolicy{
+		MaxRetries: c.Retries,
+		Delay:      c.RetryDelay,
+	}
+}
+
+// FolderSelection returns the folders, folder patterns, recursion setting and
+// folder exclusions used to determine which folders are checked for the
+// account.
+func (ma MailAccount) FolderSelection() mbxs.FolderSelection {
+	return mbxs.FolderSelection{
+		Folders: ma.Folders,
+		Recurse: ma.RecurseFolders,
+		Exclude: ma.ExcludeFolders,
+	}
+}
+
+// MessageFilter returns the message criteria and search filters used to
+// limit the messages counted and listed for the account.
+func (ma MailAccount) MessageFilter() mbxs.MessageFilter {
+	return mbxs.MessageFilter{
+		Criteria: ma.Criteria,
+		Include:  ma.IncludeFilters,
+		Exclude:  ma.ExcludeFilters,
+	}
+}
+
+// StateScope returns a description of the folders and message filter
+// checked for the account. This is used to keep the state recorded by
+// checks of the same account using different folders or filters separate.
+func (ma MailAccount) StateScope() string {
+	return fmt.Sprintf(
+		"folders=%q;recurse=%t;exclude-folders=%q;criteria=%q;include=%q;exclude=%q",
+		[]string(ma.Folders),
+		ma.RecurseFolders,
+		[]string(ma.ExcludeFolders),
+		[]string(ma.Criteria),
+		[]string(ma.IncludeFilters),
+		[]string(ma.ExcludeFilters),
+	)
+}
+
+// Mailbox returns the mailbox logged into for the account; the username if
+// Basic Authentication is used or the shared mailbox if OAuth2 is used.
+func (ma MailAccount) Mailbox() string {
+	if ma.AuthType == AuthTypeOAuth2ClientCreds {
+		return ma.OAuth2Settings.SharedMailbox
+	}
+
+	return ma.Username
+}
@@ -179,7 +179,7 @@ func (c *Config) setupLogging(appType AppType) error {
 			Str("min_tls_version", c.MinTLSVersionKeyword()).
 			Logger()
 
-	case appType.PluginIMAPMailboxOAuth2:
+	case appType.PluginIMAPMailboxOAuth2, appType.PluginIMAPQuota:
 
 		// Whatever output meant for consumption is emitted to stdout and
 		// whatever is meant for troubleshooting is sent to stderr. To help
@@ -0,0 +1,120 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/check-mail/internal/oauth2"
+)
+
+// SupportedOAuth2Providers returns the complete list of supported OAuth2
+// provider presets.
+func (c Config) SupportedOAuth2Providers() []string {
+	return []string{
+		OAuth2ProviderOffice365,
+		OAuth2ProviderGoogle,
+		OAuth2ProviderGenericOIDC,
+	}
+}
+
+// applyProviderPresets fills in the TokenURL and Scopes values using
+// defaults for the specified OAuth2 provider. Explicitly specified TokenURL
+// and Scopes values are left as-is. Unknown provider keywords are ignored
+// here; validation is responsible for reporting those.
+//
+// The generic-oidc provider requires a network request to determine the
+// token endpoint URL and is handled separately by resolveProviderEndpoints.
+func (o *OAuth2ClientCredentialsFlow) applyProviderPresets() {
+
+	switch strings.ToLower(o.Provider) {
+	case OAuth2ProviderOffice365:
+		if o.TokenURL == "" && o.TenantID != "" {
+			o.TokenURL = fmt.Sprintf(oauth2Office365TokenURLTemplate, o.TenantID)
+		}
+
+		if len(o.Scopes) == 0 {
+			o.Scopes = multiValueFlag{oauth2Office365DefaultScope}
+		}
+
+	case OAuth2ProviderGoogle:
+		if o.TokenURL == "" {
+			o.TokenURL = oauth2GoogleTokenURL
+		}
+
+		if len(o.Scopes) == 0 {
+			o.Scopes = multiValueFlag{oauth2GoogleDefaultScope}
+		}
+	}
+}
+
+// resolveProviderEndpoints uses OpenID Connect discovery to fill in the
+// TokenURL value for the generic-oidc provider if not already explicitly
+// specified. This is a no-op for all other providers.
+func (o *OAuth2ClientCredentialsFlow) resolveProviderEndpoints(ctx context.Context) error {
+
+	if strings.ToLower(o.Provider) != OAuth2ProviderGenericOIDC || o.TokenURL != "" {
+		return nil
+	}
+
+	endpoints, err := oauth2.DiscoverProviderEndpoints(ctx, o.IssuerURL)
+	if err != nil {
+		return err
+	}
+
+	o.TokenURL = endpoints.TokenURL
+
+	return nil
+}
+
+// resolveOAuth2Providers applies OAuth2 provider settings for all configured
+// accounts and for the token fetcher settings. Any error encountered while
+// resolving provider endpoints is returned.
+func (c *Config) resolveOAuth2Providers() error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOIDCDiscoveryTimeout)
+	defer cancel()
+
+	for i := range c.Accounts {
+		if c.Accounts[i].AuthType != AuthTypeOAuth2ClientCreds {
+			continue
+		}
+
+		settings := &c.Accounts[i].OAuth2Settings
+		if err := settings.resolveProviderEndpoints(ctx); err != nil {
+			return fmt.Errorf(
+				"failed to resolve %s provider endpoints for account %s: %w",
+				settings.Provider,
+				c.Accounts[i].Name,
+				err,
+			)
+		}
+
+		if settings.Provider != "" {
+			c.Log.Debug().
+				Str("account", c.Accounts[i].Name).
+				Str("provider", settings.Provider).
+				Str("token_url", settings.TokenURL).
+				Str("scopes", settings.Scopes.String()).
+				Msg("Applied OAuth2 provider settings")
+		}
+	}
+
+	settings := &c.FetcherOAuth2TokenSettings.OAuth2ClientCredentialsFlow
+	if err := settings.resolveProviderEndpoints(ctx); err != nil {
+		return fmt.Errorf(
+			"failed to resolve %s provider endpoints: %w",
+			settings.Provider,
+			err,
+		)
+	}
+
+	return nil
+}
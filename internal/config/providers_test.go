@@ -0,0 +1,176 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestApplyProviderPresets asserts that token URL and scopes values are
+// filled in for supported providers without overriding explicitly specified
+// values.
+func TestApplyProviderPresets(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input OAuth2ClientCredentialsFlow
+		want  OAuth2ClientCredentialsFlow
+	}{
+		"office365 with tenant ID": {
+			input: OAuth2ClientCredentialsFlow{
+				Provider: OAuth2ProviderOffice365,
+				TenantID: "6029c1d9-aa2f-4227-8f7c-0c23224a0fa9",
+			},
+			want: OAuth2ClientCredentialsFlow{
+				Provider: OAuth2ProviderOffice365,
+				TenantID: "6029c1d9-aa2f-4227-8f7c-0c23224a0fa9",
+				TokenURL: "https://login.microsoftonline.com/6029c1d9-aa2f-4227-8f7c-0c23224a0fa9/oauth2/v2.0/token",
+				Scopes:   multiValueFlag{oauth2Office365DefaultScope},
+			},
+		},
+		"office365 without tenant ID": {
+			input: OAuth2ClientCredentialsFlow{
+				Provider: OAuth2ProviderOffice365,
+			},
+			want: OAuth2ClientCredentialsFlow{
+				Provider: OAuth2ProviderOffice365,
+				Scopes:   multiValueFlag{oauth2Office365DefaultScope},
+			},
+		},
+		"google with explicit scopes": {
+			input: OAuth2ClientCredentialsFlow{
+				Provider: OAuth2ProviderGoogle,
+				Scopes:   multiValueFlag{"https://example.com/custom"},
+			},
+			want: OAuth2ClientCredentialsFlow{
+				Provider: OAuth2ProviderGoogle,
+				TokenURL: oauth2GoogleTokenURL,
+				Scopes:   multiValueFlag{"https://example.com/custom"},
+			},
+		},
+		"generic-oidc is not resolved statically": {
+			input: OAuth2ClientCredentialsFlow{
+				Provider:  OAuth2ProviderGenericOIDC,
+				IssuerURL: "https://idp.example.com",
+			},
+			want: OAuth2ClientCredentialsFlow{
+				Provider:  OAuth2ProviderGenericOIDC,
+				IssuerURL: "https://idp.example.com",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.input
+			got.applyProviderPresets()
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("(-want, +got)\n:%s", d)
+			}
+		})
+	}
+}
+
+// TestResolveProviderEndpointsGenericOIDC asserts that the token URL for the
+// generic-oidc provider is discovered from the issuer's OpenID Provider
+// Configuration document.
+func TestResolveProviderEndpointsGenericOIDC(t *testing.T) {
+	t.Parallel()
+
+	var issuer string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprintf(
+			w,
+			`{"issuer": %q, "token_endpoint": %q}`,
+			issuer,
+			issuer+"/protocol/openid-connect/token",
+		)
+	}))
+	t.Cleanup(srv.Close)
+	issuer = srv.URL
+
+	settings := OAuth2ClientCredentialsFlow{
+		Provider:  OAuth2ProviderGenericOIDC,
+		IssuerURL: issuer + "/",
+	}
+
+	if err := settings.resolveProviderEndpoints(context.Background()); err != nil {
+		t.Fatalf("failed to resolve provider endpoints: %v", err)
+	}
+
+	if want, got := issuer+"/protocol/openid-connect/token", settings.TokenURL; want != got {
+		t.Errorf("\nwant token URL %q\ngot token URL %q", want, got)
+	}
+}
+
+// TestValidateOAuth2Provider asserts that provider-specific settings are
+// required.
+func TestValidateOAuth2Provider(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input   OAuth2ClientCredentialsFlow
+		wantErr bool
+	}{
+		"no provider": {
+			input: OAuth2ClientCredentialsFlow{},
+		},
+		"unknown provider": {
+			input:   OAuth2ClientCredentialsFlow{Provider: "example"},
+			wantErr: true,
+		},
+		"office365 missing tenant ID": {
+			input:   OAuth2ClientCredentialsFlow{Provider: OAuth2ProviderOffice365},
+			wantErr: true,
+		},
+		"generic-oidc missing issuer": {
+			input:   OAuth2ClientCredentialsFlow{Provider: OAuth2ProviderGenericOIDC},
+			wantErr: true,
+		},
+		"generic-oidc plaintext issuer": {
+			input: OAuth2ClientCredentialsFlow{
+				Provider:  OAuth2ProviderGenericOIDC,
+				IssuerURL: "http://idp.example.com",
+			},
+			wantErr: true,
+		},
+		"generic-oidc with explicit token URL": {
+			input: OAuth2ClientCredentialsFlow{
+				Provider: OAuth2ProviderGenericOIDC,
+				TokenURL: "https://idp.example.com/token",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateOAuth2Provider(tt.input)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Error("want error, got nil")
+			case !tt.wantErr && err != nil:
+				t.Errorf("want no error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultRateWindow is the time window used to evaluate message arrival,
+// drain and growth rates if no windows are specified.
+const defaultRateWindow time.Duration = time.Hour
+
+// RateWindows is a custom type that satisfies the flag.Value interface in
+// order to accept one or more time windows (e.g., "1h,24h") over which
+// message arrival, drain and growth rates are evaluated.
+type RateWindows []time.Duration
+
+// String returns a comma separated string consisting of all rate windows.
+func (rw *RateWindows) String() string {
+
+	// From the `flag` package docs:
+	// "The flag package may call the String method with a zero-valued
+	// receiver, such as a nil pointer."
+	if rw == nil {
+		return ""
+	}
+
+	windows := make([]string, 0, len(*rw))
+	for _, window := range *rw {
+		windows = append(windows, window.String())
+	}
+
+	return strings.Join(windows, ", ")
+}
+
+// Set is called once by the flag package, in command line order, for each
+// flag present.
+func (rw *RateWindows) Set(value string) error {
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		window, err := time.ParseDuration(entry)
+		if err != nil || window <= 0 {
+			return fmt.Errorf(
+				"invalid rate window %q; a positive duration (e.g., 1h) is required",
+				entry,
+			)
+		}
+
+		*rw = append(*rw, window)
+	}
+
+	return nil
+}
+
+// RateThresholds is the collection of Nagios range strings evaluated against
+// the rates (in messages per hour) at which messages arrive in, are removed
+// from (drain) and accumulate in (growth) each checked folder.
+type RateThresholds struct {
+	ArrivalWarning  string
+	ArrivalCritical string
+	DrainWarning    string
+	DrainCritical   string
+	GrowthWarning   string
+	GrowthCritical  string
+}
+
+// IsSet indicates whether any rate thresholds are specified.
+func (rt RateThresholds) IsSet() bool {
+	return rt != RateThresholds{}
+}
+
+// validateRateSettings asserts that valid rate thresholds and windows are
+// specified and that rate evaluation is enabled (along with a state
+// directory to record check history) if they are.
+func validateRateSettings(c Config) error {
+	ranges := []struct {
+		name  string
+		value string
+	}{
+		{name: ArrivalRateWarningFlag, value: c.RateThresholds.ArrivalWarning},
+		{name: ArrivalRateCriticalFlag, value: c.RateThresholds.ArrivalCritical},
+		{name: DrainRateWarningFlag, value: c.RateThresholds.DrainWarning},
+		{name: DrainRateCriticalFlag, value: c.RateThresholds.DrainCritical},
+		{name: GrowthRateWarningFlag, value: c.RateThresholds.GrowthWarning},
+		{name: GrowthRateCriticalFlag, value: c.RateThresholds.GrowthCritical},
+	}
+
+	for _, r := range ranges {
+		if err := validateThresholdRange(r.name, r.value); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case !c.Rates && (c.RateThresholds.IsSet() || len(c.RateWindows) > 0):
+		return fmt.Errorf(
+			"rate thresholds and windows require the %s flag",
+			RatesFlag,
+		)
+
+	case c.Rates && strings.TrimSpace(c.StateDir) == "":
+		return fmt.Errorf(
+			"%s value is required when %s is specified",
+			StateDirFlag,
+			RatesFlag,
+		)
+	}
+
+	return nil
+}
+
+// EvaluationRateWindows returns the time windows over which message arrival,
+// drain and growth rates are evaluated or the default window if none were
+// specified.
+func (c Config) EvaluationRateWindows() []time.Duration {
+	if len(c.RateWindows) == 0 {
+		return []time.Duration{defaultRateWindow}
+	}
+
+	return c.RateWindows
+}
+
+// RateHistoryRetention returns how long check history is retained for rate
+// evaluation; the longest evaluated time window.
+func (c Config) RateHistoryRetention() time.Duration {
+	var retention time.Duration
+	for _, window := range c.EvaluationRateWindows() {
+		retention = max(retention, window)
+	}
+
+	return retention
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// TestValidateRateSettings asserts that rate thresholds and windows are only
+// accepted if rate evaluation is enabled with a state directory.
+func TestValidateRateSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"defaults": {
+			cfg: Config{Rates: defaultRates},
+		},
+		"rates with state dir": {
+			cfg: Config{
+				Rates:          true,
+				StateDir:       "/var/lib/check-mail",
+				RateWindows:    RateWindows{time.Hour, 24 * time.Hour},
+				RateThresholds: RateThresholds{ArrivalWarning: "50", DrainWarning: "10:", GrowthCritical: "100"},
+			},
+		},
+		"rates without state dir": {
+			cfg:     Config{Rates: true},
+			wantErr: StateDirFlag,
+		},
+		"thresholds without rates": {
+			cfg:     Config{StateDir: "/var/lib/check-mail", RateThresholds: RateThresholds{ArrivalWarning: "50"}},
+			wantErr: RatesFlag,
+		},
+		"windows without rates": {
+			cfg:     Config{StateDir: "/var/lib/check-mail", RateWindows: RateWindows{time.Hour}},
+			wantErr: RatesFlag,
+		},
+		"invalid threshold": {
+			cfg:     Config{Rates: true, StateDir: "/var/lib/check-mail", RateThresholds: RateThresholds{GrowthWarning: "fifty"}},
+			wantErr: GrowthRateWarningFlag,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRateSettings(tt.cfg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("want no error, got %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("want error for %s flag, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestValidateRateSettingsInvalidRange asserts that invalid rate threshold
+// ranges are reported as invalid Nagios ranges.
+func TestValidateRateSettingsInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Rates:          true,
+		StateDir:       "/var/lib/check-mail",
+		RateThresholds: RateThresholds{DrainCritical: "ten"},
+	}
+
+	err := validateRateSettings(cfg)
+	if !errors.Is(err, nagios.ErrInvalidRangeThreshold) {
+		t.Errorf("want error %v, got %v", nagios.ErrInvalidRangeThreshold, err)
+	}
+}
+
+// TestRateWindowsSet asserts that comma separated rate windows are parsed
+// and that non-positive windows are rejected.
+func TestRateWindowsSet(t *testing.T) {
+	t.Parallel()
+
+	var windows RateWindows
+	if err := windows.Set("1h, 30m"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want, got := "1h0m0s, 30m0s", windows.String(); want != got {
+		t.Errorf("want windows %q, got %q", want, got)
+	}
+
+	for _, invalid := range []string{"0s", "-1h", "hourly"} {
+		err := new(RateWindows).Set("1h," + invalid)
+		if err == nil || !strings.Contains(err.Error(), `rate window "`+invalid+`"`) {
+			t.Errorf("want error for rate window %q, got %v", invalid, err)
+		}
+	}
+
+	cfg := Config{}
+	if want, got := time.Hour, cfg.RateHistoryRetention(); want != got {
+		t.Errorf("want default retention %v, got %v", want, got)
+	}
+
+	cfg.RateWindows = windows
+	if want, got := time.Hour, cfg.RateHistoryRetention(); want != got {
+		t.Errorf("want retention %v, got %v", want, got)
+	}
+}
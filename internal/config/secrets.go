@@ -0,0 +1,274 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Prefixes used to indicate that a secret value is a reference to be
+// resolved instead of a literal value.
+const (
+	secretRefPrefixFile       string = "file:"
+	secretRefPrefixEnv        string = "env:"
+	secretRefPrefixCredential string = "credential:"
+	secretRefPrefixCmd        string = "cmd:"
+	secretRefPrefixPlain      string = "plain:"
+)
+
+// systemdCredentialsDirEnvVar is the environment variable set by systemd
+// for services using LoadCredential= or SetCredential= directives.
+//
+// https://systemd.io/CREDENTIALS/
+const systemdCredentialsDirEnvVar string = "CREDENTIALS_DIRECTORY"
+
+var (
+	// ErrSecretReferenceUnresolved indicates that a reference to a secret
+	// value (e.g., file:, env:, credential: or cmd:) could not be resolved.
+	ErrSecretReferenceUnresolved = errors.New("failed to resolve secret reference")
+)
+
+// secretSource returns the keyword describing the source of the given
+// secret value. This is safe to log as it never includes the secret or the
+// reference target.
+func secretSource(value string) string {
+	switch {
+	case strings.HasPrefix(value, secretRefPrefixFile):
+		return "file"
+	case strings.HasPrefix(value, secretRefPrefixEnv):
+		return "env"
+	case strings.HasPrefix(value, secretRefPrefixCredential):
+		return "credential"
+	case strings.HasPrefix(value, secretRefPrefixCmd):
+		return "cmd"
+	default:
+		return "literal"
+	}
+}
+
+// resolveSecret resolves the given secret value. Values using one of the
+// supported reference prefixes are replaced with the value read from the
+// referenced file, environment variable, systemd credential or command
+// output. Values using the plain: prefix have the prefix removed and are
+// otherwise returned as-is. All other values are returned unmodified.
+//
+// Error messages never include the resolved secret value or command output.
+func resolveSecret(ctx context.Context, value string) (string, error) {
+	var resolved string
+
+	switch {
+	case strings.HasPrefix(value, secretRefPrefixPlain):
+		return strings.TrimPrefix(value, secretRefPrefixPlain), nil
+
+	case strings.HasPrefix(value, secretRefPrefixFile):
+		filename := strings.TrimPrefix(value, secretRefPrefixFile)
+		content, err := readSecretFile(filename)
+		if err != nil {
+			return "", err
+		}
+		resolved = content
+
+	case strings.HasPrefix(value, secretRefPrefixEnv):
+		name := strings.TrimPrefix(value, secretRefPrefixEnv)
+		envValue, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf(
+				"environment variable %q is not set: %w",
+				name,
+				ErrSecretReferenceUnresolved,
+			)
+		}
+		resolved = envValue
+
+	case strings.HasPrefix(value, secretRefPrefixCredential):
+		name := strings.TrimPrefix(value, secretRefPrefixCredential)
+		content, err := readSystemdCredential(name)
+		if err != nil {
+			return "", err
+		}
+		resolved = content
+
+	case strings.HasPrefix(value, secretRefPrefixCmd):
+		command := strings.TrimPrefix(value, secretRefPrefixCmd)
+		output, err := runSecretCommand(ctx, command)
+		if err != nil {
+			return "", err
+		}
+		resolved = output
+
+	default:
+		return value, nil
+	}
+
+	if resolved == "" {
+		return "", fmt.Errorf(
+			"%s reference resolved to an empty value: %w",
+			secretSource(value),
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	return resolved, nil
+}
+
+// readSecretFile reads the specified file and returns its content with any
+// trailing newline removed.
+func readSecretFile(filename string) (string, error) {
+	if filename == "" {
+		return "", fmt.Errorf(
+			"empty file path in file reference: %w",
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to read secret file %q: %v: %w",
+			filename,
+			err,
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// readSystemdCredential reads the named credential from the directory
+// provided by systemd via the CREDENTIALS_DIRECTORY environment variable.
+func readSystemdCredential(name string) (string, error) {
+	if name == "" || strings.ContainsRune(name, '/') || name == "." || name == ".." {
+		return "", fmt.Errorf(
+			"invalid credential name %q: %w",
+			name,
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	dir := os.Getenv(systemdCredentialsDirEnvVar)
+	if dir == "" {
+		return "", fmt.Errorf(
+			"credential %q requested but %s environment variable is not set"+
+				" (not running as a systemd service with credentials?): %w",
+			name,
+			systemdCredentialsDirEnvVar,
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	return readSecretFile(filepath.Join(dir, name))
+}
+
+// runSecretCommand executes the given command (without a shell) and returns
+// its standard output with any trailing newline removed. Surrounding quotes
+// around the full command are removed; arguments are separated by
+// whitespace. Quoting individual arguments is not supported and is rejected
+// so that arguments containing spaces are not silently split.
+func runSecretCommand(ctx context.Context, command string) (string, error) {
+	command = strings.TrimSpace(command)
+	if len(command) >= 2 && command[0] == '"' && command[len(command)-1] == '"' {
+		command = command[1 : len(command)-1]
+	}
+
+	if strings.ContainsAny(command, `"'`) {
+		return "", fmt.Errorf(
+			"quoted arguments are not supported in cmd reference"+
+				" (use a wrapper script for arguments or paths containing spaces): %w",
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf(
+			"empty command in cmd reference: %w",
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultSecretCommandTimeout)
+	defer cancel()
+
+	// #nosec G204 -- command is explicitly provided by the sysadmin
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf(
+			"failed to run secret command %q: %v: %w",
+			args[0],
+			err,
+			ErrSecretReferenceUnresolved,
+		)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// resolveSecrets resolves secret references for all configured accounts and
+// for the token fetcher settings (including the token encryption key). Only
+// the field name and reference type are logged; secret values are never
+// logged.
+func (c *Config) resolveSecrets() error {
+
+	ctx := context.Background()
+
+	resolve := func(account string, field string, value *string) error {
+		if *value == "" {
+			return nil
+		}
+
+		source := secretSource(*value)
+
+		resolved, err := resolveSecret(ctx, *value)
+		if err != nil {
+			if account != "" {
+				return fmt.Errorf("%s for account %s: %w", field, account, err)
+			}
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		*value = resolved
+
+		if source != "literal" {
+			c.Log.Debug().
+				Str("account", account).
+				Str("field", field).
+				Str("source", source).
+				Msg("Resolved secret reference")
+		}
+
+		return nil
+	}
+
+	for i := range c.Accounts {
+		account := &c.Accounts[i]
+
+		if err := resolve(account.Name, "password", &account.Password); err != nil {
+			return err
+		}
+
+		if err := resolve(account.Name, "client secret", &account.OAuth2Settings.ClientSecret); err != nil {
+			return err
+		}
+	}
+
+	if err := resolve("", "client secret", &c.FetcherOAuth2TokenSettings.ClientSecret); err != nil {
+		return err
+	}
+
+	return resolve("", "encryption key", &c.FetcherOAuth2TokenSettings.EncryptionKey)
+}
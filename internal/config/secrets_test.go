@@ -0,0 +1,115 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestResolveSecret asserts that secret references are replaced with the
+// referenced value and that literal values are returned unmodified.
+func TestResolveSecret(t *testing.T) {
+	dir := t.TempDir()
+
+	secretFile := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("failed to create secret file: %v", err)
+	}
+
+	credsDir := filepath.Join(dir, "credentials")
+	if err := os.Mkdir(credsDir, 0700); err != nil {
+		t.Fatalf("failed to create credentials directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(credsDir, "mail-password"), []byte("from-systemd"), 0600); err != nil {
+		t.Fatalf("failed to create credential file: %v", err)
+	}
+
+	t.Setenv("CHECK_MAIL_TEST_SECRET", "from-env")
+	t.Setenv(systemdCredentialsDirEnvVar, credsDir)
+
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"literal":           {input: "hunter2", want: "hunter2"},
+		"plain prefix":      {input: "plain:env:notareference", want: "env:notareference"},
+		"file reference":    {input: "file:" + secretFile, want: "from-file"},
+		"env reference":     {input: "env:CHECK_MAIL_TEST_SECRET", want: "from-env"},
+		"systemd reference": {input: "credential:mail-password", want: "from-systemd"},
+		"cmd reference":     {input: `cmd:"echo from-cmd"`, want: "from-cmd"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveSecret(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("failed to resolve secret: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestResolveSecretErrors asserts that unresolvable references are reported
+// without including secret values in the error message.
+func TestResolveSecretErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatalf("failed to create secret file: %v", err)
+	}
+
+	t.Setenv(systemdCredentialsDirEnvVar, "")
+
+	tests := map[string]string{
+		"missing file":           "file:" + filepath.Join(dir, "missing"),
+		"empty file":             "file:" + emptyFile,
+		"unset env var":          "env:CHECK_MAIL_TEST_SECRET_UNSET",
+		"no credentials dir":     "credential:mail-password",
+		"credential path":        "credential:../secret",
+		"empty command":          "cmd:",
+		"failing command":        "cmd:false",
+		"command without output": "cmd:true",
+		"quoted argument":        `cmd:pass show "mail/intake box"`,
+		"single quoted argument": `cmd:"pass show 'mail/intake box'"`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := resolveSecret(context.Background(), input)
+			if !errors.Is(err, ErrSecretReferenceUnresolved) {
+				t.Fatalf("want %v, got %v", ErrSecretReferenceUnresolved, err)
+			}
+		})
+	}
+
+	t.Run("command output not included in error", func(t *testing.T) {
+		script := filepath.Join(dir, "leak.sh")
+		if err := os.WriteFile(script, []byte("#!/bin/sh\necho leaked\nexit 1\n"), 0700); err != nil {
+			t.Fatalf("failed to create script: %v", err)
+		}
+
+		_, err := resolveSecret(context.Background(), "cmd:"+script)
+		if err == nil {
+			t.Fatal("want error, got nil")
+		}
+
+		if strings.Contains(err.Error(), "leaked") {
+			t.Errorf("error message contains command output: %v", err)
+		}
+	})
+}
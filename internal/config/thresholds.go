@@ -0,0 +1,179 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// FolderThreshold represents message count thresholds for a specific folder
+// (IMAP "mailbox"). A WARNING state is indicated if the number of messages
+// found exceeds the Warning value and a CRITICAL state if the number of
+// messages found exceeds the Critical value.
+type FolderThreshold struct {
+	// Folder is the name of the folder the thresholds apply to.
+	Folder string
+
+	// Warning is the maximum number of messages permitted before a WARNING
+	// state is indicated.
+	Warning int
+
+	// Critical is the maximum number of messages permitted before a
+	// CRITICAL state is indicated.
+	Critical int
+}
+
+// WarningRange returns the Nagios range string equivalent of the Warning
+// value.
+func (ft FolderThreshold) WarningRange() string {
+	return strconv.Itoa(ft.Warning)
+}
+
+// CriticalRange returns the Nagios range string equivalent of the Critical
+// value.
+func (ft FolderThreshold) CriticalRange() string {
+	return strconv.Itoa(ft.Critical)
+}
+
+// FolderThresholds is a custom type that satisfies the flag.Value interface
+// in order to accept per-folder thresholds in the form of
+// "INBOX:5:20,Junk:100:500".
+type FolderThresholds []FolderThreshold
+
+// String returns a comma separated string consisting of all folder
+// thresholds.
+func (fts *FolderThresholds) String() string {
+
+	// From the `flag` package docs:
+	// "The flag package may call the String method with a zero-valued
+	// receiver, such as a nil pointer."
+	if fts == nil {
+		return ""
+	}
+
+	entries := make([]string, 0, len(*fts))
+	for _, ft := range *fts {
+		entries = append(entries, fmt.Sprintf("%s:%d:%d", ft.Folder, ft.Warning, ft.Critical))
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// Set is called once by the flag package, in command line order, for each
+// flag present.
+func (fts *FolderThresholds) Set(value string) error {
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		ft, err := parseFolderThreshold(entry)
+		if err != nil {
+			return err
+		}
+
+		*fts = append(*fts, ft)
+	}
+
+	return nil
+}
+
+// Lookup returns the thresholds for the specified folder and true if
+// present or false if not. The INBOX folder name is matched
+// case-insensitively; all other folder names are case-sensitive.
+func (fts FolderThresholds) Lookup(folder string) (FolderThreshold, bool) {
+	for _, ft := range fts {
+		if folderNamesMatch(ft.Folder, folder) {
+			return ft, true
+		}
+	}
+
+	return FolderThreshold{}, false
+}
+
+// parseFolderThreshold parses a single FOLDER:WARNING:CRITICAL entry. The
+// folder name may itself contain a colon character; the last two fields are
+// used as the threshold values.
+func parseFolderThreshold(entry string) (FolderThreshold, error) {
+	invalidErr := func(reason string) error {
+		return fmt.Errorf(
+			"invalid folder threshold %q (want FOLDER:WARNING:CRITICAL): %s",
+			entry,
+			reason,
+		)
+	}
+
+	critIdx := strings.LastIndex(entry, ":")
+	if critIdx < 0 {
+		return FolderThreshold{}, invalidErr("missing thresholds")
+	}
+
+	warnIdx := strings.LastIndex(entry[:critIdx], ":")
+	if warnIdx <= 0 {
+		return FolderThreshold{}, invalidErr("missing folder name or warning threshold")
+	}
+
+	folder := strings.TrimSpace(entry[:warnIdx])
+	if folder == "" {
+		return FolderThreshold{}, invalidErr("empty folder name")
+	}
+
+	warning, err := strconv.Atoi(strings.TrimSpace(entry[warnIdx+1 : critIdx]))
+	if err != nil || warning < 0 {
+		return FolderThreshold{}, invalidErr("warning threshold is not a non-negative whole number")
+	}
+
+	critical, err := strconv.Atoi(strings.TrimSpace(entry[critIdx+1:]))
+	if err != nil || critical < 0 {
+		return FolderThreshold{}, invalidErr("critical threshold is not a non-negative whole number")
+	}
+
+	if critical < warning {
+		return FolderThreshold{}, invalidErr("critical threshold is less than warning threshold")
+	}
+
+	return FolderThreshold{
+		Folder:   folder,
+		Warning:  warning,
+		Critical: critical,
+	}, nil
+}
+
+// folderNamesMatch indicates whether two folder names refer to the same
+// folder. Per RFC 3501 the INBOX folder name is case-insensitive.
+func folderNamesMatch(a string, b string) bool {
+	if strings.EqualFold(a, "INBOX") {
+		return strings.EqualFold(a, b)
+	}
+
+	return a == b
+}
+
+// validateThresholdRange asserts that the given value is a valid Nagios
+// range string. Empty values are permitted.
+func validateThresholdRange(name string, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if nagios.ParseRangeString(value) == nil {
+		return fmt.Errorf(
+			"invalid %s threshold range %q: %w",
+			name,
+			value,
+			nagios.ErrInvalidRangeThreshold,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestFolderThresholdsSet asserts that per-folder thresholds are parsed from
+// a comma-separated list of FOLDER:WARNING:CRITICAL entries.
+func TestFolderThresholdsSet(t *testing.T) {
+	t.Parallel()
+
+	var got FolderThresholds
+	if err := got.Set("INBOX:5:20, Junk:100:500,Archive:2024:0:10"); err != nil {
+		t.Fatalf("failed to parse folder thresholds: %v", err)
+	}
+
+	want := FolderThresholds{
+		{Folder: "INBOX", Warning: 5, Critical: 20},
+		{Folder: "Junk", Warning: 100, Critical: 500},
+		{Folder: "Archive:2024", Warning: 0, Critical: 10},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	if _, ok := got.Lookup("Inbox"); !ok {
+		t.Error("want case-insensitive match for INBOX folder")
+	}
+
+	if _, ok := got.Lookup("junk"); ok {
+		t.Error("want case-sensitive match for non-INBOX folder")
+	}
+}
+
+// TestFolderThresholdsSetInvalid asserts that malformed entries are
+// rejected.
+func TestFolderThresholdsSetInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"INBOX",
+		"INBOX:5",
+		":5:20",
+		"INBOX:five:20",
+		"INBOX:-1:20",
+		"INBOX:20:5",
+	} {
+		var fts FolderThresholds
+		if err := fts.Set(input); err == nil {
+			t.Errorf("input %q: want error, got nil", input)
+		}
+	}
+}
+
+// TestValidateExpectMail asserts that expect mail mode settings are only
+// accepted with expect mail mode enabled and that the default expect mail
+// thresholds indicate a CRITICAL state when no messages are found.
+func TestValidateExpectMail(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"expect mail with newest age thresholds": {
+			cfg: Config{
+				ExpectMail:                 true,
+				NewestAgeWarningThreshold:  26 * time.Hour,
+				NewestAgeCriticalThreshold: 48 * time.Hour,
+				MessageWindow:              24 * time.Hour,
+			},
+		},
+		"newest age threshold without expect mail": {
+			cfg:     Config{NewestAgeWarningThreshold: time.Hour},
+			wantErr: true,
+		},
+		"window without expect mail": {
+			cfg:     Config{MessageWindow: time.Hour},
+			wantErr: true,
+		},
+		"critical less than warning": {
+			cfg: Config{
+				ExpectMail:                 true,
+				NewestAgeWarningThreshold:  2 * time.Hour,
+				NewestAgeCriticalThreshold: time.Hour,
+			},
+			wantErr: true,
+		},
+		"folder thresholds with expect mail": {
+			cfg: Config{
+				ExpectMail:       true,
+				FolderThresholds: FolderThresholds{{Folder: "INBOX", Warning: 1, Critical: 2}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateExpectMail(tt.cfg)
+			if tt.wantErr && err == nil {
+				t.Error("want error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("want no error, got %v", err)
+			}
+		})
+	}
+
+	warning, critical := Config{ExpectMail: true}.TotalThresholds()
+	if warning != "" || critical != expectMailCriticalThreshold {
+		t.Errorf("want thresholds (%q, %q), got (%q, %q)", "", expectMailCriticalThreshold, warning, critical)
+	}
+}
+
+// TestValidateFolderThresholdsExpandedFolders asserts that per-folder
+// thresholds are only required to name a listed folder if the folders to
+// check are not expanded from patterns, recursion or exclusions.
+func TestValidateFolderThresholdsExpandedFolders(t *testing.T) {
+	t.Parallel()
+
+	thresholds := FolderThresholds{{Folder: "Projects/Alpha", Warning: 1, Critical: 2}}
+
+	tests := map[string]struct {
+		account MailAccount
+		wantErr bool
+	}{
+		"listed folders": {
+			account: MailAccount{Folders: multiValueFlag{"INBOX", "Projects"}},
+			wantErr: true,
+		},
+		"folder pattern": {
+			account: MailAccount{Folders: multiValueFlag{"INBOX", "Projects/*"}},
+		},
+		"recursive folders": {
+			account: MailAccount{Folders: multiValueFlag{"Projects"}, RecurseFolders: true},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateThresholds(Config{
+				Accounts:         []MailAccount{tt.account},
+				FolderThresholds: thresholds,
+			})
+			if tt.wantErr && err == nil {
+				t.Error("want error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("want no error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestValidateFolders asserts that only supported special-use roles may be
+// used in place of folder names.
+func TestValidateFolders(t *testing.T) {
+	t.Parallel()
+
+	valid := MailAccount{
+		Folders:        multiValueFlag{"INBOX", `\Junk`},
+		ExcludeFolders: multiValueFlag{`\Trash`},
+	}
+	if err := validateFolders(valid); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+
+	invalid := MailAccount{
+		Folders: multiValueFlag{"INBOX", `\Spam`},
+	}
+	if err := validateFolders(invalid); err == nil {
+		t.Error("want error for unsupported special-use role, got nil")
+	}
+}
+
+// TestValidateMissingFolderState asserts that only supported plugin state
+// keywords are accepted for folders which are not found.
+func TestValidateMissingFolderState(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		state    string
+		wantErr  bool
+		wantCode int
+	}{
+		"default":       {state: defaultMissingFolderState, wantCode: nagios.StateCRITICALExitCode},
+		"ok":            {state: "ok", wantCode: nagios.StateOKExitCode},
+		"mixed case":    {state: "Warning", wantCode: nagios.StateWARNINGExitCode},
+		"unknown":       {state: "unknown", wantCode: nagios.StateUNKNOWNExitCode},
+		"unsupported":   {state: "dependent", wantErr: true},
+		"empty keyword": {state: "", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := Config{MissingFolderState: tt.state}
+
+			err := validateMissingFolderState(c)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("want error, got nil")
+			case !tt.wantErr && err != nil:
+				t.Fatalf("want no error, got %v", err)
+			case tt.wantErr:
+				return
+			}
+
+			if got := c.MissingFolderExitCode(); got != tt.wantCode {
+				t.Errorf("want exit code %d, got %d", tt.wantCode, got)
+			}
+		})
+	}
+}
@@ -9,7 +9,10 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
 )
 
 // validateTLSVersion asserts that the specified TLS version keyword is valid.
@@ -53,6 +56,253 @@ func validateLoggingLevels(c Config) error {
 	return nil
 }
 
+// validateThresholds asserts that the specified message count thresholds
+// are valid and that per-folder thresholds refer to folders being checked.
+func validateThresholds(c Config) error {
+	if err := validateThresholdRange("warning", c.WarningThreshold); err != nil {
+		return err
+	}
+
+	if err := validateThresholdRange("critical", c.CriticalThreshold); err != nil {
+		return err
+	}
+
+	if c.AgeWarningThreshold < 0 || c.AgeCriticalThreshold < 0 {
+		return fmt.Errorf(
+			"invalid message age threshold; negative durations are not supported",
+		)
+	}
+
+	if c.AgeWarningThreshold > 0 &&
+		c.AgeCriticalThreshold > 0 &&
+		c.AgeCriticalThreshold < c.AgeWarningThreshold {
+		return fmt.Errorf(
+			"message age critical threshold %s is less than warning threshold %s",
+			c.AgeCriticalThreshold,
+			c.AgeWarningThreshold,
+		)
+	}
+
+	if err := validateExpectMail(c); err != nil {
+		return err
+	}
+
+	for _, ft := range c.FolderThresholds {
+		for _, account := range c.Accounts {
+			// The folders checked are only known once patterns, recursion
+			// and exclusions are expanded against the server.
+			if account.FolderSelection().IsExpanded() {
+				continue
+			}
+
+			found := false
+			for _, folder := range account.Folders {
+				if folderNamesMatch(ft.Folder, folder) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return fmt.Errorf(
+					"threshold specified for folder %q which is not in the list of folders to check",
+					ft.Folder,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateQuotaThresholds asserts that the specified quota usage percentage
+// thresholds are valid.
+func validateQuotaThresholds(c Config) error {
+	thresholds := []struct {
+		flag  string
+		value int
+	}{
+		{flag: WarningThresholdFlagLong, value: c.QuotaWarningThreshold},
+		{flag: CriticalThresholdFlagLong, value: c.QuotaCriticalThreshold},
+	}
+
+	for _, threshold := range thresholds {
+		if threshold.value < 0 || threshold.value > 100 {
+			return fmt.Errorf(
+				"invalid %s value %d; a percentage between 0 and 100 is required",
+				threshold.flag,
+				threshold.value,
+			)
+		}
+	}
+
+	if c.QuotaWarningThreshold > 0 &&
+		c.QuotaCriticalThreshold > 0 &&
+		c.QuotaCriticalThreshold < c.QuotaWarningThreshold {
+		return fmt.Errorf(
+			"%s value %d is less than %s value %d",
+			CriticalThresholdFlagLong,
+			c.QuotaCriticalThreshold,
+			WarningThresholdFlagLong,
+			c.QuotaWarningThreshold,
+		)
+	}
+
+	return nil
+}
+
+// validateExpectMail asserts that expect mail (freshness) mode settings are
+// valid and only used with expect mail mode enabled.
+func validateExpectMail(c Config) error {
+	if !c.ExpectMail {
+		if c.NewestAgeWarningThreshold != 0 ||
+			c.NewestAgeCriticalThreshold != 0 ||
+			c.MessageWindow != 0 {
+			return fmt.Errorf(
+				"newest message age thresholds and message window require the %s flag",
+				ExpectMailFlag,
+			)
+		}
+
+		return nil
+	}
+
+	if len(c.FolderThresholds) > 0 {
+		return fmt.Errorf(
+			"per-folder thresholds are not supported with the %s flag",
+			ExpectMailFlag,
+		)
+	}
+
+	if c.NewestAgeWarningThreshold < 0 ||
+		c.NewestAgeCriticalThreshold < 0 ||
+		c.MessageWindow < 0 {
+		return fmt.Errorf(
+			"invalid newest message age threshold or message window; negative durations are not supported",
+		)
+	}
+
+	if c.NewestAgeWarningThreshold > 0 &&
+		c.NewestAgeCriticalThreshold > 0 &&
+		c.NewestAgeCriticalThreshold < c.NewestAgeWarningThreshold {
+		return fmt.Errorf(
+			"newest message age critical threshold %s is less than warning threshold %s",
+			c.NewestAgeCriticalThreshold,
+			c.NewestAgeWarningThreshold,
+		)
+	}
+
+	return nil
+}
+
+// validateOAuth2Provider asserts that the specified OAuth2 provider keyword
+// is valid and that any provider-specific settings have been provided. An
+// empty provider keyword is valid; the token URL and scopes are then required
+// to be explicitly specified.
+func validateOAuth2Provider(settings OAuth2ClientCredentialsFlow) error {
+	switch strings.ToLower(settings.Provider) {
+	case "":
+		return nil
+
+	case OAuth2ProviderGoogle:
+		return nil
+
+	case OAuth2ProviderOffice365:
+		if settings.TokenURL == "" && settings.TenantID == "" {
+			return fmt.Errorf(
+				"tenant ID not provided for %s provider",
+				OAuth2ProviderOffice365,
+			)
+		}
+
+		return nil
+
+	case OAuth2ProviderGenericOIDC:
+		if settings.TokenURL != "" {
+			return nil
+		}
+
+		if settings.IssuerURL == "" {
+			return fmt.Errorf(
+				"issuer URL not provided for %s provider",
+				OAuth2ProviderGenericOIDC,
+			)
+		}
+
+		issuer, err := url.Parse(settings.IssuerURL)
+		if err != nil {
+			return fmt.Errorf(
+				"invalid issuer URL %q: %w",
+				settings.IssuerURL,
+				err,
+			)
+		}
+
+		// OpenID Connect requires that the issuer use the https scheme.
+		//
+		// https://openid.net/specs/openid-connect-discovery-1_0.html#IssuerDiscovery
+		if issuer.Scheme != "https" || issuer.Host == "" {
+			return fmt.Errorf(
+				"invalid issuer URL %q; https URL required",
+				settings.IssuerURL,
+			)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf(
+			"unexpected OAuth2 provider %q: %w",
+			settings.Provider,
+			ErrInvalidOAuth2Provider,
+		)
+	}
+}
+
+// validateOAuth2GrantType asserts that the specified OAuth2 grant type
+// keyword is valid and that any grant-specific settings have been provided.
+// An empty grant type keyword is treated as the Client Credentials grant.
+func validateOAuth2GrantType(settings OAuth2ClientCredentialsFlow) error {
+	switch strings.ToLower(settings.GrantType) {
+	case "", OAuth2GrantTypeClientCredentials:
+		return nil
+
+	case OAuth2GrantTypeJWTBearer:
+		if settings.ServiceAccountKeyFile == "" {
+			return fmt.Errorf(
+				"service account key file not provided for %s grant",
+				OAuth2GrantTypeJWTBearer,
+			)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf(
+			"unexpected OAuth2 grant type %q: %w",
+			settings.GrantType,
+			ErrInvalidOAuth2GrantType,
+		)
+	}
+}
+
+// tokenURLPendingDiscovery indicates whether the token URL for the given
+// OAuth2 settings is not yet known but will be resolved via OpenID Connect
+// discovery after validation is complete or (for the JWT bearer grant) read
+// from the service account key file when requesting a token.
+func tokenURLPendingDiscovery(settings OAuth2ClientCredentialsFlow) bool {
+	if settings.TokenURL != "" {
+		return false
+	}
+
+	if settings.UsesJWTBearerGrant() {
+		return true
+	}
+
+	return settings.IssuerURL != "" &&
+		strings.ToLower(settings.Provider) == OAuth2ProviderGenericOIDC
+}
+
 // validateAccountBasicAuthFields is responsible for validating MailAccount
 // fields specific to the Basic Authentication type. The caller is responsible
 // for calling this function for the appropriate application type.
@@ -77,18 +327,38 @@ func validateAccountBasicAuthFields(account MailAccount, _ AppType) error {
 // authentication type. The caller is responsible for calling this function
 // for the appropriate application type.
 func validateAccountOAuth2ClientCredsAuthFields(account MailAccount, _ AppType) error {
-	if account.OAuth2Settings.ClientID == "" {
-		return fmt.Errorf("client ID not provided for account %s",
+	if err := validateOAuth2Provider(account.OAuth2Settings); err != nil {
+		return fmt.Errorf(
+			"invalid OAuth2 provider settings for account %s: %w",
 			account.Name,
+			err,
 		)
 	}
 
-	if account.OAuth2Settings.ClientSecret == "" {
-		return fmt.Errorf("client secret not provided for account %s",
+	if err := validateOAuth2GrantType(account.OAuth2Settings); err != nil {
+		return fmt.Errorf(
+			"invalid OAuth2 grant settings for account %s: %w",
 			account.Name,
+			err,
 		)
 	}
 
+	// A service account key is used in place of the client ID and secret for
+	// the JWT bearer grant.
+	if !account.OAuth2Settings.UsesJWTBearerGrant() {
+		if account.OAuth2Settings.ClientID == "" {
+			return fmt.Errorf("client ID not provided for account %s",
+				account.Name,
+			)
+		}
+
+		if account.OAuth2Settings.ClientSecret == "" {
+			return fmt.Errorf("client secret not provided for account %s",
+				account.Name,
+			)
+		}
+	}
+
 	// Scopes is non-optional. If we want to support just *one* IMAP provider
 	// (e.g., O365) we can fallback to using a default scope, but if the goal
 	// is (it is) to support multiple providers we need to require at least
@@ -114,7 +384,8 @@ func validateAccountOAuth2ClientCredsAuthFields(account MailAccount, _ AppType)
 		)
 	}
 
-	if account.OAuth2Settings.TokenURL == "" {
+	if account.OAuth2Settings.TokenURL == "" &&
+		!tokenURLPendingDiscovery(account.OAuth2Settings) {
 		return fmt.Errorf("token URL not provided for account %s",
 			account.Name,
 		)
@@ -131,12 +402,32 @@ func validateFetcherOAuth2TokenFields(tokenSettings FetcherOAuth2TokenSettings,
 
 	switch {
 	case appType.FetcherOAuth2TokenFromAuthServer:
-		if tokenSettings.ClientID == "" {
-			return fmt.Errorf("client ID not provided")
+		if err := validateOAuth2Provider(tokenSettings.OAuth2ClientCredentialsFlow); err != nil {
+			return fmt.Errorf("invalid OAuth2 provider settings: %w", err)
 		}
 
-		if tokenSettings.ClientSecret == "" {
-			return fmt.Errorf("client secret not provided")
+		if err := validateOAuth2GrantType(tokenSettings.OAuth2ClientCredentialsFlow); err != nil {
+			return fmt.Errorf("invalid OAuth2 grant settings: %w", err)
+		}
+
+		switch {
+		case tokenSettings.UsesJWTBearerGrant():
+			// There is no shared mailbox to fall back to for this app type.
+			if tokenSettings.Subject == "" {
+				return fmt.Errorf(
+					"subject not provided for %s grant",
+					OAuth2GrantTypeJWTBearer,
+				)
+			}
+
+		default:
+			if tokenSettings.ClientID == "" {
+				return fmt.Errorf("client ID not provided")
+			}
+
+			if tokenSettings.ClientSecret == "" {
+				return fmt.Errorf("client secret not provided")
+			}
 		}
 
 		// Scopes is non-optional. If we want to support just *one* IMAP provider
@@ -154,7 +445,8 @@ func validateFetcherOAuth2TokenFields(tokenSettings FetcherOAuth2TokenSettings,
 			}
 		}
 
-		if tokenSettings.TokenURL == "" {
+		if tokenSettings.TokenURL == "" &&
+			!tokenURLPendingDiscovery(tokenSettings.OAuth2ClientCredentialsFlow) {
 			return fmt.Errorf("token URL not provided")
 		}
 
@@ -165,6 +457,12 @@ func validateFetcherOAuth2TokenFields(tokenSettings FetcherOAuth2TokenSettings,
 			)
 		}
 
+		// Encryption applies to the token file only; the token is emitted
+		// as-is to stdout.
+		if tokenSettings.EncryptionKey != "" && tokenSettings.Filename == "" {
+			return fmt.Errorf("encryption key provided without filename")
+		}
+
 	case appType.FetcherOAuth2TokenFromCache:
 
 		// The filename to read a token from is only required for this specific
@@ -185,6 +483,190 @@ func validateFetcherOAuth2TokenFields(tokenSettings FetcherOAuth2TokenSettings,
 	return nil
 }
 
+// validateMissingFolderState asserts that a supported plugin state keyword
+// is specified for folders which are not found.
+func validateMissingFolderState(c Config) error {
+	switch strings.ToLower(c.MissingFolderState) {
+	case stateKeywordOK, stateKeywordWarning, stateKeywordCritical, stateKeywordUnknown:
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid %s value %q; one of %s, %s, %s or %s is required",
+			MissingFolderStateFlag,
+			c.MissingFolderState,
+			stateKeywordOK,
+			stateKeywordWarning,
+			stateKeywordCritical,
+			stateKeywordUnknown,
+		)
+	}
+}
+
+// validateDeltaSettings asserts that a state directory is specified if
+// delta mode is enabled.
+func validateDeltaSettings(c Config) error {
+	if c.Delta && strings.TrimSpace(c.StateDir) == "" {
+		return fmt.Errorf(
+			"%s value is required when %s is specified",
+			StateDirFlag,
+			DeltaFlag,
+		)
+	}
+
+	return nil
+}
+
+// validateMessageListing asserts that valid settings for listing messages
+// in the extended plugin output are specified.
+func validateMessageListing(c Config) error {
+	if c.ListMessages < 0 {
+		return fmt.Errorf(
+			"invalid %s value %d; a non-negative whole number is required",
+			ListMessagesFlag,
+			c.ListMessages,
+		)
+	}
+
+	switch strings.ToLower(c.ListOrder) {
+	case ListOrderNewest, ListOrderOldest:
+	default:
+		return fmt.Errorf(
+			"invalid %s value %q; one of %s or %s is required",
+			ListOrderFlag,
+			c.ListOrder,
+			ListOrderNewest,
+			ListOrderOldest,
+		)
+	}
+
+	switch strings.ToLower(c.ListFormat) {
+	case ListFormatText, ListFormatHTML:
+	default:
+		return fmt.Errorf(
+			"invalid %s value %q; one of %s or %s is required",
+			ListFormatFlag,
+			c.ListFormat,
+			ListFormatText,
+			ListFormatHTML,
+		)
+	}
+
+	return nil
+}
+
+// validateRetrySettings asserts that valid settings for retrying transient
+// errors and limiting plugin runtime are specified.
+func validateRetrySettings(c Config) error {
+	if c.Retries < 0 {
+		return fmt.Errorf(
+			"invalid %s value %d; a non-negative whole number is required",
+			RetriesFlag,
+			c.Retries,
+		)
+	}
+
+	if c.RetryDelay < 0 {
+		return fmt.Errorf(
+			"invalid %s value %s; negative durations are not supported",
+			RetryDelayFlag,
+			c.RetryDelay,
+		)
+	}
+
+	if c.Timeout < 0 {
+		return fmt.Errorf(
+			"invalid %s value %s; negative durations are not supported",
+			TimeoutFlag,
+			c.Timeout,
+		)
+	}
+
+	return nil
+}
+
+// validateFetchSettings asserts that valid settings for retrieving message
+// details are specified.
+func validateFetchSettings(c Config) error {
+	if c.FetchBatchSize < 1 {
+		return fmt.Errorf(
+			"invalid %s value %d; a positive whole number is required",
+			FetchBatchSizeFlag,
+			c.FetchBatchSize,
+		)
+	}
+
+	if c.MaxMessagesPerFolder < 0 {
+		return fmt.Errorf(
+			"invalid %s value %d; a non-negative whole number is required",
+			MaxMessagesFlag,
+			c.MaxMessagesPerFolder,
+		)
+	}
+
+	return nil
+}
+
+// validateMessageCriteria asserts that only supported message criteria and
+// valid search filters are specified for the given account.
+func validateMessageCriteria(account MailAccount) error {
+	for _, criterion := range account.Criteria {
+		switch strings.ToLower(criterion) {
+		case messageCriterionUnseen,
+			messageCriterionFlagged,
+			messageCriterionUnanswered,
+			messageCriterionDeleted:
+		default:
+			return fmt.Errorf(
+				"unsupported message criterion %q provided for account %s; supported criteria: %s",
+				criterion,
+				account.Name,
+				strings.Join([]string{
+					messageCriterionUnseen,
+					messageCriterionFlagged,
+					messageCriterionUnanswered,
+					messageCriterionDeleted,
+				}, ", "),
+			)
+		}
+	}
+
+	for _, filters := range []SearchFilters{account.IncludeFilters, account.ExcludeFilters} {
+		for _, expr := range filters {
+			if err := mbxs.ValidateSearchFilter(expr); err != nil {
+				return fmt.Errorf(
+					"invalid search filter provided for account %s: %w",
+					account.Name,
+					err,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFolders asserts that special-use roles (e.g., \Junk) used in
+// place of folder names are supported.
+func validateFolders(account MailAccount) error {
+	for _, folders := range []multiValueFlag{account.Folders, account.ExcludeFolders} {
+		for _, folder := range folders {
+			if !mbxs.IsSpecialUseRole(folder) {
+				continue
+			}
+
+			if err := mbxs.ValidateSpecialUseRole(folder); err != nil {
+				return fmt.Errorf(
+					"invalid folder provided for account %s: %w",
+					account.Name,
+					err,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
 // validateAccounts is responsible for validating MailAccount fields.
 func validateAccounts(c Config, appType AppType) error {
 	for _, account := range c.Accounts {
@@ -234,11 +716,30 @@ func validateAccounts(c Config, appType AppType) error {
 				)
 			}
 
+			if err := validateFolders(account); err != nil {
+				return err
+			}
+
+			if err := validateMessageCriteria(account); err != nil {
+				return err
+			}
+
 		case appType.InspectorIMAPCaps:
 
 			// This app type only uses the server/port values.
 
 		case appType.PluginIMAPMailboxBasicAuth:
+			// Accounts loaded from a configuration file may specify an
+			// authentication type not supported by this plugin.
+			if account.AuthType != AuthTypeBasic {
+				return fmt.Errorf(
+					"unsupported authentication type %q for account %s: %w",
+					account.AuthType,
+					account.Name,
+					ErrInvalidAuthType,
+				)
+			}
+
 			if err := validateAccountBasicAuthFields(account, appType); err != nil {
 				return err
 			}
@@ -250,8 +751,27 @@ func validateAccounts(c Config, appType AppType) error {
 				)
 			}
 
+			if err := validateFolders(account); err != nil {
+				return err
+			}
+
+			if err := validateMessageCriteria(account); err != nil {
+				return err
+			}
+
 		case appType.PluginIMAPMailboxOAuth2:
 
+			// Accounts loaded from a configuration file may specify an
+			// authentication type not supported by this plugin.
+			if account.AuthType != AuthTypeOAuth2ClientCreds {
+				return fmt.Errorf(
+					"unsupported authentication type %q for account %s: %w",
+					account.AuthType,
+					account.Name,
+					ErrInvalidAuthType,
+				)
+			}
+
 			if err := validateAccountOAuth2ClientCredsAuthFields(account, appType); err != nil {
 				return err
 			}
@@ -262,6 +782,35 @@ func validateAccounts(c Config, appType AppType) error {
 					account.Name,
 				)
 			}
+
+			if err := validateFolders(account); err != nil {
+				return err
+			}
+
+			if err := validateMessageCriteria(account); err != nil {
+				return err
+			}
+
+		case appType.PluginIMAPQuota:
+
+			switch account.AuthType {
+			case AuthTypeBasic:
+				if err := validateAccountBasicAuthFields(account, appType); err != nil {
+					return err
+				}
+
+			case AuthTypeOAuth2ClientCreds:
+				if err := validateAccountOAuth2ClientCredsAuthFields(account, appType); err != nil {
+					return err
+				}
+
+			default:
+				return fmt.Errorf(
+					"unexpected authentication type %q: %w",
+					account.AuthType,
+					ErrInvalidAuthType,
+				)
+			}
 		}
 
 	}
@@ -300,6 +849,26 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		if err := validateThresholds(c); err != nil {
+			return err
+		}
+
+		if err := validateMissingFolderState(c); err != nil {
+			return err
+		}
+
+		if err := validateMessageListing(c); err != nil {
+			return err
+		}
+
+		if err := validateDeltaSettings(c); err != nil {
+			return err
+		}
+
+		if err := validateRateSettings(c); err != nil {
+			return err
+		}
+
 		if err := validateTLSVersion(c); err != nil {
 			return err
 		}
@@ -308,6 +877,14 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		if err := validateRetrySettings(c); err != nil {
+			return err
+		}
+
+		if err := validateFetchSettings(c); err != nil {
+			return err
+		}
+
 		if err := validateLoggingLevels(c); err != nil {
 			return err
 		}
@@ -318,6 +895,56 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		if err := validateThresholds(c); err != nil {
+			return err
+		}
+
+		if err := validateMissingFolderState(c); err != nil {
+			return err
+		}
+
+		if err := validateMessageListing(c); err != nil {
+			return err
+		}
+
+		if err := validateDeltaSettings(c); err != nil {
+			return err
+		}
+
+		if err := validateRateSettings(c); err != nil {
+			return err
+		}
+
+		if err := validateTLSVersion(c); err != nil {
+			return err
+		}
+
+		if err := validateNetworkType(c); err != nil {
+			return err
+		}
+
+		if err := validateRetrySettings(c); err != nil {
+			return err
+		}
+
+		if err := validateFetchSettings(c); err != nil {
+			return err
+		}
+
+		if err := validateLoggingLevels(c); err != nil {
+			return err
+		}
+
+	case appType.PluginIMAPQuota:
+
+		if err := validateAccounts(c, appType); err != nil {
+			return err
+		}
+
+		if err := validateQuotaThresholds(c); err != nil {
+			return err
+		}
+
 		if err := validateTLSVersion(c); err != nil {
 			return err
 		}
@@ -326,6 +953,10 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		if err := validateRetrySettings(c); err != nil {
+			return err
+		}
+
 		if err := validateLoggingLevels(c); err != nil {
 			return err
 		}
@@ -357,6 +988,10 @@ func (c Config) validate(appType AppType) error {
 			return fmt.Errorf("missing log file output directory")
 		}
 
+		if err := validateDeltaSettings(c); err != nil {
+			return err
+		}
+
 		if err := validateAccounts(c, appType); err != nil {
 			return err
 		}
@@ -369,6 +1004,14 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		if err := validateRetrySettings(c); err != nil {
+			return err
+		}
+
+		if err := validateFetchSettings(c); err != nil {
+			return err
+		}
+
 		if err := validateLoggingLevels(c); err != nil {
 			return err
 		}
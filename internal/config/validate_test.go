@@ -0,0 +1,189 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestValidateDeltaSettings asserts that a state directory is required for
+// delta mode.
+func TestValidateDeltaSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"defaults": {
+			cfg: Config{Delta: defaultDelta, StateDir: defaultStateDir},
+		},
+		"delta with state dir": {
+			cfg: Config{Delta: true, StateDir: "/var/lib/check-mail"},
+		},
+		"delta without state dir": {
+			cfg:     Config{Delta: true, StateDir: " "},
+			wantErr: StateDirFlag,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateDeltaSettings(tt.cfg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("want no error, got %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("want error for %s flag, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestValidateMessageListing asserts that message listing settings are
+// validated and that listing messages requires message details.
+func TestValidateMessageListing(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"defaults": {
+			cfg: Config{ListMessages: defaultListMessages, ListOrder: defaultListOrder, ListFormat: defaultListFormat},
+		},
+		"oldest first as html": {
+			cfg: Config{ListMessages: 5, ListOrder: "Oldest", ListFormat: "HTML"},
+		},
+		"negative limit": {
+			cfg:     Config{ListMessages: -1, ListOrder: ListOrderNewest, ListFormat: ListFormatText},
+			wantErr: ListMessagesFlag,
+		},
+		"unsupported order": {
+			cfg:     Config{ListMessages: 5, ListOrder: "subject", ListFormat: ListFormatText},
+			wantErr: ListOrderFlag,
+		},
+		"unsupported format": {
+			cfg:     Config{ListMessages: 5, ListOrder: ListOrderNewest, ListFormat: "markdown"},
+			wantErr: ListFormatFlag,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateMessageListing(tt.cfg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("want no error, got %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("want error for %s flag, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if !(Config{ListMessages: 1}).FetchMessageDetails() {
+		t.Error("want message details fetched when listing messages")
+	}
+}
+
+// TestValidateRetrySettings asserts that negative retry counts, retry delays
+// and timeouts are rejected.
+func TestValidateRetrySettings(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"defaults": {
+			cfg: Config{Retries: defaultRetries, RetryDelay: defaultRetryDelay, Timeout: defaultTimeout},
+		},
+		"retries disabled": {
+			cfg: Config{Retries: 0, RetryDelay: 0, Timeout: 0},
+		},
+		"negative retries": {
+			cfg:     Config{Retries: -1},
+			wantErr: "invalid " + RetriesFlag + " value",
+		},
+		"negative retry delay": {
+			cfg:     Config{Retries: 1, RetryDelay: -time.Second},
+			wantErr: "invalid " + RetryDelayFlag + " value",
+		},
+		"negative timeout": {
+			cfg:     Config{Timeout: -time.Second},
+			wantErr: "invalid " + TimeoutFlag + " value",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRetrySettings(tt.cfg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("want no error, got %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("want error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestValidateQuotaThresholds asserts that quota thresholds are percentages
+// and that the critical threshold is not less than the warning threshold.
+func TestValidateQuotaThresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"defaults": {
+			cfg: Config{QuotaWarningThreshold: defaultQuotaWarning, QuotaCriticalThreshold: defaultQuotaCritical},
+		},
+		"warning disabled": {
+			cfg: Config{QuotaCriticalThreshold: 95},
+		},
+		"equal thresholds": {
+			cfg: Config{QuotaWarningThreshold: 90, QuotaCriticalThreshold: 90},
+		},
+		"critical less than warning": {
+			cfg:     Config{QuotaWarningThreshold: 90, QuotaCriticalThreshold: 80},
+			wantErr: CriticalThresholdFlagLong + " value 80 is less than " + WarningThresholdFlagLong + " value 90",
+		},
+		"negative threshold": {
+			cfg:     Config{QuotaWarningThreshold: -1, QuotaCriticalThreshold: 90},
+			wantErr: "invalid " + WarningThresholdFlagLong + " value -1",
+		},
+		"threshold over 100 percent": {
+			cfg:     Config{QuotaWarningThreshold: 80, QuotaCriticalThreshold: 101},
+			wantErr: "invalid " + CriticalThresholdFlagLong + " value 101",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateQuotaThresholds(tt.cfg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("want no error, got %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("want error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
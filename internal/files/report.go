@@ -10,6 +10,7 @@ package files
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -17,6 +18,7 @@ import (
 	"time"
 
 	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/check-mail/internal/textutils"
 	"github.com/rs/zerolog"
 )
 
@@ -43,9 +45,61 @@ type ReportData struct {
 	// character set.
 	UnicodeCharSubstitute string
 
+	// MessageRows, if set, provides the rows of the "Emails found" table as
+	// written to a MessageSpool. These rows are used in place of the
+	// messages recorded in MailboxCheckResults.
+	MessageRows io.Reader
+
 	// What else to include here?
 }
 
+// reportTemplateFuncs returns the collection of helper functions made
+// available to the report file template.
+func reportTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// messageAge returns the age of a message relative to the report
+		// time or a placeholder value if the message date is not known.
+		"messageAge": func(date time.Time, reportTime time.Time) string {
+			if date.IsZero() {
+				return "N/A"
+			}
+
+			return mbxs.FormatMessageAge(reportTime.Sub(date))
+		},
+
+		// textile replaces characters known to interfere with Textile
+		// formatting (e.g., the pipe characters used to separate table
+		// cells).
+		"textile": textutils.ReplaceTextileFormatCharacters,
+	}
+}
+
+// reportFilename returns the name of the report file for the specified
+// account and report time.
+func reportFilename(accountName string, reportTime time.Time) string {
+	return fmt.Sprintf(
+		ReportFilenameTemplate,
+		accountName,
+		reportTime.Format(ReportFilenameDateLayout),
+	)
+}
+
+// parseReportTemplate parses the given report template text.
+func parseReportTemplate(name string, text string) (*template.Template, error) {
+	tmpl, err := template.
+		New(name).
+		Funcs(reportTemplateFuncs()).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse report file template: %w",
+			err,
+		)
+	}
+
+	return tmpl, nil
+}
+
 // GenerateReport is a wrapper around the steps needed for generating or
 // updating a email summary report. This function receives a ReportData type
 // that acts as a container for all required information used by the report
@@ -57,26 +111,29 @@ func GenerateReport(reportData ReportData, reportDirectory string, logger zerolo
 	// with this file, but playing it safe?
 	var mutex = &sync.Mutex{}
 
-	reportFilename := fmt.Sprintf(
-		ReportFilenameTemplate,
-		reportData.AccountName,
-		reportData.ReportTime.Format(ReportFilenameDateLayout),
+	reportFilePath := filepath.Join(
+		reportDirectory,
+		reportFilename(reportData.AccountName, reportData.ReportTime),
 	)
 
-	reportFilePath := filepath.Join(reportDirectory, reportFilename)
-
 	logger = logger.With().
 		Str("report_file", reportFilePath).
 		Str("report_directory", reportDirectory).
 		Logger()
 
-	reportFileTemplate, tmplParseErr := template.
-		New("reportFileTemplate").Parse(reportFileTemplateText)
+	reportFileTemplate, tmplParseErr := parseReportTemplate("reportFileTemplate", reportFileTemplateText)
 	if tmplParseErr != nil {
-		return fmt.Errorf(
-			"failed to parse report file template: %w",
-			tmplParseErr,
-		)
+		return tmplParseErr
+	}
+
+	reportHeaderTemplate, tmplParseErr := parseReportTemplate("reportHeaderTemplate", reportHeaderTemplateText)
+	if tmplParseErr != nil {
+		return tmplParseErr
+	}
+
+	reportFooterTemplate, tmplParseErr := parseReportTemplate("reportFooterTemplate", reportFooterTemplateText)
+	if tmplParseErr != nil {
+		return tmplParseErr
 	}
 
 	logger.Debug().Msg("Calling os.MkdirAll to ensure report directory exists")
@@ -123,7 +180,26 @@ func GenerateReport(reportData ReportData, reportDirectory string, logger zerolo
 	}()
 
 	logger.Debug().Msg("Executing template to update report file")
-	if tmplErr := reportFileTemplate.Execute(f, reportData); tmplErr != nil {
+	writeReport := func(w io.Writer) error {
+		if reportData.MessageRows == nil {
+			return reportFileTemplate.Execute(w, reportData)
+		}
+
+		// Rows for messages streamed as they were retrieved are copied from
+		// the spool file in place of the rows generated by the template.
+		logger.Debug().Msg("Writing message rows from spool file")
+		if err := reportHeaderTemplate.Execute(w, reportData); err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(w, reportData.MessageRows); err != nil {
+			return err
+		}
+
+		return reportFooterTemplate.Execute(w, reportData)
+	}
+
+	if tmplErr := writeReport(f); tmplErr != nil {
 
 		// if there were template execution errors, go ahead and try to close
 		// the file before returning the template write error
@@ -0,0 +1,106 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package files
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/atc0005/check-mail/internal/textutils"
+)
+
+// messageSpoolFilePattern is the filename pattern used for temporary message
+// spool files created within the report directory.
+const messageSpoolFilePattern string = ".messages-*.spool"
+
+// MessageSpool is a temporary file used to collect report rows for messages
+// as they are retrieved. This allows generating reports for very large
+// mailboxes without retaining all messages in memory.
+type MessageSpool struct {
+	file   *os.File
+	writer *bufio.Writer
+	rows   int
+}
+
+// NewMessageSpool creates a new message spool file within the specified
+// report directory. The caller is responsible for calling Close to remove
+// the spool file once the report is generated.
+func NewMessageSpool(reportDirectory string) (*MessageSpool, error) {
+	if err := os.MkdirAll(reportDirectory, defaultDirectoryPerms); err != nil {
+		return nil, fmt.Errorf("failed to create report output dir: %w", err)
+	}
+
+	f, err := os.CreateTemp(reportDirectory, messageSpoolFilePattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message spool file: %w", err)
+	}
+
+	return &MessageSpool{
+		file:   f,
+		writer: bufio.NewWriter(f),
+	}, nil
+}
+
+// Add writes a report row for the given message found in the specified
+// mailbox to the spool file.
+func (ms *MessageSpool) Add(mailbox string, msg mbxs.Message) error {
+	subject := msg.OriginalSubject
+	if msg.ModifiedSubject != "" {
+		subject = msg.ModifiedSubject
+	}
+
+	if _, err := fmt.Fprintf(
+		ms.writer,
+		reportMessageRowFormat,
+		mailbox,
+		textutils.ReplaceTextileFormatCharacters(msg.From),
+		subject,
+		msg.EnvelopeDateFormatted,
+		msg.SizeFormatted(),
+	); err != nil {
+		return fmt.Errorf("failed to write to message spool file: %w", err)
+	}
+
+	ms.rows++
+
+	return nil
+}
+
+// Rows returns the number of report rows written to the spool file.
+func (ms *MessageSpool) Rows() int {
+	return ms.rows
+}
+
+// Reader returns a reader for all report rows written to the spool file.
+func (ms *MessageSpool) Reader() (io.Reader, error) {
+	if err := ms.writer.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to flush message spool file: %w", err)
+	}
+
+	if _, err := ms.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind message spool file: %w", err)
+	}
+
+	return bufio.NewReader(ms.file), nil
+}
+
+// Close closes and removes the spool file.
+func (ms *MessageSpool) Close() error {
+	closeErr := ms.file.Close()
+	if closeErr != nil && errors.Is(closeErr, os.ErrClosed) {
+		closeErr = nil
+	}
+
+	removeErr := os.Remove(ms.file.Name())
+
+	return errors.Join(closeErr, removeErr)
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/rs/zerolog"
+)
+
+// TestMessageSpoolReport asserts that a report generated using message rows
+// from a spool file matches a report generated from retained messages.
+func TestMessageSpoolReport(t *testing.T) {
+	t.Parallel()
+
+	reportTime := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	messages := []mbxs.Message{
+		{OriginalSubject: "first", EnvelopeDateFormatted: "2025-03-10T10:00:00Z", From: "Jane | Doe <jane@example.com>", Size: 2048},
+		{OriginalSubject: "second *bold*", ModifiedSubject: "second bold", EnvelopeDateFormatted: "2025-03-10T11:00:00Z"},
+	}
+
+	retained := ReportData{
+		AccountName: "retained",
+		MailboxCheckResults: mbxs.MailboxCheckResults{
+			{MailboxName: "INBOX", ItemsFound: len(messages), Messages: messages},
+			{MailboxName: "Junk"},
+		},
+		ReportTime: reportTime,
+	}
+
+	dir := t.TempDir()
+
+	spool, err := NewMessageSpool(dir)
+	if err != nil {
+		t.Fatalf("failed to create spool: %v", err)
+	}
+	for _, msg := range messages {
+		if err := spool.Add("INBOX", msg); err != nil {
+			t.Fatalf("failed to add message to spool: %v", err)
+		}
+	}
+
+	rows, err := spool.Reader()
+	if err != nil {
+		t.Fatalf("failed to read spool: %v", err)
+	}
+
+	streamed := retained
+	streamed.AccountName = "streamed"
+	streamed.MailboxCheckResults = mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX", ItemsFound: len(messages)},
+		{MailboxName: "Junk"},
+	}
+	streamed.MessageRows = rows
+
+	for _, data := range []ReportData{retained, streamed} {
+		if err := GenerateReport(data, dir, zerolog.Nop()); err != nil {
+			t.Fatalf("failed to generate %s report: %v", data.AccountName, err)
+		}
+	}
+
+	if err := spool.Close(); err != nil {
+		t.Errorf("failed to close spool: %v", err)
+	}
+
+	readReport := func(account string) string {
+		t.Helper()
+
+		b, err := os.ReadFile(filepath.Join(dir, reportFilename(account, reportTime)))
+		if err != nil {
+			t.Fatalf("failed to read %s report: %v", account, err)
+		}
+
+		return string(b)
+	}
+
+	// Only the account name heading is expected to differ.
+	want := readReport("retained")
+	got := strings.Replace(readReport("streamed"), "h4. streamed", "h4. retained", 1)
+
+	if want != got {
+		t.Errorf("reports differ\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	if !strings.Contains(got, "| Jane &#124; Doe <jane@example.com> |") {
+		t.Errorf("want sender escaped for Textile, got:\n%s", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read report directory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("want spool file removed leaving 2 reports, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package files
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+)
+
+// stateFilenameTemplate is the filename pattern used for account state
+// files. The application name, server, account name and state scope hash
+// are substituted.
+const stateFilenameTemplate string = "%s_%s_%s_%s.json"
+
+// stateScopeHashLength is the number of bytes of the state scope hash used
+// in state filenames.
+const stateScopeHashLength int = 6
+
+// stateTempFilePattern is the filename pattern used for temporary files
+// created while updating an account state file.
+const stateTempFilePattern string = ".state-*.tmp"
+
+// ErrInvalidStateFile indicates that an account state file could not be
+// parsed.
+var ErrInvalidStateFile = errors.New("invalid state file")
+
+// AccountState records the results of previous checks for an account. This
+// is used to limit later checks to messages which arrived since the
+// previous check (delta mode) and to determine the rate at which messages
+// arrive in and are removed from each folder.
+type AccountState struct {
+	// Account is the name of the account (e.g., username or shared mailbox).
+	Account string `json:"account"`
+
+	// Server is the name of the server hosting the account.
+	Server string `json:"server"`
+
+	// Updated is when the state was last updated.
+	Updated time.Time `json:"updated"`
+
+	// Folders is the position reached in each checked folder by the
+	// previous check indexed by folder name.
+	Folders map[string]mbxs.Checkpoint `json:"folders"`
+
+	// History is the collection of samples recorded by previous checks for
+	// each folder indexed by folder name, oldest first.
+	History map[string][]mbxs.MailboxSample `json:"history,omitempty"`
+}
+
+// StateFilename returns the path to the state file for the given account
+// within the specified state directory. The application name is included so
+// that applications checking the same account do not share state. A hash of
+// the given scope (e.g., the folders and message filter checked) is
+// included so that checks of the same account using different folders or
+// filters do not share state.
+func StateFilename(stateDirectory string, appName string, server string, account string, scope string) string {
+	scopeHash := sha256.Sum256([]byte(scope))
+
+	return filepath.Join(
+		stateDirectory,
+		fmt.Sprintf(
+			stateFilenameTemplate,
+			stateFilenameComponent(appName),
+			stateFilenameComponent(server),
+			stateFilenameComponent(account),
+			hex.EncodeToString(scopeHash[:stateScopeHashLength]),
+		),
+	)
+}
+
+// stateFilenameComponent replaces characters in the given value which are
+// not safe for use in filenames.
+func stateFilenameComponent(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z',
+			r >= 'A' && r <= 'Z',
+			r >= '0' && r <= '9',
+			r == '.', r == '-', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+}
+
+// LoadAccountState reads the account state from the specified file. An empty
+// state is returned if the file does not exist (e.g., for the first check).
+func LoadAccountState(filename string) (AccountState, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return AccountState{}, nil
+	case err != nil:
+		return AccountState{}, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state AccountState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return AccountState{}, fmt.Errorf(
+			"failed to parse state file %s: %v: %w",
+			filename,
+			err,
+			ErrInvalidStateFile,
+		)
+	}
+
+	return state, nil
+}
+
+// Checkpoints returns the position reached in each folder by the previous
+// check. A non-nil (possibly empty) collection is always returned so that
+// it can be used to enable delta mode.
+func (as AccountState) Checkpoints() mbxs.Checkpoints {
+	checkpoints := make(mbxs.Checkpoints, len(as.Folders))
+	for folder, cp := range as.Folders {
+		checkpoints[folder] = cp
+	}
+
+	return checkpoints
+}
+
+// Update records the position reached in each folder by the given check
+// results. The previous position is retained for folders which could not be
+// checked so that messages which arrived since are not missed.
+func (as *AccountState) Update(results mbxs.MailboxCheckResults, now time.Time) {
+	if as.Folders == nil {
+		as.Folders = make(map[string]mbxs.Checkpoint, len(results))
+	}
+
+	for _, result := range results {
+		if result.Err != nil || result.Checkpoint.IsZero() {
+			continue
+		}
+
+		as.Folders[result.MailboxName] = result.Checkpoint
+	}
+
+	as.Updated = now
+}
+
+// Record adds a sample of each folder recorded by the given check results to
+// the history. Samples older than the given retention period are discarded
+// except for the newest of them so that the full retention period remains
+// covered. All previous samples for a folder are discarded if its
+// UIDVALIDITY value has changed.
+func (as *AccountState) Record(results mbxs.MailboxCheckResults, now time.Time, retention time.Duration) {
+	if as.History == nil {
+		as.History = make(map[string][]mbxs.MailboxSample, len(results))
+	}
+
+	for _, result := range results {
+		sample, ok := result.Sample(now)
+		if !ok {
+			continue
+		}
+
+		as.History[result.MailboxName] = trimSamples(
+			append(as.History[result.MailboxName], sample),
+			now.Add(-retention),
+		)
+	}
+
+	as.Updated = now
+}
+
+// trimSamples returns the given samples (oldest first) recorded with the same
+// UIDVALIDITY value as the newest sample and after the given cutoff time
+// along with the newest sample recorded on or before the cutoff time.
+func trimSamples(samples []mbxs.MailboxSample, cutoff time.Time) []mbxs.MailboxSample {
+	newest := samples[len(samples)-1]
+
+	start := len(samples) - 1
+	for start > 0 {
+		previous := samples[start-1]
+		if previous.UIDValidity != newest.UIDValidity {
+			break
+		}
+
+		start--
+		if !previous.Time.After(cutoff) {
+			break
+		}
+	}
+
+	return slices.Clone(samples[start:])
+}
+
+// SaveAccountState writes the given account state to the specified file,
+// creating the parent directory if needed. The file is replaced atomically
+// so that an interrupted update does not leave a partial file behind.
+func SaveAccountState(filename string, state AccountState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, defaultDirectoryPerms); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, stateTempFilePattern)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	tempName := f.Name()
+
+	_, writeErr := f.Write(data)
+	closeErr := f.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tempName)
+
+		return fmt.Errorf("failed to write temporary state file: %w", err)
+	}
+
+	if err := os.Rename(tempName, filename); err != nil {
+		_ = os.Remove(tempName)
+
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
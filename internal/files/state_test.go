@@ -0,0 +1,175 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package files
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-mail/internal/mbxs"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestAccountStateRoundTrip asserts that account state is loaded empty for
+// the first check, saved and reloaded and that folders which could not be
+// checked retain their previous position.
+func TestAccountStateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	filename := StateFilename(
+		filepath.Join(t.TempDir(), "state"),
+		"check_imap_mailbox_basic",
+		"imap.example.com",
+		"user@example.com",
+		`folders=["INBOX"]`,
+	)
+
+	if want, got := "check_imap_mailbox_basic_imap.example.com_user@example.com_", filepath.Base(filename); !strings.HasPrefix(got, want) || filepath.Ext(got) != ".json" {
+		t.Errorf("want state filename starting with %q, got %q", want, got)
+	}
+
+	state, err := LoadAccountState(filename)
+	if err != nil {
+		t.Fatalf("unexpected error loading missing state file: %v", err)
+	}
+
+	if checkpoints := state.Checkpoints(); checkpoints == nil || len(checkpoints) != 0 {
+		t.Errorf("want empty, non-nil checkpoints; got %#v", checkpoints)
+	}
+
+	first := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	state.Update(mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX", Checkpoint: mbxs.Checkpoint{UIDValidity: 7, HighestUID: 99}},
+		{MailboxName: "Triage", Checkpoint: mbxs.Checkpoint{UIDValidity: 3, HighestUID: 12}},
+	}, first)
+
+	if err := SaveAccountState(filename, state); err != nil {
+		t.Fatalf("unexpected error saving state file: %v", err)
+	}
+
+	second := first.Add(5 * time.Minute)
+	state.Update(mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX", Checkpoint: mbxs.Checkpoint{UIDValidity: 7, HighestUID: 104}},
+		{MailboxName: "Triage", Err: errors.New("error occurred examining mailbox")},
+	}, second)
+
+	if err := SaveAccountState(filename, state); err != nil {
+		t.Fatalf("unexpected error saving state file: %v", err)
+	}
+
+	loaded, err := LoadAccountState(filename)
+	if err != nil {
+		t.Fatalf("unexpected error loading state file: %v", err)
+	}
+
+	want := mbxs.Checkpoints{
+		"INBOX":  {UIDValidity: 7, HighestUID: 104},
+		"Triage": {UIDValidity: 3, HighestUID: 12},
+	}
+	if d := cmp.Diff(want, loaded.Checkpoints()); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	if !loaded.Updated.Equal(second) {
+		t.Errorf("want updated time %v, got %v", second, loaded.Updated)
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(filename), stateTempFilePattern))
+	if err != nil {
+		t.Fatalf("failed to list temporary state files: %v", err)
+	}
+	if len(leftovers) != 0 {
+		t.Errorf("want no temporary state files, got %v", leftovers)
+	}
+}
+
+// TestStateFilenameScope asserts that checks of the same account using
+// different folders or message filters are given separate state files.
+func TestStateFilenameScope(t *testing.T) {
+	t.Parallel()
+
+	filename := func(scope string) string {
+		return StateFilename("state", "check_imap_mailbox_basic", "imap.example.com", "user@example.com", scope)
+	}
+
+	inbox := filename(`folders=["INBOX"];criteria=[]`)
+
+	if got := filename(`folders=["INBOX"];criteria=[]`); got != inbox {
+		t.Errorf("want same state file %q for same scope, got %q", inbox, got)
+	}
+
+	for _, scope := range []string{
+		`folders=["Archive"];criteria=[]`,
+		`folders=["INBOX"];criteria=["unseen"]`,
+	} {
+		if got := filename(scope); got == inbox {
+			t.Errorf("want separate state file for scope %q, got %q", scope, got)
+		}
+	}
+}
+
+// TestLoadAccountStateInvalid asserts that a corrupt state file is reported
+// instead of silently resetting delta mode.
+func TestLoadAccountStateInvalid(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(filename, []byte("{not json"), 0600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	if _, err := LoadAccountState(filename); !errors.Is(err, ErrInvalidStateFile) {
+		t.Errorf("want error %v, got %v", ErrInvalidStateFile, err)
+	}
+}
+
+// TestAccountStateRecord asserts that a sample is recorded for each checked
+// folder and that history no longer needed to cover the retention period or
+// recorded for a previous UIDVALIDITY value is discarded.
+func TestAccountStateRecord(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	var state AccountState
+	for i, highestUID := range []uint32{10, 12, 15, 20} {
+		state.Record(mbxs.MailboxCheckResults{
+			{MailboxName: "INBOX", MailboxMessages: int(highestUID), Checkpoint: mbxs.Checkpoint{UIDValidity: 7, HighestUID: highestUID}},
+		}, start.Add(time.Duration(i)*30*time.Minute), time.Hour)
+	}
+
+	// The sample recorded at 12:30 is the newest recorded at least an hour
+	// before the last check at 13:30 and is retained; 12:00 is discarded.
+	want := []mbxs.MailboxSample{
+		{Time: start.Add(30 * time.Minute), Messages: 12, UIDValidity: 7, HighestUID: 12},
+		{Time: start.Add(60 * time.Minute), Messages: 15, UIDValidity: 7, HighestUID: 15},
+		{Time: start.Add(90 * time.Minute), Messages: 20, UIDValidity: 7, HighestUID: 20},
+	}
+	if d := cmp.Diff(want, state.History["INBOX"]); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	now := start.Add(2 * time.Hour)
+	state.Record(mbxs.MailboxCheckResults{
+		{MailboxName: "INBOX", MailboxMessages: 3, Checkpoint: mbxs.Checkpoint{UIDValidity: 8, HighestUID: 3}},
+		{MailboxName: "Triage", Err: errors.New("error occurred examining mailbox")},
+	}, now, time.Hour)
+
+	want = []mbxs.MailboxSample{{Time: now, Messages: 3, UIDValidity: 8, HighestUID: 3}}
+	if d := cmp.Diff(want, state.History["INBOX"]); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	if _, ok := state.History["Triage"]; ok {
+		t.Error("want no history for folder which could not be checked")
+	}
+}
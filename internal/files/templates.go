@@ -23,7 +23,13 @@ const ReportFilenameDateLayout string = "2006-01-02-15_04"
 // reportFileTemplateText is used when generating the output report file. This
 // file is provided to the team as part of the daily email checks (e.g.,
 // software updates, vulnerability announcements, etc.).
-const reportFileTemplateText string = `
+const reportFileTemplateText string = reportHeaderTemplateText +
+	reportMessageRowsTemplateText +
+	reportFooterTemplateText
+
+// reportHeaderTemplateText is the portion of the report file template
+// preceding the rows of the "Emails found" table.
+const reportHeaderTemplateText string = `
 h4. {{ .AccountName }}
 
 h5. Overview
@@ -32,19 +38,40 @@ h5. Overview
 | Summary | {{ .MessagesFoundSummary }} |
 | Placeholder character | {{ .UnicodeCharSubstitute }} (substituted for Emoji incompatible with MySQL utf8mb3 character set) |
 
-h5. Emails found
+h5. Message ages
 
-|_.Folder|_.Subject|_.Date|
+|_.Folder|_.Messages|_.Oldest message|_.Newest message|
 {{ range .MailboxCheckResults -}}
+| {{ .MailboxName }} | {{ .ItemsFound }} | {{ messageAge .OldestMessageDate $.ReportTime }} | {{ messageAge .NewestMessageDate $.ReportTime }} |
+{{ end }}
+h5. Emails found
+
+|_.Folder|_.From|_.Subject|_.Date|_.Size|
+`
+
+// reportMessageRowsTemplateText is the portion of the report file template
+// generating the rows of the "Emails found" table. The rows are written from
+// a spool file instead if messages were streamed as they were retrieved. See
+// reportMessageRowFormat.
+const reportMessageRowsTemplateText string = `{{ range .MailboxCheckResults -}}
 {{- $mailboxName := .MailboxName -}}
 {{- range .Messages -}}
 {{- if .ModifiedSubject -}}
-| {{ $mailboxName }} | {{ .ModifiedSubject }} | {{ .EnvelopeDateFormatted }} |
+| {{ $mailboxName }} | {{ textile .From }} | {{ .ModifiedSubject }} | {{ .EnvelopeDateFormatted }} | {{ .SizeFormatted }} |
 {{- else -}}
-| {{ $mailboxName }} | {{ .OriginalSubject }} | {{ .EnvelopeDateFormatted }} |
+| {{ $mailboxName }} | {{ textile .From }} | {{ .OriginalSubject }} | {{ .EnvelopeDateFormatted }} | {{ .SizeFormatted }} |
 {{- end }}
 {{ end -}}
-{{ end }}
+{{ end }}`
+
+// reportMessageRowFormat is the format of a single row of the "Emails found"
+// table written to a spool file. This matches the rows generated by
+// reportMessageRowsTemplateText.
+const reportMessageRowFormat string = "| %s | %s | %s | %s | %s |\n"
+
+// reportFooterTemplateText is the portion of the report file template
+// following the rows of the "Emails found" table.
+const reportFooterTemplateText string = `
 
 h5. Reported emails
 
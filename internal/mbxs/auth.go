@@ -9,7 +9,6 @@ package mbxs
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
@@ -19,12 +18,6 @@ import (
 	"github.com/rs/zerolog"
 )
 
-var (
-	// ErrRequiredAuthMechanismUnsupported indicates that a required
-	// authentication mechanism is unsupported.
-	ErrRequiredAuthMechanismUnsupported = errors.New("required auth mechanism unsupported")
-)
-
 // Login uses the provided client connection and credentials to login to the
 // IMAP server using plaintext authentication. Most servers will reject logins
 // unless TLS is used.
@@ -47,7 +40,8 @@ func Login(c *client.Client, username string, password string, logger zerolog.Lo
 	if err != nil {
 		logger.Debug().Err(err).Msg("Unable to list server capabilities")
 		return fmt.Errorf(
-			"unable to list server capabilities: %w",
+			"unable to list server capabilities: %w: %w",
+			commandErrorKind(err, ErrProtocol),
 			err,
 		)
 	}
@@ -66,14 +60,16 @@ func Login(c *client.Client, username string, password string, logger zerolog.Lo
 	loginDisabled, capErr := c.Support(IMAPv4CapabilityLoginDisabled)
 	if capErr != nil {
 		return fmt.Errorf(
-			"failed to detect support for logins: %w",
+			"failed to detect support for logins: %w: %w",
+			commandErrorKind(capErr, ErrProtocol),
 			capErr,
 		)
 	}
 
 	if loginDisabled {
 		return fmt.Errorf(
-			"server has disabled logins: %w",
+			"server has disabled logins: %w: %w",
+			ErrRequiredAuthMechanismUnsupported,
 			client.ErrLoginDisabled,
 		)
 	}
@@ -83,7 +79,7 @@ func Login(c *client.Client, username string, password string, logger zerolog.Lo
 		errMsg := "login error occurred"
 		logger.Error().Err(err).Msg(errMsg)
 
-		return fmt.Errorf("%s: %w", errMsg, err)
+		return fmt.Errorf("%s: %w: %w", errMsg, commandErrorKind(err, ErrAuthRejected), err)
 	}
 	logger.Debug().Msg("Logged in")
 
@@ -133,17 +129,95 @@ func OAuth2ClientCredsAuth(
 	)
 	if err != nil {
 		logger.Debug().Err(err).Msg("Failed to retrieve token")
+		return fmt.Errorf(
+			"failed to authenticate: %w: %w",
+			commandErrorKind(err, ErrAuthRejected),
+			err,
+		)
+	}
+	logger.Debug().
+		Str("token_expiration", token.Expiry.Format(time.RFC3339)).
+		Str("token_type", token.Type()).
+		Msg("Token acquired")
+
+	return xoauth2Auth(imapClient, mailbox, token.AccessToken, logger)
+
+}
+
+// OAuth2JWTBearerAuth uses the provided client connection and service
+// account key file to obtain a token via the OAuth2 JWT bearer grant while
+// impersonating the given subject (domain-wide delegation). The token is
+// then used to authenticate as the mailbox user.
+//
+// The XOAUTH2 authentication mechanism is used as described in
+// https://developers.google.com/gmail/xoauth2_protocol.
+func OAuth2JWTBearerAuth(
+	ctx context.Context,
+	imapClient *client.Client,
+	mailbox string,
+	serviceAccountKeyFile string,
+	subject string,
+	scopes []string,
+	tokenEndpointURL string,
+	maxAttempts int,
+	logger zerolog.Logger,
+) error {
+
+	if imapClient == nil {
+		logger.Error().Str("subject", subject).Msg("invalid (nil) client received while attempting login")
+
+		return fmt.Errorf("invalid (nil) client received while attempting login for subject: %v", subject)
+	}
+
+	// Due to logic applied during connection establishment this is highly
+	// unlikely to be true, but on the mischance that it is we issue a
+	// warning.
+	if !imapClient.IsTLS() {
+		logger.Warn().Msg("WARNING: Connection to server is insecure (TLS is not enabled)")
+	}
+
+	key, err := oauth2.ReadServiceAccountKeyFile(serviceAccountKeyFile)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Failed to load service account key")
 		return fmt.Errorf(
 			"failed to authenticate: %w",
 			err,
 		)
 	}
+
+	logger.Debug().
+		Str("service_account", key.ClientEmail).
+		Str("subject", subject).
+		Msg("Acquiring fresh token via JWT bearer grant")
+
+	token, err := oauth2.GetJWTBearerToken(
+		ctx,
+		key,
+		subject,
+		scopes,
+		tokenEndpointURL,
+		maxAttempts,
+	)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Failed to retrieve token")
+		return fmt.Errorf(
+			"failed to authenticate: %w: %w",
+			commandErrorKind(err, ErrAuthRejected),
+			err,
+		)
+	}
 	logger.Debug().
 		Str("token_expiration", token.Expiry.Format(time.RFC3339)).
 		Str("token_type", token.Type()).
 		Msg("Token acquired")
 
-	accessToken := token.AccessToken
+	return xoauth2Auth(imapClient, mailbox, token.AccessToken, logger)
+
+}
+
+// xoauth2Auth uses the provided client connection and access token to
+// authenticate as the given mailbox user via the XOAUTH2 mechanism.
+func xoauth2Auth(imapClient *client.Client, mailbox string, accessToken string, logger zerolog.Logger) error {
 
 	// NOTE: Security concern. Perhaps log at trace level to console instead
 	// of the normal logger output path (e.g., if normally a file, send to
@@ -157,7 +231,8 @@ func OAuth2ClientCredsAuth(
 	if err != nil {
 		logger.Debug().Err(err).Msg("Unable to list server capabilities")
 		return fmt.Errorf(
-			"unable to list server capabilities: %w",
+			"unable to list server capabilities: %w: %w",
+			commandErrorKind(err, ErrProtocol),
 			err,
 		)
 	}
@@ -177,8 +252,9 @@ func OAuth2ClientCredsAuth(
 			Msg("Failed to confirm mechanism support")
 
 		return fmt.Errorf(
-			"failed to confirm support for mechanism %s: %w",
+			"failed to confirm support for mechanism %s: %w: %w",
 			sasl.Xoauth2,
+			commandErrorKind(err, ErrProtocol),
 			err,
 		)
 	}
@@ -205,12 +281,12 @@ func OAuth2ClientCredsAuth(
 		logger.Debug().
 			Err(err).
 			Str("mechanism", sasl.Xoauth2).
-			Str("client_id", clientID).
 			Str("mailbox", mailbox).
 			Msg("Failed to authenticate.")
 
 		return fmt.Errorf(
-			"failed to authenticate: %w",
+			"failed to authenticate: %w: %w",
+			commandErrorKind(err, ErrAuthRejected),
 			err,
 		)
 	}
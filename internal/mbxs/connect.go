@@ -26,7 +26,7 @@ func openConnection(addrs []string, port int, dialer Dialer, tlsConfig *tls.Conf
 	if len(addrs) < 1 {
 		logger.Error().Msg("empty list of IP Addresses received")
 
-		return nil, fmt.Errorf("empty list of IP Addresses received")
+		return nil, fmt.Errorf("empty list of IP Addresses received: %w", ErrConnectFailed)
 	}
 
 	var c *client.Client
@@ -86,7 +86,12 @@ func openConnection(addrs []string, port int, dialer Dialer, tlsConfig *tls.Conf
 			Str("failed_ip_addresses", strings.Join(addrs, ", ")).
 			Msg(errMsg)
 
-		return nil, fmt.Errorf("%s; last error: %w", errMsg, connectErr)
+		return nil, fmt.Errorf(
+			"%s: %w; last error: %w",
+			errMsg,
+			connectErrorKind(connectErr),
+			connectErr,
+		)
 	}
 
 	return c, nil
@@ -109,8 +114,9 @@ func Connect(server string, port int, netType string, minTLSVer uint16, logger z
 		logger.Error().Err(lookupErr).Msg(errMsg)
 
 		return nil, fmt.Errorf(
-			"error resolving hostname %s: %w",
+			"error resolving hostname %s: %w: %w",
 			server,
+			ErrDNSLookupFailed,
 			lookupErr,
 		)
 	}
@@ -119,7 +125,11 @@ func Connect(server string, port int, netType string, minTLSVer uint16, logger z
 	case len(lookupResults) < 1:
 		logger.Error().Str("server", server).Msg("failed to resolve hostname to IP Addresses")
 
-		return nil, fmt.Errorf("failed to resolve hostname to IP Addresses: %v", server)
+		return nil, fmt.Errorf(
+			"failed to resolve hostname %s to IP Addresses: %w",
+			server,
+			ErrDNSLookupFailed,
+		)
 
 	default:
 		logger.Debug().
@@ -138,8 +148,9 @@ func Connect(server string, port int, netType string, minTLSVer uint16, logger z
 		ip := net.ParseIP(lookupResults[i])
 		if ip == nil {
 			return nil, fmt.Errorf(
-				"error parsing %s as an IP Address",
+				"error parsing %s as an IP Address: %w",
 				lookupResults[i],
+				ErrDNSLookupFailed,
 			)
 		}
 		ips = append(ips, ip)
@@ -156,9 +167,10 @@ func Connect(server string, port int, netType string, minTLSVer uint16, logger z
 			Msg("failed to to convert DNS lookup results to net.IP values after receiving DNS lookup results")
 
 		return nil, fmt.Errorf(
-			"failed to to convert DNS lookup results to net.IP values after receiving %d DNS lookup results ([%s])",
+			"failed to to convert DNS lookup results to net.IP values after receiving %d DNS lookup results ([%s]): %w",
 			numLookupResults,
 			lookupResultsList,
+			ErrDNSLookupFailed,
 		)
 
 	default:
@@ -213,9 +225,10 @@ func Connect(server string, port int, netType string, minTLSVer uint16, logger z
 			Msg("failed to gather IP Addresses for connection attempts after receiving and parsing DNS lookup results")
 
 		return nil, fmt.Errorf(
-			"failed to gather IP Addresses for connection attempts after receiving and parsing %d DNS lookup results ([%s])",
+			"failed to gather IP Addresses for connection attempts after receiving and parsing %d DNS lookup results ([%s]): %w",
 			numLookupResults,
 			lookupResultsList,
+			ErrDNSLookupFailed,
 		)
 
 	default:
@@ -239,9 +252,10 @@ func Connect(server string, port int, netType string, minTLSVer uint16, logger z
 
 	if c == nil {
 		return nil, fmt.Errorf(
-			"failed to create client connection to %s using any of IPs %s",
+			"failed to create client connection to %s using any of IPs %s: %w",
 			server,
 			strings.Join(addrs, ", "),
+			ErrConnectFailed,
 		)
 	}
 
@@ -13,12 +13,21 @@ import "github.com/atc0005/check-mail/internal/textutils"
 // mailboxes will need to be checked on a remote mail server.
 const mailboxCountGuesstimate int = 5
 
+// DefaultFetchBatchSize is the default number of messages retrieved per
+// FETCH command when listing messages. Retrieving messages in batches limits
+// the size of individual server responses for very large mailboxes.
+const DefaultFetchBatchSize int = 500
+
 // DefaultReplacementString is used when replacing Unicode characters
 // incompatible with a target character set. The common use case is
 // substituting Unicode characters incompatible with the utf8mb3 character
 // set.
 const DefaultReplacementString string = textutils.EmojiScissors
 
+// RedactedValue is used in place of credentials in IMAP protocol debug
+// output.
+const RedactedValue string = "[REDACTED]"
+
 // Known, named networks used for IMAP connections. These names match the
 // network names used by the `net` standard library package.
 const (
@@ -41,6 +50,12 @@ const (
 	// plaintext password authenticating this user.
 	// https://datatracker.ietf.org/doc/html/rfc3501#section-6.2.3
 	IMAPv4CommandLogin string = "LOGIN"
+
+	// The AUTHENTICATE command indicates a SASL authentication mechanism to
+	// the server. Any initial response and the client responses which
+	// follow carry credentials (e.g., an XOAUTH2 bearer token).
+	// https://datatracker.ietf.org/doc/html/rfc3501#section-6.2.2
+	IMAPv4CommandAuthenticate string = "AUTHENTICATE"
 )
 
 // IMAP capabilities
@@ -51,4 +66,38 @@ const (
 	// connection.
 	// https://datatracker.ietf.org/doc/html/rfc3501#section-6.1.1
 	IMAPv4CapabilityLoginDisabled string = "LOGINDISABLED"
+
+	// The ENABLE capability indicates that the ENABLE command may be used to
+	// enable protocol extensions.
+	// https://datatracker.ietf.org/doc/html/rfc5161
+	IMAPv4CapabilityEnable string = "ENABLE"
+
+	// The UTF8=ACCEPT capability indicates that the server accepts and (once
+	// enabled) sends UTF-8 mailbox names instead of modified UTF-7.
+	// https://datatracker.ietf.org/doc/html/rfc6855
+	IMAPv4CapabilityUTF8Accept string = "UTF8=ACCEPT"
+
+	// The QUOTA capability indicates that the server supports retrieving
+	// mailbox quota usage and limits.
+	// https://datatracker.ietf.org/doc/html/rfc9208
+	IMAPv4CapabilityQuota string = "QUOTA"
+)
+
+// Message criteria used to limit the messages counted and listed within a
+// mailbox. Criteria are applied by the server using the SEARCH command.
+const (
+
+	// MessageCriterionUnseen matches messages without the \Seen flag.
+	MessageCriterionUnseen string = "unseen"
+
+	// MessageCriterionFlagged matches messages with the \Flagged flag.
+	MessageCriterionFlagged string = "flagged"
+
+	// MessageCriterionUnanswered matches messages without the \Answered
+	// flag.
+	MessageCriterionUnanswered string = "unanswered"
+
+	// MessageCriterionDeleted matches messages with the \Deleted flag which
+	// have not yet been expunged.
+	MessageCriterionDeleted string = "deleted"
 )
@@ -0,0 +1,86 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/emersion/go-imap"
+)
+
+var (
+	// ErrInvalidMessageCriterion indicates that an invalid or unsupported
+	// message criterion was specified.
+	ErrInvalidMessageCriterion = errors.New("invalid message criterion")
+)
+
+// SearchCriteria converts the given message criteria into IMAP SEARCH
+// criteria. All given criteria must match for a message to be included. A
+// nil value is returned if no criteria are given.
+func SearchCriteria(criteria []string) (*imap.SearchCriteria, error) {
+	if len(criteria) == 0 {
+		return nil, nil
+	}
+
+	search := imap.NewSearchCriteria()
+	for _, criterion := range criteria {
+		switch strings.ToLower(criterion) {
+		case MessageCriterionUnseen:
+			search.WithoutFlags = append(search.WithoutFlags, imap.SeenFlag)
+		case MessageCriterionFlagged:
+			search.WithFlags = append(search.WithFlags, imap.FlaggedFlag)
+		case MessageCriterionUnanswered:
+			search.WithoutFlags = append(search.WithoutFlags, imap.AnsweredFlag)
+		case MessageCriterionDeleted:
+			search.WithFlags = append(search.WithFlags, imap.DeletedFlag)
+		default:
+			return nil, fmt.Errorf(
+				"unsupported criterion %q: %w",
+				criterion,
+				ErrInvalidMessageCriterion,
+			)
+		}
+	}
+
+	return search, nil
+}
+
+// unseenSearch returns a copy of the given search criteria narrowed to
+// messages without the \Seen flag. All unseen messages are matched if no
+// search criteria are given.
+func unseenSearch(search *imap.SearchCriteria) *imap.SearchCriteria {
+	criteria := imap.NewSearchCriteria()
+	if search != nil {
+		copied := *search
+		criteria = &copied
+	}
+
+	criteria.WithoutFlags = append(slices.Clone(criteria.WithoutFlags), imap.SeenFlag)
+
+	return criteria
+}
+
+// unseenOnly indicates whether the given message filter consists solely of
+// the unseen criterion. The number of unseen messages is available via the
+// (lightweight) STATUS command.
+func unseenOnly(filter MessageFilter) bool {
+	if len(filter.Criteria) == 0 || len(filter.Include) > 0 || len(filter.Exclude) > 0 {
+		return false
+	}
+
+	for _, criterion := range filter.Criteria {
+		if !strings.EqualFold(criterion, MessageCriterionUnseen) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/emersion/go-imap"
+)
+
+// literalMarkerRegex matches an IMAP literal (synchronizing or not) at the
+// end of a line. The literal content follows on the next line(s).
+// https://datatracker.ietf.org/doc/html/rfc7888
+var literalMarkerRegex = regexp.MustCompile(`\{\d+\+?\}$`)
+
+// NewRedactingDebugWriter returns an io.Writer intended for use with the
+// go-imap client SetDebug method. IMAP protocol traffic is passed through to
+// the given writer one line at a time with credentials masked:
+//
+//   - the password argument of LOGIN commands
+//   - the initial response of AUTHENTICATE commands and all client SASL
+//     responses which follow
+//   - server continuation requests carrying SASL challenge data
+func NewRedactingDebugWriter(w io.Writer) io.Writer {
+	var mu sync.Mutex
+
+	local := &redactingWriter{dst: w, mu: &mu, redact: redactClientLine}
+	remote := &redactingWriter{dst: w, mu: &mu, redact: redactServerLine}
+
+	return imap.NewDebugWriter(local, remote)
+}
+
+// redactingWriter buffers written data until a complete line is available
+// and then writes the redacted line to the destination writer.
+type redactingWriter struct {
+	dst    io.Writer
+	mu     *sync.Mutex
+	buf    bytes.Buffer
+	state  redactState
+	redact func(line string, state *redactState) string
+}
+
+// redactState tracks protocol state spanning multiple lines.
+type redactState struct {
+	// inSASLExchange indicates that an AUTHENTICATE command was sent and
+	// client responses are expected.
+	inSASLExchange bool
+
+	// redactLiteral indicates that the next line carries literal data
+	// belonging to a LOGIN command.
+	redactLiteral bool
+}
+
+// Write implements the io.Writer interface.
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	rw.buf.Write(p)
+
+	for {
+		idx := bytes.IndexByte(rw.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := strings.TrimRight(string(rw.buf.Next(idx+1)), "\r\n")
+		redacted := rw.redact(line, &rw.state) + "\r\n"
+
+		rw.mu.Lock()
+		_, err := io.WriteString(rw.dst, redacted)
+		rw.mu.Unlock()
+
+		if err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// redactClientLine masks credentials in a line sent by the client.
+func redactClientLine(line string, state *redactState) string {
+	if state.redactLiteral {
+		state.redactLiteral = literalMarkerRegex.MatchString(line)
+		return RedactedValue
+	}
+
+	fields := strings.Fields(line)
+
+	// SASL client responses are base64 encoded (or "*" to cancel) and do not
+	// contain spaces. Anything else is a new command.
+	if state.inSASLExchange {
+		switch len(fields) {
+		case 0:
+			return line
+		case 1:
+			return RedactedValue
+		}
+		state.inSASLExchange = false
+	}
+
+	if len(fields) < 2 {
+		return line
+	}
+
+	tag, command := fields[0], strings.ToUpper(fields[1])
+
+	switch command {
+	case IMAPv4CommandLogin:
+		if len(fields) < 3 {
+			return line
+		}
+
+		// A username sent as a literal carries the password on a following
+		// line; a password sent as a literal carries it on the next line.
+		state.redactLiteral = literalMarkerRegex.MatchString(line)
+
+		return strings.Join([]string{tag, fields[1], fields[2], RedactedValue}, " ")
+
+	case IMAPv4CommandAuthenticate:
+		state.inSASLExchange = true
+
+		if len(fields) < 3 {
+			return line
+		}
+
+		redacted := []string{tag, fields[1], fields[2]}
+		if len(fields) > 3 {
+			redacted = append(redacted, RedactedValue)
+		}
+
+		return strings.Join(redacted, " ")
+	}
+
+	return line
+}
+
+// redactServerLine masks SASL challenge data in continuation requests sent
+// by the server. Continuation requests with human-readable text (e.g., "+
+// Ready for literal data") are left as-is.
+func redactServerLine(line string, _ *redactState) string {
+	if !strings.HasPrefix(line, "+ ") {
+		return line
+	}
+
+	data := strings.TrimSpace(strings.TrimPrefix(line, "+ "))
+	if data == "" || strings.Contains(data, " ") {
+		return line
+	}
+
+	return "+ " + RedactedValue
+}
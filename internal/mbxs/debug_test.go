@@ -0,0 +1,95 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestRedactingWriterClient asserts that credentials sent by the client are
+// masked, including when written in partial chunks.
+func TestRedactingWriterClient(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input   string
+		want    string
+		secrets []string
+	}{
+		"login": {
+			input:   "A1 LOGIN \"user@example.com\" \"hunter2\"\r\nA2 SELECT INBOX\r\n",
+			want:    "A1 LOGIN \"user@example.com\" [REDACTED]\r\nA2 SELECT INBOX\r\n",
+			secrets: []string{"hunter2"},
+		},
+		"login password literal": {
+			input:   "A1 LOGIN user {7}\r\nhunter2\r\nA2 SELECT INBOX\r\n",
+			want:    "A1 LOGIN user [REDACTED]\r\n[REDACTED]\r\nA2 SELECT INBOX\r\n",
+			secrets: []string{"hunter2"},
+		},
+		"authenticate initial response": {
+			input:   "A1 AUTHENTICATE XOAUTH2 dXNlcj1zZWNyZXQ=\r\nA2 LIST \"\" *\r\n",
+			want:    "A1 AUTHENTICATE XOAUTH2 [REDACTED]\r\nA2 LIST \"\" *\r\n",
+			secrets: []string{"dXNlcj1zZWNyZXQ="},
+		},
+		"authenticate continuation responses": {
+			input:   "A1 AUTHENTICATE PLAIN\r\nAHVzZXIAaHVudGVyMg==\r\nA2 LOGOUT\r\n",
+			want:    "A1 AUTHENTICATE PLAIN\r\n[REDACTED]\r\nA2 LOGOUT\r\n",
+			secrets: []string{"AHVzZXIAaHVudGVyMg=="},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			w := &redactingWriter{dst: &out, mu: &sync.Mutex{}, redact: redactClientLine}
+
+			// Write in small chunks to mimic buffered network writes.
+			for i := 0; i < len(tt.input); i += 5 {
+				end := min(i+5, len(tt.input))
+				if _, err := w.Write([]byte(tt.input[i:end])); err != nil {
+					t.Fatalf("write failed: %v", err)
+				}
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("\nwant %q\ngot  %q", tt.want, got)
+			}
+
+			for _, secret := range tt.secrets {
+				if strings.Contains(out.String(), secret) {
+					t.Errorf("output contains secret %q", secret)
+				}
+			}
+		})
+	}
+}
+
+// TestRedactingWriterServer asserts that SASL challenge data in server
+// continuation requests is masked while other responses are left as-is.
+func TestRedactingWriterServer(t *testing.T) {
+	t.Parallel()
+
+	input := "+ eyJzdGF0dXMiOiI0MDEifQ==\r\n+ Ready for literal data\r\nA1 OK LOGIN completed\r\n"
+	want := "+ [REDACTED]\r\n+ Ready for literal data\r\nA1 OK LOGIN completed\r\n"
+
+	var out bytes.Buffer
+	w := &redactingWriter{dst: &out, mu: &sync.Mutex{}, redact: redactServerLine}
+
+	if _, err := w.Write([]byte(input)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if got := out.String(); got != want {
+		t.Errorf("\nwant %q\ngot  %q", want, got)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"github.com/emersion/go-imap"
+	"github.com/rs/zerolog"
+)
+
+// Checkpoint records the position reached in a mailbox by a previous check.
+// Messages with a UID greater than HighestUID arrived after the check was
+// performed provided that the UIDVALIDITY value of the mailbox is unchanged.
+type Checkpoint struct {
+	// UIDValidity is the UIDVALIDITY value of the mailbox when checked. UIDs
+	// recorded for a different UIDVALIDITY value are not comparable.
+	UIDValidity uint32 `json:"uid_validity"`
+
+	// HighestUID is the highest UID assigned in the mailbox when checked.
+	HighestUID uint32 `json:"highest_uid"`
+}
+
+// IsZero indicates whether the checkpoint is unset.
+func (cp Checkpoint) IsZero() bool {
+	return cp.UIDValidity == 0 && cp.HighestUID == 0
+}
+
+// Checkpoints is a collection of checkpoints indexed by mailbox name.
+type Checkpoints map[string]Checkpoint
+
+// checkpoint returns the checkpoint recorded for the named mailbox if delta
+// mode is enabled and the checkpoint is still valid for the given
+// UIDVALIDITY value. Reset is true if a checkpoint was recorded but is no
+// longer valid because the UIDVALIDITY value of the mailbox has changed.
+func (mf MessageFilter) checkpoint(mailbox string, uidValidity uint32) (cp Checkpoint, found bool, reset bool) {
+	cp, found = mf.Checkpoints[mailbox]
+	switch {
+	case !found || cp.IsZero():
+		return Checkpoint{}, false, false
+	case cp.UIDValidity != uidValidity:
+		return Checkpoint{}, false, true
+	default:
+		return cp, true, false
+	}
+}
+
+// logCheckpoint records the checkpoint applied to the named mailbox in delta
+// mode.
+func logCheckpoint(logger zerolog.Logger, mailbox string, cp Checkpoint, found bool, reset bool) {
+	switch {
+	case reset:
+		logger.Warn().
+			Str("mailbox", mailbox).
+			Msg("UIDVALIDITY changed since previous check; treating all messages as new")
+	case found:
+		logger.Debug().
+			Str("mailbox", mailbox).
+			Uint32("uid_validity", cp.UIDValidity).
+			Uint32("highest_uid", cp.HighestUID).
+			Msg("Checking for messages since previous check")
+	default:
+		logger.Debug().
+			Str("mailbox", mailbox).
+			Msg("No previous check recorded; treating all messages as new")
+	}
+}
+
+// newMessagesSearch returns a copy of the given search criteria (which may be
+// nil) limited to messages with a UID greater than the highest UID recorded
+// by the given checkpoint.
+func newMessagesSearch(search *imap.SearchCriteria, cp Checkpoint) *imap.SearchCriteria {
+	criteria := imap.NewSearchCriteria()
+	if search != nil {
+		copied := *search
+		criteria = &copied
+	}
+
+	// A UID range of N:* always matches the message with the highest UID,
+	// even if lower than N; results are filtered by newUIDs.
+	criteria.Uid = new(imap.SeqSet)
+	criteria.Uid.AddRange(cp.HighestUID+1, 0)
+
+	return criteria
+}
+
+// newUIDs returns the given UIDs which are greater than the highest UID
+// recorded by the given checkpoint.
+func newUIDs(uids []uint32, cp Checkpoint) []uint32 {
+	filtered := make([]uint32, 0, len(uids))
+	for _, uid := range uids {
+		if uid > cp.HighestUID {
+			filtered = append(filtered, uid)
+		}
+	}
+
+	return filtered
+}
+
+// hasNewMessages indicates whether the given mailbox status reports that UIDs
+// have been assigned since the given checkpoint was recorded. True is
+// returned if the next UID value is not known.
+func hasNewMessages(mailbox *imap.MailboxStatus, cp Checkpoint) bool {
+	if mailbox.Messages == 0 {
+		return false
+	}
+
+	return mailbox.UidNext == 0 || mailbox.UidNext-1 > cp.HighestUID
+}
+
+// nextCheckpoint returns the checkpoint reached for a mailbox with the given
+// status. If the server did not report the next UID value the highest of the
+// given (previous) checkpoint and the matched message UIDs is used instead.
+func nextCheckpoint(mailbox *imap.MailboxStatus, previous Checkpoint, uids []uint32) Checkpoint {
+	cp := Checkpoint{UIDValidity: mailbox.UidValidity}
+
+	if mailbox.UidNext > 0 {
+		cp.HighestUID = mailbox.UidNext - 1
+
+		return cp
+	}
+
+	if previous.UIDValidity == mailbox.UidValidity {
+		cp.HighestUID = previous.HighestUID
+	}
+
+	for _, uid := range uids {
+		cp.HighestUID = max(cp.HighestUID, uid)
+	}
+
+	return cp
+}
+
+// CheckpointsReset returns the names of checked mailboxes for which the
+// recorded checkpoint was discarded because the UIDVALIDITY value of the
+// mailbox changed.
+func (mcr MailboxCheckResults) CheckpointsReset() []string {
+	var names []string
+	for _, result := range mcr {
+		if result.CheckpointReset {
+			names = append(names, result.MailboxName)
+		}
+	}
+
+	return names
+}
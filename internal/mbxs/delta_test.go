@@ -0,0 +1,234 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// TestCountMailDelta asserts that only messages which arrived since the
+// recorded checkpoint (and the unseen messages among them) are counted in
+// delta mode and that a UIDVALIDITY change resets the checkpoint.
+func TestCountMailDelta(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		filter         MessageFilter
+		searchResults  map[string]string
+		wantSearches   []string
+		want           int
+		wantUnseen     int
+		wantCheckpoint Checkpoint
+		wantReset      bool
+	}{
+		"no previous check": {
+			filter:         MessageFilter{Checkpoints: Checkpoints{}},
+			want:           42,
+			wantUnseen:     3,
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+		},
+		"no new messages": {
+			filter:         MessageFilter{Checkpoints: Checkpoints{"INBOX": {UIDValidity: 7, HighestUID: 99}}},
+			want:           0,
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+		},
+		"new messages": {
+			filter: MessageFilter{Checkpoints: Checkpoints{"INBOX": {UIDValidity: 7, HighestUID: 95}}},
+			searchResults: map[string]string{
+				`UID 96:*`:        `* SEARCH 97 99`,
+				`UID 96:* UNSEEN`: `* SEARCH 99`,
+			},
+			wantSearches:   []string{`UID 96:*`, `UID 96:* UNSEEN`},
+			want:           2,
+			wantUnseen:     1,
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+		},
+		"new unseen messages": {
+			filter: MessageFilter{
+				Criteria:    []string{MessageCriterionUnseen},
+				Checkpoints: Checkpoints{"INBOX": {UIDValidity: 7, HighestUID: 95}},
+			},
+			searchResults:  map[string]string{`UID 96:* UNSEEN`: `* SEARCH 99`},
+			wantSearches:   []string{`UID 96:* UNSEEN`},
+			want:           1,
+			wantUnseen:     1,
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+		},
+		"uidvalidity changed": {
+			filter:         MessageFilter{Checkpoints: Checkpoints{"INBOX": {UIDValidity: 6, HighestUID: 120}}},
+			want:           42,
+			wantUnseen:     3,
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+			wantReset:      true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotStatus string
+			var gotSearches []string
+			c := newTestClient(t, "", map[string]testCommandHandler{
+				"STATUS": func(args string) ([]string, error) {
+					gotStatus = args
+					return []string{
+						`* STATUS "INBOX" (MESSAGES 42 UNSEEN 3 RECENT 1 UIDVALIDITY 7 UIDNEXT 100)`,
+					}, nil
+				},
+				"EXAMINE": func(string) ([]string, error) {
+					return []string{`* 42 EXISTS`, `* 1 RECENT`}, nil
+				},
+				"UID": func(args string) ([]string, error) {
+					search := strings.TrimPrefix(args, "SEARCH CHARSET UTF-8 ")
+					gotSearches = append(gotSearches, search)
+					return []string{tt.searchResults[search]}, nil
+				},
+			})
+
+			results, err := CountMail(c, "user", []string{"INBOX"}, tt.filter, zerolog.Nop())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.Contains(gotStatus, "UIDVALIDITY") || !strings.Contains(gotStatus, "UIDNEXT") {
+				t.Errorf("want UIDVALIDITY and UIDNEXT status items, got %q", gotStatus)
+			}
+
+			if !slices.Equal(gotSearches, tt.wantSearches) {
+				t.Errorf("want searches %q, got %q", tt.wantSearches, gotSearches)
+			}
+
+			got := results[0]
+			if got.ItemsFound != tt.want {
+				t.Errorf("want %d messages, got %d", tt.want, got.ItemsFound)
+			}
+
+			if got.UnseenFound != tt.wantUnseen {
+				t.Errorf("want %d unseen messages, got %d", tt.wantUnseen, got.UnseenFound)
+			}
+
+			if got.Checkpoint != tt.wantCheckpoint {
+				t.Errorf("want checkpoint %+v, got %+v", tt.wantCheckpoint, got.Checkpoint)
+			}
+
+			if got.CheckpointReset != tt.wantReset {
+				t.Errorf("want checkpoint reset %t, got %t", tt.wantReset, got.CheckpointReset)
+			}
+
+			if !slices.Contains(got.Criteria, "new") {
+				t.Errorf("want %q criteria label, got %v", "new", got.Criteria)
+			}
+		})
+	}
+}
+
+// TestCheckMailDelta asserts that only messages which arrived since the
+// recorded checkpoint are listed in delta mode, including when the server
+// does not report the next UID value.
+func TestCheckMailDelta(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		examine        []string
+		checkpoint     Checkpoint
+		searchResult   string
+		wantSearch     string
+		wantFetched    []string
+		want           int
+		wantCheckpoint Checkpoint
+	}{
+		"new messages": {
+			examine:        []string{`* 3 EXISTS`, `* OK [UIDVALIDITY 7] UIDs valid`, `* OK [UIDNEXT 100] Predicted next UID`},
+			checkpoint:     Checkpoint{UIDValidity: 7, HighestUID: 97},
+			searchResult:   `* SEARCH 98 99`,
+			wantSearch:     `UID 98:*`,
+			wantFetched:    []string{"98:99"},
+			want:           2,
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+		},
+		"no new messages without next UID": {
+			examine:        []string{`* 3 EXISTS`, `* OK [UIDVALIDITY 7] UIDs valid`},
+			checkpoint:     Checkpoint{UIDValidity: 7, HighestUID: 99},
+			searchResult:   `* SEARCH 99`,
+			wantSearch:     `UID 100:*`,
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+		},
+		"no new messages": {
+			examine:        []string{`* 3 EXISTS`, `* OK [UIDVALIDITY 7] UIDs valid`, `* OK [UIDNEXT 100] Predicted next UID`},
+			checkpoint:     Checkpoint{UIDValidity: 7, HighestUID: 99},
+			wantCheckpoint: Checkpoint{UIDValidity: 7, HighestUID: 99},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotSearch string
+			var fetched []string
+			c := newTestClient(t, "", map[string]testCommandHandler{
+				"EXAMINE": func(string) ([]string, error) {
+					return tt.examine, nil
+				},
+				"UID": func(args string) ([]string, error) {
+					fields := strings.Fields(args)
+					switch fields[0] {
+					case "SEARCH":
+						gotSearch = strings.TrimPrefix(args, "SEARCH CHARSET UTF-8 ")
+						return []string{tt.searchResult}, nil
+					case "FETCH":
+						fetched = append(fetched, fields[1])
+
+						var lines []string
+						for i, uid := range strings.Split(fields[1], ":") {
+							lines = append(lines, fmt.Sprintf(
+								`* %d FETCH (UID %s INTERNALDATE "10-Mar-2025 10:00:00 +0000" `+
+									`ENVELOPE ("Mon, 10 Mar 2025 10:00:00 +0000" "message %s" NIL NIL NIL NIL NIL NIL NIL "<%s@example.com>"))`,
+								i+2, uid, uid, uid,
+							))
+						}
+
+						return lines, nil
+					}
+
+					return nil, errors.New("unexpected UID command")
+				},
+			})
+
+			filter := MessageFilter{Checkpoints: Checkpoints{"INBOX": tt.checkpoint}}
+			results, err := CheckMail(c, "user", []string{"INBOX"}, filter, FetchOptions{}, zerolog.Nop())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotSearch != tt.wantSearch {
+				t.Errorf("want search %q, got %q", tt.wantSearch, gotSearch)
+			}
+
+			if !slices.Equal(tt.wantFetched, fetched) {
+				t.Errorf("want UID batches %v, got %v", tt.wantFetched, fetched)
+			}
+
+			got := results[0]
+			if got.ItemsFound != tt.want || len(got.Messages) != tt.want {
+				t.Errorf("want %d messages, got %d found and %d listed", tt.want, got.ItemsFound, len(got.Messages))
+			}
+
+			if got.Checkpoint != tt.wantCheckpoint {
+				t.Errorf("want checkpoint %+v, got %+v", tt.wantCheckpoint, got.Checkpoint)
+			}
+		})
+	}
+}
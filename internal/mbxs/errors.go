@@ -0,0 +1,128 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"os"
+)
+
+// Error categories for failures connecting to, authenticating to or
+// communicating with an IMAP server. Errors returned by this package wrap
+// one of these values (where applicable) along with the underlying error so
+// that the category can be determined using errors.Is.
+var (
+	// ErrDNSLookupFailed indicates that the IMAP server hostname could not
+	// be resolved to usable IP Addresses.
+	ErrDNSLookupFailed = errors.New("DNS lookup failed")
+
+	// ErrConnectFailed indicates that a connection to the IMAP server could
+	// not be established or was lost.
+	ErrConnectFailed = errors.New("connection failed")
+
+	// ErrTLSHandshakeFailed indicates that a TLS connection to the IMAP
+	// server could not be negotiated (e.g., due to an untrusted or expired
+	// certificate or an unsupported TLS version).
+	ErrTLSHandshakeFailed = errors.New("TLS handshake failed")
+
+	// ErrAuthRejected indicates that the IMAP server (or OAuth2 token
+	// endpoint) rejected the provided credentials.
+	ErrAuthRejected = errors.New("authentication rejected")
+
+	// ErrRequiredAuthMechanismUnsupported indicates that a required
+	// authentication mechanism is unsupported.
+	ErrRequiredAuthMechanismUnsupported = errors.New("required auth mechanism unsupported")
+
+	// ErrMailboxNotFound indicates that a requested mailbox is not present
+	// for the associated user account.
+	ErrMailboxNotFound = errors.New("mailbox not found")
+
+	// ErrProtocol indicates that the IMAP server rejected a command or
+	// returned an unexpected response.
+	ErrProtocol = errors.New("IMAP protocol error")
+
+	// ErrTimeout indicates that an operation did not complete within the
+	// permitted time.
+	ErrTimeout = errors.New("operation timed out")
+)
+
+// isTimeout indicates whether the given error represents a timeout.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isTLSError indicates whether the given error occurred while negotiating a
+// TLS connection or verifying the server certificate.
+func isTLSError(err error) bool {
+	var (
+		recordHeaderErr  tls.RecordHeaderError
+		alertErr         tls.AlertError
+		certVerifyErr    *tls.CertificateVerificationError
+		unknownAuthErr   x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		certInvalidErr   x509.CertificateInvalidError
+		systemRootsError x509.SystemRootsError
+	)
+
+	return errors.As(err, &recordHeaderErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &certVerifyErr) ||
+		errors.As(err, &unknownAuthErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certInvalidErr) ||
+		errors.As(err, &systemRootsError)
+}
+
+// isConnectionError indicates whether the given error indicates a network
+// failure or a lost connection.
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+
+	return errors.As(err, &opErr) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// connectErrorKind returns the error category for a failed attempt to
+// establish a connection to the IMAP server.
+func connectErrorKind(err error) error {
+	switch {
+	case isTimeout(err):
+		return ErrTimeout
+	case isTLSError(err):
+		return ErrTLSHandshakeFailed
+	default:
+		return ErrConnectFailed
+	}
+}
+
+// commandErrorKind returns the error category for a failed IMAP command. The
+// given category is used if the failure is not due to a timeout or a network
+// failure (e.g., the server responded with NO or BAD).
+func commandErrorKind(err error, rejected error) error {
+	switch {
+	case isTimeout(err):
+		return ErrTimeout
+	case isConnectionError(err):
+		return ErrConnectFailed
+	default:
+		return rejected
+	}
+}
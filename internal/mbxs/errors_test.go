@@ -0,0 +1,123 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// TestErrorKinds asserts that connection and command failures are assigned
+// the expected error category.
+func TestErrorKinds(t *testing.T) {
+	t.Parallel()
+
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := map[string]struct {
+		got  error
+		want error
+	}{
+		"connect refused": {
+			got:  connectErrorKind(opErr),
+			want: ErrConnectFailed,
+		},
+		"connect timeout": {
+			got:  connectErrorKind(fmt.Errorf("dial: %w", context.DeadlineExceeded)),
+			want: ErrTimeout,
+		},
+		"connect untrusted certificate": {
+			got:  connectErrorKind(fmt.Errorf("handshake: %w", x509.UnknownAuthorityError{})),
+			want: ErrTLSHandshakeFailed,
+		},
+		"command rejected": {
+			got:  commandErrorKind(errors.New("NO [AUTHENTICATIONFAILED] invalid credentials"), ErrAuthRejected),
+			want: ErrAuthRejected,
+		},
+		"command connection lost": {
+			got:  commandErrorKind(io.ErrUnexpectedEOF, ErrProtocol),
+			want: ErrConnectFailed,
+		},
+		"command timeout": {
+			got:  commandErrorKind(&net.DNSError{IsTimeout: true}, ErrProtocol),
+			want: ErrTimeout,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if !errors.Is(tt.got, tt.want) {
+				t.Errorf("want %v, got %v", tt.want, tt.got)
+			}
+		})
+	}
+}
+
+// TestConnectErrors asserts that errors resolving and connecting to a server
+// can be identified using errors.Is.
+func TestConnectErrors(t *testing.T) {
+	t.Parallel()
+
+	// Obtain a local port which is not accepting connections.
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	_, err = Connect("127.0.0.1", port, NetTypeTCP4, 0, zerolog.Nop())
+	if !errors.Is(err, ErrConnectFailed) {
+		t.Errorf("want ErrConnectFailed, got %v", err)
+	}
+
+	// Reserved TLD; guaranteed not to resolve.
+	_, err = Connect("imap.example.invalid", 993, NetTypeTCP4, 0, zerolog.Nop())
+	if !errors.Is(err, ErrDNSLookupFailed) {
+		t.Errorf("want ErrDNSLookupFailed, got %v", err)
+	}
+}
+
+// TestCommandErrors asserts that errors returned by the server for mailbox
+// commands can be identified using errors.Is.
+func TestCommandErrors(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"STATUS": func(string) ([]string, error) {
+			return nil, errors.New("internal server error")
+		},
+	})
+
+	results, err := CountMail(c, "user", []string{"INBOX"}, MessageFilter{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !errors.Is(results.Err(), ErrProtocol) {
+		t.Errorf("want ErrProtocol, got %v", results.Err())
+	}
+
+	_, err = ExpandMailboxesList(newTestClient(t, "", map[string]testCommandHandler{
+		"LIST": func(string) ([]string, error) {
+			return []string{`* LIST () "/" "INBOX"`}, nil
+		},
+	}), FolderSelection{Folders: []string{"INBOX", "Missing"}}, zerolog.Nop())
+	if !errors.Is(err, ErrMailboxNotFound) {
+		t.Errorf("want ErrMailboxNotFound, got %v", err)
+	}
+}
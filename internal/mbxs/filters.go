@@ -0,0 +1,261 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+)
+
+var (
+	// ErrInvalidSearchFilter indicates that an invalid or unsupported search
+	// filter expression was specified.
+	ErrInvalidSearchFilter = errors.New("invalid search filter")
+)
+
+// Search filter fields supported in FIELD:VALUE filter expressions.
+const (
+	SearchFilterFrom    string = "from"
+	SearchFilterTo      string = "to"
+	SearchFilterCc      string = "cc"
+	SearchFilterSubject string = "subject"
+	SearchFilterHeader  string = "header"
+	SearchFilterLarger  string = "larger"
+	SearchFilterSmaller string = "smaller"
+	SearchFilterSince   string = "since"
+	SearchFilterBefore  string = "before"
+)
+
+// searchFilterDateLayout is the layout for absolute dates used with the
+// since and before search filters.
+const searchFilterDateLayout string = "2006-01-02"
+
+// MessageFilter limits the messages counted and listed within a mailbox.
+// All filters are applied by the server using the SEARCH command.
+type MessageFilter struct {
+	// Criteria is a collection of message flag criteria (e.g., "unseen").
+	// All criteria must match for a message to be included.
+	Criteria []string
+
+	// Include is a collection of FIELD:VALUE search filter expressions. All
+	// expressions must match for a message to be included.
+	Include []string
+
+	// Exclude is a collection of FIELD:VALUE search filter expressions. A
+	// message matching any of these expressions is excluded.
+	Exclude []string
+
+	// Checkpoints, if not nil, limits matching messages to those which
+	// arrived after the checkpoint recorded for each mailbox by a previous
+	// check (delta mode). All matching messages are treated as new for a
+	// mailbox without a checkpoint or whose UIDVALIDITY value has changed.
+	Checkpoints Checkpoints
+}
+
+// IsEmpty indicates whether no search filters are specified. Checkpoints
+// are applied separately for each mailbox and are not considered.
+func (mf MessageFilter) IsEmpty() bool {
+	return len(mf.Criteria) == 0 && len(mf.Include) == 0 && len(mf.Exclude) == 0
+}
+
+// Labels returns short labels describing the applied filters for use in
+// summaries.
+func (mf MessageFilter) Labels() []string {
+	if mf.IsEmpty() && mf.Checkpoints == nil {
+		return nil
+	}
+
+	labels := make([]string, 0, len(mf.Criteria)+2)
+	labels = append(labels, mf.Criteria...)
+
+	if len(mf.Include) > 0 || len(mf.Exclude) > 0 {
+		labels = append(labels, "filtered")
+	}
+
+	if mf.Checkpoints != nil {
+		labels = append(labels, "new")
+	}
+
+	return labels
+}
+
+// SearchCriteria converts the message filter into IMAP SEARCH criteria.
+// Relative since and before filters are evaluated against the given time. A
+// nil value is returned if no filters are specified.
+func (mf MessageFilter) SearchCriteria(now time.Time) (*imap.SearchCriteria, error) {
+	if mf.IsEmpty() {
+		return nil, nil
+	}
+
+	search, err := SearchCriteria(mf.Criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	if search == nil {
+		search = imap.NewSearchCriteria()
+	}
+
+	for _, expr := range mf.Include {
+		if err := applySearchFilter(search, expr, now); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, expr := range mf.Exclude {
+		exclude := imap.NewSearchCriteria()
+		if err := applySearchFilter(exclude, expr, now); err != nil {
+			return nil, err
+		}
+
+		search.Not = append(search.Not, exclude)
+	}
+
+	return search, nil
+}
+
+// ValidateSearchFilter asserts that the given FIELD:VALUE search filter
+// expression is valid.
+func ValidateSearchFilter(expr string) error {
+	return applySearchFilter(imap.NewSearchCriteria(), expr, time.Now())
+}
+
+// applySearchFilter parses the given FIELD:VALUE search filter expression
+// and adds the equivalent criterion to the given search criteria.
+func applySearchFilter(search *imap.SearchCriteria, expr string, now time.Time) error {
+	field, value, found := strings.Cut(expr, ":")
+	field = strings.ToLower(strings.TrimSpace(field))
+	value = strings.TrimSpace(value)
+
+	if !found || value == "" {
+		return fmt.Errorf(
+			"filter %q (want FIELD:VALUE): %w",
+			expr,
+			ErrInvalidSearchFilter,
+		)
+	}
+
+	switch field {
+	case SearchFilterFrom:
+		search.Header.Add("From", value)
+
+	case SearchFilterTo:
+		search.Header.Add("To", value)
+
+	case SearchFilterCc:
+		search.Header.Add("Cc", value)
+
+	case SearchFilterSubject:
+		search.Header.Add("Subject", value)
+
+	case SearchFilterHeader:
+		name, headerValue, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fmt.Errorf(
+				"filter %q (want header:NAME=VALUE): %w",
+				expr,
+				ErrInvalidSearchFilter,
+			)
+		}
+		search.Header.Add(name, strings.TrimSpace(headerValue))
+
+	case SearchFilterLarger, SearchFilterSmaller:
+		size, err := parseMessageSize(value)
+		if err != nil {
+			return fmt.Errorf("filter %q: %w", expr, err)
+		}
+
+		if field == SearchFilterLarger {
+			search.Larger = size
+		} else {
+			search.Smaller = size
+		}
+
+	case SearchFilterSince, SearchFilterBefore:
+		date, err := parseSearchDate(value, now)
+		if err != nil {
+			return fmt.Errorf("filter %q: %w", expr, err)
+		}
+
+		if field == SearchFilterSince {
+			search.Since = date
+		} else {
+			search.Before = date
+		}
+
+	default:
+		return fmt.Errorf(
+			"filter %q has unsupported field %q: %w",
+			expr,
+			field,
+			ErrInvalidSearchFilter,
+		)
+	}
+
+	return nil
+}
+
+// parseMessageSize parses a message size in bytes with an optional K, M or G
+// (1024-based) suffix.
+func parseMessageSize(value string) (uint32, error) {
+	multiplier := uint64(1)
+	number := strings.ToUpper(value)
+
+	switch {
+	case strings.HasSuffix(number, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(number, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(number, "G"):
+		multiplier = 1 << 30
+	}
+	number = strings.TrimRight(number, "KMG")
+
+	size, err := strconv.ParseUint(number, 10, 32)
+	if err != nil || size*multiplier > uint64(^uint32(0)) {
+		return 0, fmt.Errorf(
+			"invalid message size %q: %w",
+			value,
+			ErrInvalidSearchFilter,
+		)
+	}
+
+	return uint32(size * multiplier), nil
+}
+
+// parseSearchDate parses an absolute date in YYYY-MM-DD format or a duration
+// (e.g., "36h" or "7d") relative to the given time. IMAP date searches
+// ignore the time of day.
+func parseSearchDate(value string, now time.Time) (time.Time, error) {
+	if date, err := time.Parse(searchFilterDateLayout, value); err == nil {
+		return date, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"invalid date %q (want YYYY-MM-DD or a duration such as 36h or 7d): %w",
+		value,
+		ErrInvalidSearchFilter,
+	)
+}
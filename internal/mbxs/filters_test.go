@@ -0,0 +1,106 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/commands"
+)
+
+// formatSearchCriteria returns the IMAP command representation of the given
+// search criteria.
+func formatSearchCriteria(t *testing.T, search *imap.SearchCriteria) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	cmd := (&commands.Search{Criteria: search}).Command()
+	if err := cmd.WriteTo(imap.NewWriter(&buf)); err != nil {
+		t.Fatalf("failed to write search criteria: %v", err)
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(buf.String(), "* SEARCH "), "\r\n")
+}
+
+// TestMessageFilterSearchCriteria asserts that include and exclude filter
+// expressions are converted into the expected IMAP SEARCH criteria.
+func TestMessageFilterSearchCriteria(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		filter MessageFilter
+		want   string
+	}{
+		"from and unseen": {
+			filter: MessageFilter{
+				Criteria: []string{MessageCriterionUnseen},
+				Include:  []string{"from:alerts@example.com"},
+			},
+			want: `FROM "alerts@example.com" UNSEEN`,
+		},
+		"exclude ticketing system": {
+			filter: MessageFilter{
+				Exclude: []string{"from:tickets@example.com", "header:X-Ticket-System=yes"},
+			},
+			want: `NOT (FROM "tickets@example.com") NOT (HEADER "X-Ticket-System" "yes")`,
+		},
+		"size and relative date": {
+			filter: MessageFilter{
+				Include: []string{"larger:1M", "since:7d"},
+			},
+			want: `SINCE "3-Mar-2025" LARGER 1048576`,
+		},
+		"absolute date": {
+			filter: MessageFilter{
+				Include: []string{"before:2025-01-31"},
+			},
+			want: `BEFORE "31-Jan-2025"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			search, err := tt.filter.SearchCriteria(now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := formatSearchCriteria(t, search); got != tt.want {
+				t.Errorf("\nwant %s\ngot  %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestValidateSearchFilterInvalid asserts that invalid filter expressions are
+// rejected.
+func TestValidateSearchFilterInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		"alerts@example.com",
+		"from:",
+		"body:secret",
+		"header:=value",
+		"larger:lots",
+		"since:yesterday",
+	} {
+		if err := ValidateSearchFilter(expr); !errors.Is(err, ErrInvalidSearchFilter) {
+			t.Errorf("%q: want error %v, got %v", expr, ErrInvalidSearchFilter, err)
+		}
+	}
+}
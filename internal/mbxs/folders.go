@@ -0,0 +1,361 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/emersion/go-imap"
+	"github.com/rs/zerolog"
+)
+
+// IMAP LIST wildcard characters. The "*" wildcard matches zero or more
+// characters including the hierarchy delimiter; the "%" wildcard does not
+// match the hierarchy delimiter.
+//
+// https://datatracker.ietf.org/doc/html/rfc3501#section-6.3.8
+const (
+	folderWildcardAny   string = "*"
+	folderWildcardLevel string = "%"
+)
+
+// ErrInvalidSpecialUseRole indicates that an unsupported special-use folder
+// role was specified.
+var ErrInvalidSpecialUseRole = errors.New("invalid special-use folder role")
+
+// specialUseRoles are the special-use mailbox attributes (RFC 6154) which
+// may be used in place of folder names. Roles are resolved using the
+// attributes reported by the server when listing mailboxes, e.g., \Junk may
+// refer to "Junk E-mail", "Spam" or "[Gmail]/Spam".
+var specialUseRoles = []string{
+	imap.AllAttr,
+	imap.ArchiveAttr,
+	imap.DraftsAttr,
+	imap.FlaggedAttr,
+	imap.JunkAttr,
+	imap.SentAttr,
+	imap.TrashAttr,
+}
+
+// FolderSelection describes the folders (IMAP "mailboxes") to check.
+type FolderSelection struct {
+	// Folders is a collection of folder names, special-use roles (e.g.,
+	// "\Junk") or patterns using the IMAP LIST wildcards "*" (any
+	// characters, including subfolders) and "%" (any characters within a
+	// single folder level). E.g., "Projects/*".
+	Folders []string
+
+	// Recurse indicates whether all subfolders of each specified folder are
+	// also checked.
+	Recurse bool
+
+	// Exclude is a collection of folder names, special-use roles or patterns
+	// excluded from the expanded list of folders to check.
+	Exclude []string
+}
+
+// IsFolderPattern indicates whether the given folder name contains IMAP LIST
+// wildcard characters.
+func IsFolderPattern(folder string) bool {
+	return strings.ContainsAny(folder, folderWildcardAny+folderWildcardLevel)
+}
+
+// IsSpecialUseRole indicates whether the given folder name refers to a
+// special-use role (e.g., "\Junk") rather than a specific folder.
+func IsSpecialUseRole(folder string) bool {
+	return strings.HasPrefix(folder, "\\")
+}
+
+// ValidateSpecialUseRole asserts that the given special-use role is
+// supported.
+func ValidateSpecialUseRole(role string) error {
+	for _, supported := range specialUseRoles {
+		if strings.EqualFold(role, supported) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"role %q (supported roles: %s): %w",
+		role,
+		strings.Join(specialUseRoles, ", "),
+		ErrInvalidSpecialUseRole,
+	)
+}
+
+// IsExpanded indicates whether the folder selection uses patterns,
+// special-use roles, recursion or exclusions and so may check folders other
+// than those listed.
+func (fs FolderSelection) IsExpanded() bool {
+	if fs.Recurse || len(fs.Exclude) > 0 {
+		return true
+	}
+
+	for _, folder := range fs.Folders {
+		if IsFolderPattern(folder) || IsSpecialUseRole(folder) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExpandMailboxesList receives a folder selection and returns the list of
+// mailboxes present for the associated user account which match it. Folder
+// patterns, special-use roles, recursion and exclusions are expanded against
+// the mailboxes listed by the server. As with ValidateMailboxesList, if any
+// (non-pattern) folders are not present the matching mailboxes are returned
+// along with a *MissingMailboxesError listing every missing folder.
+func ExpandMailboxesList(c *Session, selection FolderSelection, logger zerolog.Logger) ([]string, error) {
+
+	serverMailboxes, listErr := listMailboxInfo(c, logger)
+	if listErr != nil {
+		return nil, listErr
+	}
+
+	expanded := make([]string, 0, len(selection.Folders))
+	var missing []string
+	seen := make(map[string]bool, len(selection.Folders))
+	add := func(folder string) {
+		if !seen[folder] {
+			seen[folder] = true
+			expanded = append(expanded, folder)
+		}
+	}
+
+	for _, folder := range selection.Folders {
+		folder = NormalizeMailboxName(folder)
+
+		var matched []string
+
+		switch {
+		case IsFolderPattern(folder):
+			for _, info := range serverMailboxes {
+				if selectable(info) && matchFolderPattern(folder, info.Name, info.Delimiter) {
+					matched = append(matched, info.Name)
+				}
+			}
+
+			logger.Debug().
+				Str("pattern", folder).
+				Strs("mailboxes", matched).
+				Msg("Expanded folder pattern")
+
+			if len(matched) == 0 {
+				logger.Warn().Str("pattern", folder).Msg("Folder pattern did not match any mailboxes")
+			}
+
+		default:
+			validated, err := validateMailboxes([]string{folder}, serverMailboxes, logger)
+			var missingErr *MissingMailboxesError
+			switch {
+			case errors.As(err, &missingErr):
+				missing = append(missing, missingErr.Mailboxes...)
+			case err != nil:
+				return nil, err
+			}
+			matched = validated
+		}
+
+		for _, name := range matched {
+			add(name)
+
+			if !selection.Recurse {
+				continue
+			}
+
+			for _, info := range serverMailboxes {
+				if selectable(info) && isSubfolder(name, info.Name, info.Delimiter) {
+					add(info.Name)
+				}
+			}
+		}
+	}
+
+	checked := make([]string, 0, len(expanded))
+	for _, folder := range expanded {
+		if excluded(folder, selection.Exclude, serverMailboxes) {
+			logger.Debug().Str("mailbox", folder).Msg("Excluding mailbox")
+			continue
+		}
+		checked = append(checked, folder)
+	}
+
+	if len(missing) > 0 {
+		logger.Info().
+			Strs("mailboxes", checked).
+			Strs("missing", missing).
+			Msg("Expanded list of mailboxes to check")
+
+		return checked, &MissingMailboxesError{Mailboxes: missing}
+	}
+
+	if len(checked) == 0 {
+		return nil, fmt.Errorf(
+			"no mailboxes matched requested folders %q: %w",
+			selection.Folders,
+			ErrMailboxNotFound,
+		)
+	}
+
+	logger.Info().
+		Strs("mailboxes", checked).
+		Msg("Expanded list of mailboxes to check")
+
+	return checked, nil
+}
+
+// selectable indicates whether the given mailbox can be selected (i.e., is
+// not merely a placeholder in the folder hierarchy).
+func selectable(info *imap.MailboxInfo) bool {
+	for _, attr := range info.Attributes {
+		if strings.EqualFold(attr, imap.NoSelectAttr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// excluded indicates whether the given mailbox matches any of the given
+// exclusion names or patterns.
+func excluded(folder string, exclusions []string, serverMailboxes []*imap.MailboxInfo) bool {
+	delimiter := ""
+	for _, info := range serverMailboxes {
+		if info.Name == folder {
+			delimiter = info.Delimiter
+			break
+		}
+	}
+
+	for _, exclusion := range exclusions {
+		if IsSpecialUseRole(exclusion) {
+			if hasSpecialUseRole(folder, exclusion, serverMailboxes) {
+				return true
+			}
+			continue
+		}
+
+		if matchFolderPattern(NormalizeMailboxName(exclusion), folder, delimiter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasSpecialUseRole indicates whether the given mailbox has the specified
+// special-use role.
+func hasSpecialUseRole(folder string, role string, serverMailboxes []*imap.MailboxInfo) bool {
+	for _, info := range serverMailboxes {
+		if info.Name != folder {
+			continue
+		}
+
+		for _, attr := range info.Attributes {
+			if strings.EqualFold(attr, role) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// specialUseMailbox returns the name of the (selectable) mailbox with the
+// specified special-use role.
+func specialUseMailbox(role string, serverMailboxes []*imap.MailboxInfo) (string, bool) {
+	for _, info := range serverMailboxes {
+		if selectable(info) && hasSpecialUseRole(info.Name, role, serverMailboxes) {
+			return info.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// isSubfolder indicates whether the given mailbox is a descendant of the
+// specified parent mailbox.
+func isSubfolder(parent string, name string, delimiter string) bool {
+	if delimiter == "" {
+		return false
+	}
+
+	return strings.HasPrefix(
+		canonicalFolderName(name, delimiter),
+		canonicalFolderName(parent, delimiter)+delimiter,
+	)
+}
+
+// folderNamesEqual indicates whether the given mailbox names are equal. The
+// INBOX name (including as the first level of a hierarchy) is compared
+// case-insensitively.
+func folderNamesEqual(a string, b string, delimiter string) bool {
+	return canonicalFolderName(a, delimiter) == canonicalFolderName(b, delimiter)
+}
+
+// matchFolderPattern indicates whether the given mailbox name matches the
+// given folder name or IMAP LIST pattern. The INBOX name (including as the
+// first level of a hierarchy) is matched case-insensitively.
+func matchFolderPattern(pattern string, name string, delimiter string) bool {
+	if !IsFolderPattern(pattern) {
+		return folderNamesEqual(pattern, name, delimiter)
+	}
+
+	pattern = canonicalFolderName(pattern, delimiter)
+	name = canonicalFolderName(name, delimiter)
+
+	levelMatch := ".*"
+	if delimiter != "" {
+		levelMatch = "[^" + regexp.QuoteMeta(delimiter) + "]*"
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch string(r) {
+		case folderWildcardAny:
+			expr.WriteString(".*")
+		case folderWildcardLevel:
+			expr.WriteString(levelMatch)
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(name)
+}
+
+// canonicalFolderName returns the given folder name with a leading INBOX
+// hierarchy level normalized to uppercase. Per RFC 3501 the INBOX name is
+// case-insensitive.
+func canonicalFolderName(name string, delimiter string) string {
+	first, rest, found := name, "", false
+	if delimiter != "" {
+		first, rest, found = strings.Cut(name, delimiter)
+	}
+
+	if !strings.EqualFold(first, "INBOX") {
+		return name
+	}
+
+	if !found {
+		return "INBOX"
+	}
+
+	return "INBOX" + delimiter + rest
+}
@@ -0,0 +1,208 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// testListResponses is the folder hierarchy used by folder expansion tests.
+var testListResponses = []string{
+	`* LIST (\HasChildren) "/" "INBOX"`,
+	`* LIST (\HasNoChildren) "/" "INBOX/Alerts"`,
+	`* LIST (\Noselect \HasChildren) "/" "Projects"`,
+	`* LIST (\HasChildren) "/" "Projects/Alpha"`,
+	`* LIST (\HasNoChildren) "/" "Projects/Alpha/Old"`,
+	`* LIST (\HasNoChildren) "/" "Projects/Beta"`,
+	`* LIST (\HasChildren) "/" "Archive"`,
+	`* LIST (\HasNoChildren) "/" "Archive/2024"`,
+	`* LIST (\HasNoChildren) "/" "Archive/2025"`,
+	`* LIST (\HasNoChildren \Trash) "/" "Trash"`,
+	`* LIST (\HasNoChildren \Junk) "/" "Junk E-mail"`,
+}
+
+func TestMatchFolderPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "Trash", name: "Trash", want: true},
+		{pattern: "Trash", name: "trash", want: false},
+		{pattern: "inbox", name: "INBOX", want: true},
+		{pattern: "Inbox/Alerts", name: "INBOX/Alerts", want: true},
+		{pattern: "inbox/alerts", name: "INBOX/Alerts", want: false},
+		{pattern: "Projects/*", name: "Projects/Alpha", want: true},
+		{pattern: "Projects/*", name: "Projects/Alpha/Old", want: true},
+		{pattern: "Projects/%", name: "Projects/Alpha", want: true},
+		{pattern: "Projects/%", name: "Projects/Alpha/Old", want: false},
+		{pattern: "Projects/%", name: "Projects", want: false},
+		{pattern: "Archive/20%", name: "Archive/2025", want: true},
+		{pattern: "*", name: "Archive/2025", want: true},
+		{pattern: "INBOX*", name: "inbox/Alerts", want: true},
+		{pattern: "Proj.cts/*", name: "Projects/Alpha", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := matchFolderPattern(tt.pattern, tt.name, "/"); got != tt.want {
+			t.Errorf(
+				"matchFolderPattern(%q, %q): want %t, got %t",
+				tt.pattern,
+				tt.name,
+				tt.want,
+				got,
+			)
+		}
+	}
+}
+
+func TestExpandMailboxesList(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		selection   FolderSelection
+		want        []string
+		wantErr     bool
+		wantMissing []string
+	}{
+		"exact names": {
+			selection: FolderSelection{Folders: []string{"INBOX", "Trash"}},
+			want:      []string{"INBOX", "Trash"},
+		},
+		"missing exact name": {
+			selection:   FolderSelection{Folders: []string{"INBOX", "Missing"}},
+			want:        []string{"INBOX"},
+			wantErr:     true,
+			wantMissing: []string{"Missing"},
+		},
+		"all missing folders reported": {
+			selection:   FolderSelection{Folders: []string{"Missing", "Trash", "\\Sent", "Other"}},
+			want:        []string{"Trash"},
+			wantErr:     true,
+			wantMissing: []string{"Missing", "\\Sent", "Other"},
+		},
+		"pattern skips non-selectable folders": {
+			selection: FolderSelection{Folders: []string{"Projects*"}},
+			want:      []string{"Projects/Alpha", "Projects/Alpha/Old", "Projects/Beta"},
+		},
+		"single level pattern": {
+			selection: FolderSelection{Folders: []string{"Archive/%"}},
+			want:      []string{"Archive/2024", "Archive/2025"},
+		},
+		"recursive": {
+			selection: FolderSelection{Folders: []string{"inbox", "Archive"}, Recurse: true},
+			want:      []string{"inbox", "INBOX/Alerts", "Archive", "Archive/2024", "Archive/2025"},
+		},
+		"exclusions": {
+			selection: FolderSelection{
+				Folders: []string{"*"},
+				Exclude: []string{"Trash", "Archive/*", "Projects/%/*", "\\Junk"},
+			},
+			want: []string{"INBOX", "INBOX/Alerts", "Projects/Alpha", "Projects/Beta", "Archive"},
+		},
+		"special-use roles": {
+			selection: FolderSelection{Folders: []string{"INBOX", "\\Junk", "\\trash"}},
+			want:      []string{"INBOX", "Junk E-mail", "Trash"},
+		},
+		"missing special-use role": {
+			selection:   FolderSelection{Folders: []string{"\\Sent"}},
+			wantErr:     true,
+			wantMissing: []string{"\\Sent"},
+		},
+		"excluded special-use role": {
+			selection: FolderSelection{Folders: []string{"%"}, Exclude: []string{"\\Trash", "\\Junk"}},
+			want:      []string{"INBOX", "Archive"},
+		},
+		"duplicates removed": {
+			selection: FolderSelection{Folders: []string{"Archive/2024", "Archive/*"}},
+			want:      []string{"Archive/2024", "Archive/2025"},
+		},
+		"nothing left to check": {
+			selection: FolderSelection{Folders: []string{"Archive/*"}, Exclude: []string{"Archive/*"}},
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newTestClient(t, "", map[string]testCommandHandler{
+				"LIST": func(string) ([]string, error) {
+					return testListResponses, nil
+				},
+			})
+
+			got, err := ExpandMailboxesList(c, tt.selection, zerolog.Nop())
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatalf("want error, got %q", got)
+			case !tt.wantErr && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+
+			var missingErr *MissingMailboxesError
+			switch {
+			case errors.As(err, &missingErr):
+				if !errors.Is(err, ErrMailboxNotFound) {
+					t.Errorf("want error to match ErrMailboxNotFound, got %v", err)
+				}
+				if !slices.Equal(missingErr.Mailboxes, tt.wantMissing) {
+					t.Errorf("want missing %q, got %q", tt.wantMissing, missingErr.Mailboxes)
+				}
+			case tt.wantMissing != nil:
+				t.Errorf("want missing %q, got error %v", tt.wantMissing, err)
+			}
+		})
+	}
+}
+
+func TestFolderSelectionIsExpanded(t *testing.T) {
+	t.Parallel()
+
+	if (FolderSelection{Folders: []string{"INBOX", "Trash"}}).IsExpanded() {
+		t.Error("want exact folder names not to be expanded")
+	}
+
+	for _, fs := range []FolderSelection{
+		{Folders: []string{"INBOX", "Projects/*"}},
+		{Folders: []string{"INBOX", "\\Junk"}},
+		{Folders: []string{"INBOX"}, Recurse: true},
+		{Folders: []string{"INBOX"}, Exclude: []string{"INBOX/Spam"}},
+	} {
+		if !fs.IsExpanded() {
+			t.Errorf("want %+v to be expanded", fs)
+		}
+	}
+}
+
+func TestValidateSpecialUseRole(t *testing.T) {
+	t.Parallel()
+
+	for _, role := range []string{`\Junk`, `\junk`, `\Sent`, `\Trash`, `\Archive`, `\Drafts`} {
+		if err := ValidateSpecialUseRole(role); err != nil {
+			t.Errorf("want role %q to be valid, got %v", role, err)
+		}
+	}
+
+	for _, role := range []string{`\Spam`, `\Noselect`, `Junk`} {
+		if err := ValidateSpecialUseRole(role); !errors.Is(err, ErrInvalidSpecialUseRole) {
+			t.Errorf("want %v for role %q, got %v", ErrInvalidSpecialUseRole, role, err)
+		}
+	}
+}
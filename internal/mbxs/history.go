@@ -0,0 +1,45 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import "time"
+
+// MailboxSample records the number of messages in a mailbox and the highest
+// UID assigned when the mailbox was checked. A series of samples is used to
+// determine the rate at which messages arrive in and are removed from the
+// mailbox.
+type MailboxSample struct {
+	// Time is when the mailbox was checked.
+	Time time.Time `json:"time"`
+
+	// Messages is the total number of messages in the mailbox.
+	Messages int `json:"messages"`
+
+	// UIDValidity is the UIDVALIDITY value of the mailbox. Samples recorded
+	// for a different UIDVALIDITY value are not comparable.
+	UIDValidity uint32 `json:"uid_validity"`
+
+	// HighestUID is the highest UID assigned in the mailbox.
+	HighestUID uint32 `json:"highest_uid"`
+}
+
+// Sample returns a sample of the mailbox state recorded by the check at the
+// given time. False is returned if the mailbox could not be checked or the
+// UIDVALIDITY value of the mailbox is not known.
+func (mcr MailboxCheckResult) Sample(t time.Time) (MailboxSample, bool) {
+	if mcr.Err != nil || mcr.Checkpoint.UIDValidity == 0 {
+		return MailboxSample{}, false
+	}
+
+	return MailboxSample{
+		Time:        t,
+		Messages:    mcr.MailboxMessages,
+		UIDValidity: mcr.Checkpoint.UIDValidity,
+		HighestUID:  mcr.Checkpoint.HighestUID,
+	}, true
+}
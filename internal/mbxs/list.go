@@ -9,6 +9,8 @@ package mbxs
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,83 +20,164 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// MissingMailboxesError records all requested mailboxes (or special-use
+// roles) which are not present for the associated user account. It matches
+// ErrMailboxNotFound when using errors.Is.
+type MissingMailboxesError struct {
+	// Mailboxes is the collection of requested mailbox names or special-use
+	// roles which were not found.
+	Mailboxes []string
+}
+
+// Error implements the error interface.
+func (e *MissingMailboxesError) Error() string {
+	quoted := make([]string, 0, len(e.Mailboxes))
+	for _, mbx := range e.Mailboxes {
+		quoted = append(quoted, fmt.Sprintf("%q", mbx))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrMailboxNotFound, strings.Join(quoted, ", "))
+}
+
+// Is indicates whether the target error is ErrMailboxNotFound.
+func (e *MissingMailboxesError) Is(target error) bool {
+	return target == ErrMailboxNotFound
+}
+
 // ListMailboxes lists mailboxes associated with the logged in user account
 // (by way of an IMAP client connection).
-func ListMailboxes(c *client.Client, logger zerolog.Logger) ([]string, error) {
+func ListMailboxes(c *Session, logger zerolog.Logger) ([]string, error) {
+
+	serverMailboxes, listErr := listMailboxInfo(c, logger)
+	if listErr != nil {
+		return nil, listErr
+	}
+
+	var mailboxesList = make([]string, 0, len(serverMailboxes))
+	for _, m := range serverMailboxes {
+		mailboxesList = append(mailboxesList, m.Name)
+	}
+
+	logger.Debug().Msg("no errors encountered listing mailboxes")
+	return mailboxesList, nil
+
+}
+
+// listMailboxInfo lists details (e.g., name, attributes and hierarchy
+// delimiter) for all mailboxes associated with the logged in user account.
+func listMailboxInfo(c *Session, logger zerolog.Logger) ([]*imap.MailboxInfo, error) {
 
 	// Generate background job to list mailboxes, send down channel until done
 	mailboxes := make(chan *imap.MailboxInfo, 10)
 	done := make(chan error, 1)
-	// NOTE: This goroutine shuts down once c.List() finishes its work
+	// NOTE: This goroutine shuts down once the LIST command finishes its work
 	go func() {
-		logger.Debug().Msg("Running c.List() to fetch a list of available mailboxes")
-		done <- c.List("", "*", mailboxes)
+		logger.Debug().Msg("Running LIST command to fetch a list of available mailboxes")
+		done <- listMailboxes(c, folderWildcardAny, mailboxes)
 	}()
 
-	var mailboxesList = make([]string, 0, mailboxCountGuesstimate)
+	var serverMailboxes = make([]*imap.MailboxInfo, 0, mailboxCountGuesstimate)
 	for m := range mailboxes {
 		logger.Debug().Msg("collected mailbox from channel")
-		mailboxesList = append(mailboxesList, m.Name)
+		serverMailboxes = append(serverMailboxes, m)
 	}
 
 	if err := <-done; err != nil {
 		logger.Error().Err(err).Msg("Error occurred listing mailboxes")
 
-		return nil, err
+		return nil, fmt.Errorf(
+			"error occurred listing mailboxes: %w: %w",
+			commandErrorKind(err, ErrProtocol),
+			err,
+		)
 	}
 
-	logger.Debug().Msg("no errors encountered listing mailboxes")
-	return mailboxesList, nil
+	return serverMailboxes, nil
 
 }
 
 // ValidateMailboxesList receives a list of requested mailboxes and returns a
 // list of mailboxes from that list which have been confirmed to be present
-// for the associated user account.
-func ValidateMailboxesList(c *client.Client, userMBXList []string, logger zerolog.Logger) ([]string, error) {
+// for the associated user account. If any requested mailboxes are not
+// present, the mailboxes which were found are returned along with a
+// *MissingMailboxesError listing every missing mailbox.
+func ValidateMailboxesList(c *Session, userMBXList []string, logger zerolog.Logger) ([]string, error) {
 
 	// Get list of mailboxes on server to compare against user mailbox list.
-	serverMBXList, listErr := ListMailboxes(c, logger)
+	serverMailboxes, listErr := listMailboxInfo(c, logger)
 	if listErr != nil {
 		return nil, listErr
 	}
 
+	return validateMailboxes(userMBXList, serverMailboxes, logger)
+}
+
+// validateMailboxes receives a list of requested mailboxes and returns a list
+// of mailboxes from that list which are present in the given list of
+// mailboxes found on the server. International (modified UTF-7 encoded)
+// mailbox names are returned decoded. Mailboxes which are not found are
+// returned as a *MissingMailboxesError along with the mailboxes which were
+// found.
+func validateMailboxes(userMBXList []string, serverMailboxes []*imap.MailboxInfo, logger zerolog.Logger) ([]string, error) {
+
 	// List out detected mailboxes for debugging purposes.
-	for _, m := range serverMBXList {
-		logger.Debug().Str("mailbox", m).Msg("")
+	for _, m := range serverMailboxes {
+		logger.Debug().
+			Str("mailbox", m.Name).
+			Str("delimiter", m.Delimiter).
+			Msg("")
 	}
 
 	// Confirm that requested folders are present on server
 	validatedMBXList := make([]string, 0, len(userMBXList))
+	var missing []string
 
 	for _, mbx := range userMBXList {
 		logger.Debug().Str("mailbox", mbx).Msg("Processing requested folder")
 
-		// At this point we are looping over the user requested
-		// folders/mailboxes, but haven't yet confirmed that they exist as
-		// mailboxes on the remote server.
+		// Special-use roles (e.g., \Junk) are resolved to the mailbox
+		// having the matching attribute.
+		if IsSpecialUseRole(mbx) {
+			name, found := specialUseMailbox(mbx, serverMailboxes)
+			if !found {
+				logger.Error().Str("role", mbx).Bool("found", false).Msg("")
+				missing = append(missing, mbx)
 
-		if strings.ToLower(mbx) == "inbox" ||
-			strings.HasPrefix(strings.ToLower(mbx), "inbox/") {
-
-			// NOTE: The "inbox" mailbox/folder name is NOT case-sensitive,
-			// but *all* others should be considered case-sensitive. We should
-			// be able to safely skip validating "inbox" here since it is a
-			// required mailbox/folder name, but all the same we will play it
-			// safe and perform a case-insensitive check for a match.
-			logger.Debug().Str("mailbox", mbx).Msg("Performing case-insensitive validation")
-			if textutils.InList(mbx, serverMBXList, true) {
-				validatedMBXList = append(validatedMBXList, mbx)
+				continue
 			}
 
+			logger.Debug().
+				Str("role", mbx).
+				Str("mailbox", name).
+				Msg("Resolved special-use role")
+			validatedMBXList = append(validatedMBXList, name)
+
 			continue
 		}
 
-		logger.Debug().Str("mailbox", mbx).Msg("Performing case-sensitive validation")
-		if !textutils.InList(mbx, serverMBXList, false) {
+		mbx = NormalizeMailboxName(mbx)
+
+		// At this point we are looping over the user requested
+		// folders/mailboxes, but haven't yet confirmed that they exist as
+		// mailboxes on the remote server.
+		//
+		// NOTE: The "inbox" mailbox/folder name (including as the first
+		// level of a hierarchy using the delimiter reported by the server)
+		// is NOT case-sensitive, but *all* others should be considered
+		// case-sensitive.
+		found := false
+		for _, m := range serverMailboxes {
+			if folderNamesEqual(mbx, m.Name, m.Delimiter) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
 			logger.Error().Str("mailbox", mbx).Bool("found", false).Msg("")
+			missing = append(missing, mbx)
 
-			return nil, fmt.Errorf("mailbox not found: %q", mbx)
+			continue
 		}
 
 		// At this point we have confirmed that the requested folder to
@@ -104,13 +187,274 @@ func ValidateMailboxesList(c *client.Client, userMBXList []string, logger zerolo
 
 	}
 
+	if len(missing) > 0 {
+		return validatedMBXList, &MissingMailboxesError{Mailboxes: missing}
+	}
+
 	return validatedMBXList, nil
 
 }
 
+// CountMail generates a count of emails within the provided (and validated)
+// mailbox list for the associated account name. The STATUS command is used
+// to retrieve message counts without selecting mailboxes or fetching message
+// details. This is considerably faster than CheckMail for mailboxes with
+// many messages.
+//
+// If a message filter is given only matching messages are counted. Unless
+// only unseen messages are counted, each mailbox is opened read-only in
+// order to apply the filter using the SEARCH command. The unseen messages
+// among the matching messages are then counted using an additional search.
+//
+// An error retrieving the status of (or searching) a mailbox is recorded in
+// the result for that mailbox and the remaining mailboxes are still
+// checked.
+func CountMail(c *Session, accountName string, validatedMBXList []string, filter MessageFilter, logger zerolog.Logger) (MailboxCheckResults, error) {
+
+	search, criteriaErr := filter.SearchCriteria(time.Now())
+	if criteriaErr != nil {
+		return nil, fmt.Errorf("%s: %w", accountName, criteriaErr)
+	}
+
+	// The UIDVALIDITY and next UID values are used to determine which
+	// messages are new in delta mode and the rate at which messages arrive.
+	statusItems := []imap.StatusItem{
+		imap.StatusMessages,
+		imap.StatusUnseen,
+		imap.StatusRecent,
+		imap.StatusUidValidity,
+		imap.StatusUidNext,
+	}
+	delta := filter.Checkpoints != nil
+
+	results := make(MailboxCheckResults, 0, len(validatedMBXList))
+	for _, folder := range validatedMBXList {
+
+		logger.Debug().Str("mailbox", folder).Msg("Requesting mailbox status")
+		mailbox, statusErr := mailboxStatus(c, folder, statusItems)
+		if statusErr != nil {
+			logger.Error().
+				Err(statusErr).
+				Str("mailbox", folder).
+				Msg("Error occurred requesting mailbox status")
+
+			results = append(results, MailboxCheckResult{
+				MailboxName: folder,
+				Criteria:    filter.Labels(),
+				Err: fmt.Errorf(
+					"%s: error occurred requesting mailbox status: %w: %w",
+					accountName,
+					commandErrorKind(statusErr, ErrProtocol),
+					statusErr,
+				),
+			})
+
+			continue
+		}
+
+		logger.Debug().
+			Str("mailbox", folder).
+			Uint32("messages_found", mailbox.Messages).
+			Uint32("unseen_found", mailbox.Unseen).
+			Uint32("recent_found", mailbox.Recent).
+			Msg("Recorded mailbox status")
+
+		result := MailboxCheckResult{
+			MailboxName:     folder,
+			ItemsFound:      int(mailbox.Messages),
+			MailboxMessages: int(mailbox.Messages),
+			UnseenFound:     int(mailbox.Unseen),
+			RecentFound:     int(mailbox.Recent),
+			Criteria:        filter.Labels(),
+		}
+
+		var cp Checkpoint
+		var cpFound bool
+		if delta {
+			cp, cpFound, result.CheckpointReset = filter.checkpoint(folder, mailbox.UidValidity)
+			logCheckpoint(logger, folder, cp, cpFound, result.CheckpointReset)
+		}
+
+		var uids []uint32
+
+		// The unseen count from the STATUS command covers the whole mailbox;
+		// if only some messages are counted the unseen messages among them
+		// are counted by searching the mailbox.
+		folderSearch := search
+		switch {
+		case cpFound && !hasNewMessages(mailbox, cp):
+			result.ItemsFound = 0
+			result.UnseenFound = 0
+		case cpFound:
+			folderSearch = newMessagesSearch(search, cp)
+			fallthrough
+		case search != nil && !unseenOnly(filter) && mailbox.Messages > 0:
+			var searchErr error
+			uids, searchErr = searchMailbox(c, folder, folderSearch, logger)
+			if searchErr == nil {
+				if cpFound {
+					uids = newUIDs(uids, cp)
+				}
+				result.UnseenFound, searchErr = countUnseen(c.Client, folder, folderSearch, uids, logger)
+			}
+			if searchErr != nil {
+				result.ItemsFound = 0
+				result.UnseenFound = 0
+				result.Err = fmt.Errorf("%s: %w", accountName, searchErr)
+				results = append(results, result)
+
+				continue
+			}
+			result.ItemsFound = len(uids)
+		case unseenOnly(filter):
+			result.ItemsFound = int(mailbox.Unseen)
+		}
+
+		result.Checkpoint = nextCheckpoint(mailbox, cp, uids)
+
+		logger.Info().Msgf("%d mail items found in %q for %s",
+			result.ItemsFound, folder, accountName)
+
+		results = append(results, result)
+
+	}
+
+	return results, nil
+
+}
+
+// searchMailbox opens the specified mailbox read-only and returns the UIDs
+// of messages matching the given search criteria.
+func searchMailbox(c *Session, folder string, search *imap.SearchCriteria, logger zerolog.Logger) ([]uint32, error) {
+	logger.Debug().Str("mailbox", folder).Msg("Examining mailbox")
+	if _, err := selectMailbox(c, folder, true); err != nil {
+		logger.Error().
+			Err(err).
+			Str("mailbox", folder).
+			Msg("Error occurred examining mailbox")
+
+		return nil, fmt.Errorf(
+			"error occurred examining mailbox %s: %w: %w",
+			folder,
+			commandErrorKind(err, ErrProtocol),
+			err,
+		)
+	}
+
+	return searchSelectedMailbox(c.Client, folder, search, logger)
+}
+
+// countUnseen returns the number of the given matched message UIDs for
+// messages without the \Seen flag in the currently selected mailbox. The
+// mailbox is searched again using the given search criteria narrowed to
+// unseen messages unless the criteria already exclude seen messages.
+func countUnseen(c *client.Client, folder string, search *imap.SearchCriteria, matched []uint32, logger zerolog.Logger) (int, error) {
+	if len(matched) == 0 {
+		return 0, nil
+	}
+
+	if search != nil && slices.Contains(search.WithoutFlags, imap.SeenFlag) {
+		return len(matched), nil
+	}
+
+	unseen, err := searchSelectedMailbox(c, folder, unseenSearch(search), logger)
+	if err != nil {
+		return 0, err
+	}
+
+	matchedUIDs := make(map[uint32]struct{}, len(matched))
+	for _, uid := range matched {
+		matchedUIDs[uid] = struct{}{}
+	}
+
+	var count int
+	for _, uid := range unseen {
+		if _, ok := matchedUIDs[uid]; ok {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// searchSelectedMailbox returns the UIDs of messages in the currently
+// selected mailbox matching the given search criteria.
+func searchSelectedMailbox(c *client.Client, folder string, search *imap.SearchCriteria, logger zerolog.Logger) ([]uint32, error) {
+	logger.Debug().Str("mailbox", folder).Msg("Searching mailbox")
+	uids, err := c.UidSearch(search)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("mailbox", folder).
+			Msg("Error occurred searching mailbox")
+
+		return nil, fmt.Errorf(
+			"error occurred searching mailbox %s: %w: %w",
+			folder,
+			commandErrorKind(err, ErrProtocol),
+			err,
+		)
+	}
+
+	logger.Debug().
+		Str("mailbox", folder).
+		Int("messages_matched", len(uids)).
+		Msg("Search completed")
+
+	return uids, nil
+}
+
+// FetchOptions controls how message details are retrieved by CheckMail.
+type FetchOptions struct {
+	// BatchSize is the number of messages retrieved per FETCH command. The
+	// default batch size is used if not specified.
+	BatchSize int
+
+	// MaxMessages is the maximum number of (most recent) messages retrieved
+	// per mailbox. All messages are counted, but only details for this
+	// number of messages are retrieved. All messages are retrieved if not
+	// specified.
+	MaxMessages int
+
+	// OnMessage, if specified, is called for each message as it is
+	// retrieved. Messages passed to this function are not retained in the
+	// returned results in order to limit memory usage. An error returned
+	// from this function aborts the mail check.
+	OnMessage func(mailbox string, msg Message) error
+}
+
+// batchSize returns the specified batch size or the default if not
+// specified.
+func (fo FetchOptions) batchSize() int {
+	if fo.BatchSize <= 0 {
+		return DefaultFetchBatchSize
+	}
+
+	return fo.BatchSize
+}
+
 // CheckMail generates a listing of emails within the provided (and validated)
-// mailbox list for the associated account name.
-func CheckMail(c *client.Client, accountName string, validatedMBXList []string, logger zerolog.Logger) (MailboxCheckResults, error) {
+// mailbox list for the associated account name. If a message filter is given
+// only matching messages are listed.
+//
+// Message details are retrieved in batches of UIDs as specified by the given
+// fetch options.
+//
+// An error examining, searching or listing the messages in a mailbox is
+// recorded in the result for that mailbox and the remaining mailboxes are
+// still checked. An error returned by the OnMessage function aborts the mail
+// check.
+func CheckMail(c *Session, accountName string, validatedMBXList []string, filter MessageFilter, opts FetchOptions, logger zerolog.Logger) (MailboxCheckResults, error) {
+
+	search, criteriaErr := filter.SearchCriteria(time.Now())
+	if criteriaErr != nil {
+		return nil, fmt.Errorf("%s: %w", accountName, criteriaErr)
+	}
+
+	// All messages are listed if no filter is specified.
+	if search == nil {
+		search = imap.NewSearchCriteria()
+	}
 
 	// Process validated mailboxes list to determine number of emails within
 	// each of them. Based on our existing check and manual processing
@@ -122,19 +466,29 @@ func CheckMail(c *client.Client, accountName string, validatedMBXList []string,
 	results := make(MailboxCheckResults, 0, 10)
 	for _, folder := range validatedMBXList {
 
-		logger.Debug().Str("mailbox", folder).Msg("Selecting mailbox")
-		mailbox, selectErr := c.Select(folder, false)
+		// Open the mailbox read-only (EXAMINE) so that the \Recent flag is
+		// not cleared for messages in the mailbox.
+		logger.Debug().Str("mailbox", folder).Msg("Examining mailbox")
+		mailbox, selectErr := selectMailbox(c, folder, true)
 		if selectErr != nil {
 			logger.Error().
 				Err(selectErr).
 				Str("mailbox", folder).
-				Msg("Error occurred selecting mailbox")
+				Msg("Error occurred examining mailbox")
 
-			return nil, fmt.Errorf(
-				"%s: error occurred selecting mailbox: %w",
-				accountName,
-				selectErr,
-			)
+			results = append(results, MailboxCheckResult{
+				MailboxName: folder,
+				Criteria:    filter.Labels(),
+				Err: fmt.Errorf(
+					"%s: error occurred examining mailbox %s: %w: %w",
+					accountName,
+					folder,
+					commandErrorKind(selectErr, ErrProtocol),
+					selectErr,
+				),
+			})
+
+			continue
 		}
 
 		logger.Debug().Str("mailbox", folder).Msgf(
@@ -143,100 +497,333 @@ func CheckMail(c *client.Client, accountName string, validatedMBXList []string,
 			mailbox.Flags,
 		)
 
-		logger.Info().Msgf("%d mail items found in %q for %s",
-			mailbox.Messages, folder, accountName)
+		result := MailboxCheckResult{
+			MailboxName:     folder,
+			MailboxMessages: int(mailbox.Messages),
+			Criteria:        filter.Labels(),
+		}
+
+		// In delta mode only messages which arrived since the previous
+		// check are listed.
+		var cp Checkpoint
+		var cpFound bool
+		if filter.Checkpoints != nil {
+			cp, cpFound, result.CheckpointReset = filter.checkpoint(folder, mailbox.UidValidity)
+			logCheckpoint(logger, folder, cp, cpFound, result.CheckpointReset)
+		}
+		result.Checkpoint = nextCheckpoint(mailbox, cp, nil)
 
 		// List all email messages, if there are any
-		if mailbox.Messages == 0 {
+		if mailbox.Messages == 0 || (cpFound && !hasNewMessages(mailbox, cp)) {
 			logger.Debug().
 				Str("mailbox", folder).
 				Uint32("messages_found", mailbox.Messages).
 				Msg("no messages found")
 
 			// record "no results" so we can explicitly note this later
-			results = append(results, MailboxCheckResult{
-				MailboxName: folder,
-				ItemsFound:  0,
-			})
+			results = append(results, result)
 			continue
 		}
 
-		// specify message retrieval range: from 1 to total available
-		seqset := new(imap.SeqSet)
-		from := uint32(1)
-		to := mailbox.Messages
-		seqset.AddRange(from, to)
-
-		// room for 10 messages at once
-		messages := make(chan *imap.Message, 10)
-		done := make(chan error, 1)
-		go func() {
-			// room for one error response
-			done <- c.Fetch(seqset, []imap.FetchItem{imap.FetchEnvelope}, messages)
-		}()
-
-		// fmt.Printf("\n\nEmail messages in mailbox %q: \n\n", folder)
-
-		// collect all emails found within mailbox
-		messagesFound := make([]Message, 0, 10)
-		for msg := range messages {
-
-			var subject string
-			switch {
-			case !textutils.WithinUTF8MB3Range(msg.Envelope.Subject):
-				logger.Debug().Msg("Replacing Astral Unicode characters")
-				subject = textutils.ReplaceAstralUnicode(
-					msg.Envelope.Subject, DefaultReplacementString)
-			default:
-				logger.Debug().Msg("Using original subject line")
-				subject = msg.Envelope.Subject
-			}
-			// fmt.Println("*", subject)
+		folderSearch := search
+		if cpFound {
+			folderSearch = newMessagesSearch(search, cp)
+		}
 
-			// Replace any output formatting characters that may be present.
-			logger.Debug().Msg("Replacing any Textile characters known to cause issues")
-			subject = textutils.ReplaceTextileFormatCharacters(subject)
+		uids, searchErr := searchSelectedMailbox(c.Client, folder, folderSearch, logger)
+		if searchErr != nil {
+			result.Checkpoint = Checkpoint{}
+			result.Err = fmt.Errorf("%s: %w", accountName, searchErr)
+			results = append(results, result)
 
-			msgSummary := Message{
-				MessageID:             msg.Envelope.MessageId,
-				EnvelopeDate:          msg.Envelope.Date,
-				EnvelopeDateFormatted: msg.Envelope.Date.Format(time.RFC3339),
-				OriginalSubject:       msg.Envelope.Subject,
-			}
+			continue
+		}
+
+		if cpFound {
+			uids = newUIDs(uids, cp)
+		}
+
+		result.Checkpoint = nextCheckpoint(mailbox, cp, uids)
+
+		result.ItemsFound = len(uids)
+
+		logger.Info().Msgf("%d mail items found in %q for %s",
+			result.ItemsFound, folder, accountName)
 
-			// we only set the ModifiedSubject field if the subject line
-			// is actually modified from the original value.
-			if subject != msg.Envelope.Subject {
-				msgSummary.ModifiedSubject = subject
+		// Unseen messages are counted as message details are retrieved
+		// unless retrieval is limited; all matched messages are then
+		// searched for unseen messages instead.
+		countFetchedUnseen := true
+
+		// UIDs are assigned in ascending order as messages are added to the
+		// mailbox; the most recent messages have the highest UIDs.
+		sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+		if opts.MaxMessages > 0 && len(uids) > opts.MaxMessages {
+			logger.Debug().
+				Str("mailbox", folder).
+				Int("messages_found", len(uids)).
+				Int("max_messages", opts.MaxMessages).
+				Msg("Limiting retrieval to most recent messages")
+
+			unseen, unseenErr := countUnseen(c.Client, folder, folderSearch, uids, logger)
+			if unseenErr != nil {
+				result.Checkpoint = Checkpoint{}
+				result.Err = fmt.Errorf("%s: %w", accountName, unseenErr)
+				results = append(results, result)
+
+				continue
+			}
+			result.UnseenFound = unseen
+			countFetchedUnseen = false
+
+			// Details are not retrieved for the oldest messages; the
+			// message with the lowest UID was added to the mailbox first.
+			oldest, dateErr := fetchInternalDate(c.Client, uids[0])
+			if dateErr != nil {
+				logger.Error().
+					Err(dateErr).
+					Str("mailbox", folder).
+					Msg("Error occurred retrieving oldest message date")
+
+				result.Checkpoint = Checkpoint{}
+				result.Err = fmt.Errorf(
+					"%s: error occurred retrieving oldest message date in mailbox %s: %w: %w",
+					accountName,
+					folder,
+					commandErrorKind(dateErr, ErrProtocol),
+					dateErr,
+				)
+				results = append(results, result)
+
+				continue
 			}
+			result.trackMessageDate(oldest)
 
-			messagesFound = append(messagesFound, msgSummary)
+			uids = uids[len(uids)-opts.MaxMessages:]
+		}
 
+		if opts.OnMessage == nil {
+			result.Messages = make([]Message, 0, len(uids))
 		}
 
-		// block until we get a response
-		if err := <-done; err != nil {
-			logger.Error().
-				Err(err).
+		// Errors from the OnMessage function abort the mail check; errors
+		// retrieving messages are recorded for the mailbox.
+		var onMessageErr error
+
+		batchSize := opts.batchSize()
+		for start := 0; start < len(uids); start += batchSize {
+			end := min(start+batchSize, len(uids))
+
+			logger.Debug().
 				Str("mailbox", folder).
-				Msg("Error occurred listing emails in mailbox")
+				Uint32("first_uid", uids[start]).
+				Uint32("last_uid", uids[end-1]).
+				Int("batch_size", end-start).
+				Msg("Fetching message batch")
+
+			fetchErr := fetchMessages(c.Client, uids[start:end], logger, func(msg Message) error {
+				result.trackMessageDate(msg.ArrivalDate())
+				if countFetchedUnseen && !msg.HasFlag(imap.SeenFlag) {
+					result.UnseenFound++
+				}
+
+				if opts.OnMessage != nil {
+					onMessageErr = opts.OnMessage(folder, msg)
+					return onMessageErr
+				}
 
-			return nil, fmt.Errorf(
-				"%s: error occurred listing emails in mailbox %s",
-				accountName,
-				folder,
-			)
+				result.Messages = append(result.Messages, msg)
 
+				return nil
+			})
+			if onMessageErr != nil {
+				return nil, fmt.Errorf(
+					"%s: error occurred processing emails in mailbox %s: %w",
+					accountName,
+					folder,
+					onMessageErr,
+				)
+			}
+
+			if fetchErr != nil {
+				logger.Error().
+					Err(fetchErr).
+					Str("mailbox", folder).
+					Msg("Error occurred listing emails in mailbox")
+
+				result.Checkpoint = Checkpoint{}
+				result.Err = fmt.Errorf(
+					"%s: error occurred listing emails in mailbox %s: %w: %w",
+					accountName,
+					folder,
+					commandErrorKind(fetchErr, ErrProtocol),
+					fetchErr,
+				)
+
+				break
+			}
 		}
 
-		results = append(results, MailboxCheckResult{
-			MailboxName: folder,
-			ItemsFound:  int(mailbox.Messages),
-			Messages:    messagesFound,
-		})
+		logger.Debug().
+			Str("mailbox", folder).
+			Time("oldest_message", result.OldestMessageDate).
+			Time("newest_message", result.NewestMessageDate).
+			Msg("Recorded message arrival date range")
+
+		results = append(results, result)
 
 	}
 
 	return results, nil
 
 }
+
+// messageFetchItems is the collection of message attributes retrieved for
+// each message when message details are needed.
+var messageFetchItems = []imap.FetchItem{
+	imap.FetchEnvelope,
+	imap.FetchInternalDate,
+	imap.FetchUid,
+	imap.FetchRFC822Size,
+	imap.FetchFlags,
+}
+
+// fetchMessages retrieves message details for the given UIDs from the
+// currently selected mailbox and passes each message to the given function
+// as it is received.
+func fetchMessages(c *client.Client, uids []uint32, logger zerolog.Logger, fn func(Message) error) error {
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+
+	// room for 10 messages at once
+	messages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		// room for one error response
+		done <- c.UidFetch(seqset, messageFetchItems, messages)
+	}()
+
+	// Continue draining the channel after a callback error so that the
+	// fetch can complete.
+	var fnErr error
+	for msg := range messages {
+		if fnErr != nil || msg.Envelope == nil {
+			continue
+		}
+
+		fnErr = fn(newMessage(msg, logger))
+	}
+
+	// block until we get a response
+	if err := <-done; err != nil {
+		return err
+	}
+
+	return fnErr
+}
+
+// fetchInternalDate retrieves the date the message with the given UID was
+// received by the server (INTERNALDATE) from the currently selected mailbox.
+// A zero value is returned if the message no longer exists.
+func fetchInternalDate(c *client.Client, uid uint32) (time.Time, error) {
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+
+	messages := make(chan *imap.Message, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.UidFetch(seqset, []imap.FetchItem{imap.FetchUid, imap.FetchInternalDate}, messages)
+	}()
+
+	var date time.Time
+	for msg := range messages {
+		if msg.Uid == uid {
+			date = msg.InternalDate
+		}
+	}
+
+	if err := <-done; err != nil {
+		return time.Time{}, err
+	}
+
+	return date, nil
+}
+
+// newMessage converts a retrieved IMAP message into a Message value.
+func newMessage(msg *imap.Message, logger zerolog.Logger) Message {
+	var subject string
+	switch {
+	case !textutils.WithinUTF8MB3Range(msg.Envelope.Subject):
+		logger.Debug().Msg("Replacing Astral Unicode characters")
+		subject = textutils.ReplaceAstralUnicode(
+			msg.Envelope.Subject, DefaultReplacementString)
+	default:
+		logger.Debug().Msg("Using original subject line")
+		subject = msg.Envelope.Subject
+	}
+
+	// Replace any output formatting characters that may be present.
+	logger.Debug().Msg("Replacing any Textile characters known to cause issues")
+	subject = textutils.ReplaceTextileFormatCharacters(subject)
+
+	msgSummary := Message{
+		MessageID:             msg.Envelope.MessageId,
+		UID:                   msg.Uid,
+		EnvelopeDate:          msg.Envelope.Date,
+		EnvelopeDateFormatted: msg.Envelope.Date.Format(time.RFC3339),
+		InternalDate:          msg.InternalDate,
+		OriginalSubject:       msg.Envelope.Subject,
+		From:                  formatAddressList(msg.Envelope.From),
+		Sender:                formatAddressList(msg.Envelope.Sender),
+		ReplyTo:               formatAddressList(msg.Envelope.ReplyTo),
+		To:                    formatAddressList(msg.Envelope.To),
+		Size:                  msg.Size,
+		Flags:                 msg.Flags,
+	}
+
+	if !msg.InternalDate.IsZero() {
+		msgSummary.InternalDateFormatted = msg.InternalDate.Format(time.RFC3339)
+	}
+
+	// we only set the ModifiedSubject field if the subject line
+	// is actually modified from the original value.
+	if subject != msg.Envelope.Subject {
+		msgSummary.ModifiedSubject = subject
+	}
+
+	return msgSummary
+}
+
+// formatAddressList returns the given envelope addresses as a
+// comma-separated list sanitized for use in reports. Each address is listed
+// in "Name <address>" format if a display name is set. Group syntax markers
+// (RFC 2822 section 3.4) are omitted.
+func formatAddressList(addresses []*imap.Address) string {
+	formatted := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		if addr == nil || addr.MailboxName == "" || addr.HostName == "" {
+			continue
+		}
+
+		entry := addr.Address()
+		if addr.PersonalName != "" {
+			entry = fmt.Sprintf("%s <%s>", addr.PersonalName, entry)
+		}
+
+		formatted = append(formatted, sanitizeHeaderValue(entry))
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// sanitizeHeaderValue replaces characters in a (decoded) header value which
+// are incompatible with the utf8mb3 character set or which would break a
+// report row (e.g., line breaks). Characters with special meaning in a
+// report format (e.g., Textile) are escaped when the report is written.
+func sanitizeHeaderValue(s string) string {
+	if !textutils.WithinUTF8MB3Range(s) {
+		s = textutils.ReplaceAstralUnicode(s, DefaultReplacementString)
+	}
+
+	return textutils.ReplaceControlCharacters(s, " ")
+}
@@ -0,0 +1,475 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TestCountMail asserts that message counts are retrieved via the STATUS
+// command without selecting the mailbox.
+func TestCountMail(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"STATUS": func(args string) ([]string, error) {
+			return []string{
+				`* STATUS "INBOX" (MESSAGES 42 UNSEEN 3 RECENT 1)`,
+			}, nil
+		},
+		"SELECT": func(string) ([]string, error) {
+			return nil, errors.New("unexpected SELECT")
+		},
+	})
+
+	results, err := CountMail(c, "user", []string{"INBOX"}, MessageFilter{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("want 1 result, got %d", len(results))
+	}
+
+	got := results[0]
+	if got.ItemsFound != 42 || got.UnseenFound != 3 || got.RecentFound != 1 {
+		t.Errorf(
+			"want 42 messages, 3 unseen, 1 recent; got %d messages, %d unseen, %d recent",
+			got.ItemsFound,
+			got.UnseenFound,
+			got.RecentFound,
+		)
+	}
+
+	if len(got.Messages) != 0 {
+		t.Errorf("want no message details, got %d messages", len(got.Messages))
+	}
+}
+
+// TestCheckMailExaminesMailbox asserts that mailboxes are opened read-only
+// so that the \Recent flag is not cleared.
+func TestCheckMailExaminesMailbox(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"EXAMINE": func(string) ([]string, error) {
+			return []string{
+				`* FLAGS (\Seen \Answered \Flagged \Deleted \Draft)`,
+				`* 0 EXISTS`,
+				`* 0 RECENT`,
+			}, nil
+		},
+		"SELECT": func(string) ([]string, error) {
+			return nil, errors.New("unexpected SELECT")
+		},
+	})
+
+	results, err := CheckMail(c, "user", []string{"INBOX"}, MessageFilter{}, FetchOptions{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results.TotalMessagesFound() != 0 {
+		t.Errorf("want 0 messages, got %d", results.TotalMessagesFound())
+	}
+}
+
+// TestCountMailCriteria asserts that message criteria are applied using the
+// SEARCH command, or the STATUS command when only counting unseen messages,
+// and that only unseen messages matching the criteria are counted.
+func TestCountMailCriteria(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		criteria     []string
+		wantSearches []string
+		want         int
+		wantUnseen   int
+	}{
+		"unseen only": {
+			criteria:   []string{MessageCriterionUnseen},
+			want:       3,
+			wantUnseen: 3,
+		},
+		"unseen and flagged": {
+			criteria:     []string{MessageCriterionUnseen, MessageCriterionFlagged},
+			wantSearches: []string{`FLAGGED UNSEEN`},
+			want:         2,
+			wantUnseen:   2,
+		},
+		"deleted": {
+			criteria:     []string{MessageCriterionDeleted},
+			wantSearches: []string{`DELETED`, `DELETED UNSEEN`},
+			want:         2,
+			wantUnseen:   1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotSearches []string
+			c := newTestClient(t, "", map[string]testCommandHandler{
+				"STATUS": func(string) ([]string, error) {
+					return []string{
+						`* STATUS "INBOX" (MESSAGES 42 UNSEEN 3 RECENT 1)`,
+					}, nil
+				},
+				"EXAMINE": func(string) ([]string, error) {
+					return []string{`* 42 EXISTS`, `* 1 RECENT`}, nil
+				},
+				"UID": func(args string) ([]string, error) {
+					search := strings.TrimPrefix(args, "SEARCH CHARSET UTF-8 ")
+					gotSearches = append(gotSearches, search)
+					if search == `DELETED UNSEEN` {
+						return []string{`* SEARCH 9 12`}, nil
+					}
+
+					return []string{`* SEARCH 7 9`}, nil
+				},
+			})
+
+			results, err := CountMail(c, "user", []string{"INBOX"}, MessageFilter{Criteria: tt.criteria}, zerolog.Nop())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := results.TotalMessagesFound(); got != tt.want {
+				t.Errorf("want %d messages, got %d", tt.want, got)
+			}
+
+			if got := results[0].UnseenFound; got != tt.wantUnseen {
+				t.Errorf("want %d unseen messages, got %d", tt.wantUnseen, got)
+			}
+
+			if !slices.Equal(gotSearches, tt.wantSearches) {
+				t.Errorf("want searches %q, got %q", tt.wantSearches, gotSearches)
+			}
+		})
+	}
+}
+
+// TestSearchCriteriaInvalid asserts that unsupported criteria are rejected.
+func TestSearchCriteriaInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := SearchCriteria([]string{"unread"})
+	if !errors.Is(err, ErrInvalidMessageCriterion) {
+		t.Errorf("want error %v, got %v", ErrInvalidMessageCriterion, err)
+	}
+}
+
+// TestCheckMailBatches asserts that message details are retrieved in UID
+// batches, limited to the most recent messages and passed to the OnMessage
+// function instead of being retained, and that unseen messages are still
+// counted among all matched messages.
+func TestCheckMailBatches(t *testing.T) {
+	t.Parallel()
+
+	var fetched []string
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"EXAMINE": func(string) ([]string, error) {
+			return []string{`* 5 EXISTS`, `* 0 RECENT`}, nil
+		},
+		"UID": func(args string) ([]string, error) {
+			fields := strings.Fields(args)
+			switch fields[0] {
+			case "SEARCH":
+				if strings.Contains(args, "UNSEEN") {
+					return []string{`* SEARCH 1 5`}, nil
+				}
+
+				return []string{`* SEARCH 5 1 2 3 4`}, nil
+			case "FETCH":
+				if strings.Contains(args, "ENVELOPE") {
+					fetched = append(fetched, fields[1])
+				}
+
+				var lines []string
+				for _, uid := range strings.Split(fields[1], ":") {
+					lines = append(lines, fmt.Sprintf(
+						`* %s FETCH (UID %s INTERNALDATE "10-Mar-2025 1%s:00:00 +0000" `+
+							`ENVELOPE ("Mon, 10 Mar 2025 10:00:00 +0000" "message %s" NIL NIL NIL NIL NIL NIL NIL "<%s@example.com>"))`,
+						uid, uid, uid, uid, uid,
+					))
+				}
+
+				return lines, nil
+			}
+
+			return nil, errors.New("unexpected UID command")
+		},
+	})
+
+	var streamed []string
+	opts := FetchOptions{
+		BatchSize:   2,
+		MaxMessages: 4,
+		OnMessage: func(mailbox string, msg Message) error {
+			streamed = append(streamed, mailbox+": "+msg.OriginalSubject)
+			return nil
+		},
+	}
+
+	results, err := CheckMail(c, "user", []string{"INBOX"}, MessageFilter{}, opts, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want, got := []string{"2:3", "4:5"}, fetched; !slices.Equal(want, got) {
+		t.Errorf("want UID batches %v, got %v", want, got)
+	}
+
+	if want, got := 4, len(streamed); want != got {
+		t.Errorf("want %d streamed messages, got %d: %v", want, got, streamed)
+	}
+
+	if want, got := 5, results.TotalMessagesFound(); want != got {
+		t.Errorf("want %d messages found, got %d", want, got)
+	}
+
+	if want, got := 2, results[0].UnseenFound; want != got {
+		t.Errorf("want %d unseen messages, got %d", want, got)
+	}
+
+	if len(results[0].Messages) != 0 {
+		t.Errorf("want streamed messages to not be retained, got %d", len(results[0].Messages))
+	}
+
+	if want, got := time.Date(2025, time.March, 10, 15, 0, 0, 0, time.UTC), results[0].NewestMessageDate; !want.Equal(got) {
+		t.Errorf("want newest message date %v, got %v", want, got)
+	}
+}
+
+// TestCheckMailCappedOldestDate asserts that the oldest message date is
+// determined from the message with the lowest UID when only the most recent
+// messages are retrieved.
+func TestCheckMailCappedOldestDate(t *testing.T) {
+	t.Parallel()
+
+	var dateFetches []string
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"EXAMINE": func(string) ([]string, error) {
+			return []string{`* 5 EXISTS`, `* 0 RECENT`}, nil
+		},
+		"UID": func(args string) ([]string, error) {
+			fields := strings.Fields(args)
+			switch {
+			case fields[0] == "SEARCH" && strings.Contains(args, "UNSEEN"):
+				return []string{`* SEARCH`}, nil
+			case fields[0] == "SEARCH":
+				return []string{`* SEARCH 1 2 3 4 5`}, nil
+			case fields[0] == "FETCH" && !strings.Contains(args, "ENVELOPE"):
+				dateFetches = append(dateFetches, fields[1])
+				return []string{`* 1 FETCH (UID 1 INTERNALDATE "01-Mar-2025 09:00:00 +0000")`}, nil
+			case fields[0] == "FETCH":
+				var lines []string
+				for _, uid := range strings.Split(fields[1], ":") {
+					lines = append(lines, fmt.Sprintf(
+						`* %s FETCH (UID %s INTERNALDATE "10-Mar-2025 1%s:00:00 +0000" `+
+							`ENVELOPE ("Mon, 10 Mar 2025 10:00:00 +0000" "message %s" NIL NIL NIL NIL NIL NIL NIL "<%s@example.com>"))`,
+						uid, uid, uid, uid, uid,
+					))
+				}
+
+				return lines, nil
+			}
+
+			return nil, errors.New("unexpected UID command")
+		},
+	})
+
+	results, err := CheckMail(c, "user", []string{"INBOX"}, MessageFilter{}, FetchOptions{MaxMessages: 2}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := results[0]
+	if got.Err != nil {
+		t.Fatalf("unexpected mailbox error: %v", got.Err)
+	}
+
+	if want := []string{"1"}; !slices.Equal(want, dateFetches) {
+		t.Errorf("want INTERNALDATE fetched for UIDs %v, got %v", want, dateFetches)
+	}
+
+	if want, got := 2, len(got.Messages); want != got {
+		t.Errorf("want %d retrieved messages, got %d", want, got)
+	}
+
+	if want := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC); !want.Equal(got.OldestMessageDate) {
+		t.Errorf("want oldest message date %v, got %v", want, got.OldestMessageDate)
+	}
+
+	if want := time.Date(2025, time.March, 10, 15, 0, 0, 0, time.UTC); !want.Equal(got.NewestMessageDate) {
+		t.Errorf("want newest message date %v, got %v", want, got.NewestMessageDate)
+	}
+}
+
+// TestCheckMailMessageDetails asserts that sender and recipient addresses,
+// size, flags, UID and internal date are retrieved for each message and that
+// header values are decoded and sanitized.
+func TestCheckMailMessageDetails(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"EXAMINE": func(string) ([]string, error) {
+			return []string{`* 1 EXISTS`, `* 0 RECENT`}, nil
+		},
+		"UID": func(args string) ([]string, error) {
+			fields := strings.Fields(args)
+			switch fields[0] {
+			case "SEARCH":
+				return []string{`* SEARCH 42`}, nil
+			case "FETCH":
+				if !strings.Contains(args, "RFC822.SIZE") || !strings.Contains(args, "FLAGS") {
+					return nil, fmt.Errorf("missing fetch items: %s", args)
+				}
+
+				return []string{
+					`* 1 FETCH (UID 42 FLAGS (\Seen \Flagged) RFC822.SIZE 15360 ` +
+						`INTERNALDATE "10-Mar-2025 11:00:00 +0000" ` +
+						`ENVELOPE ("Mon, 10 Mar 2025 10:00:00 +0000" "Disk space low" ` +
+						`(("=?utf-8?q?Zo=C3=AB_|_Ops?=" NIL "zoe" "example.com")) ` +
+						`((NIL NIL "alerts" "example.com")) ` +
+						`(("Ops Team" NIL "ops" "example.com")) ` +
+						`(("Help Desk" NIL "help" "example.com")(NIL NIL "oncall" "example.com")) ` +
+						`NIL NIL NIL "<42@example.com>"))`,
+				}, nil
+			}
+
+			return nil, errors.New("unexpected UID command")
+		},
+	})
+
+	results, err := CheckMail(c, "user", []string{"INBOX"}, MessageFilter{}, FetchOptions{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results[0].Messages) != 1 {
+		t.Fatalf("want 1 message, got %d", len(results[0].Messages))
+	}
+
+	msg := results[0].Messages[0]
+
+	checks := []struct {
+		field string
+		want  string
+		got   string
+	}{
+		{field: "From", want: "Zoë | Ops <zoe@example.com>", got: msg.From},
+		{field: "Sender", want: "alerts@example.com", got: msg.Sender},
+		{field: "ReplyTo", want: "Ops Team <ops@example.com>", got: msg.ReplyTo},
+		{field: "To", want: "Help Desk <help@example.com>, oncall@example.com", got: msg.To},
+		{field: "SizeFormatted", want: "15.0 KiB", got: msg.SizeFormatted()},
+		{field: "InternalDateFormatted", want: "2025-03-10T11:00:00Z", got: msg.InternalDateFormatted},
+	}
+
+	for _, check := range checks {
+		if check.want != check.got {
+			t.Errorf("%s: want %q, got %q", check.field, check.want, check.got)
+		}
+	}
+
+	if msg.UID != 42 || msg.Size != 15360 {
+		t.Errorf("want UID 42 and size 15360, got UID %d and size %d", msg.UID, msg.Size)
+	}
+
+	if !msg.HasFlag(`\seen`) || !msg.HasFlag(`\Flagged`) || msg.HasFlag(`\Answered`) {
+		t.Errorf("unexpected flags: %v", msg.Flags)
+	}
+
+	if results[0].UnseenFound != 0 {
+		t.Errorf("want 0 unseen messages, got %d", results[0].UnseenFound)
+	}
+}
+
+// TestCountMailPartialResults asserts that an error retrieving the status of
+// one mailbox is recorded for that mailbox and that the remaining mailboxes
+// are still counted.
+func TestCountMailPartialResults(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"STATUS": func(args string) ([]string, error) {
+			if strings.Contains(args, "Broken") {
+				return nil, errors.New("mailbox is corrupt")
+			}
+
+			return []string{`* STATUS "INBOX" (MESSAGES 4 UNSEEN 0 RECENT 0)`}, nil
+		},
+	})
+
+	results, err := CountMail(c, "user", []string{"Broken", "INBOX"}, MessageFilter{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("want 2 results, got %d", len(results))
+	}
+
+	if results[0].Err == nil {
+		t.Error("want error recorded for Broken mailbox, got nil")
+	}
+
+	if results[1].Err != nil || results[1].ItemsFound != 4 {
+		t.Errorf("want 4 messages and no error for INBOX, got %d messages and error %v", results[1].ItemsFound, results[1].Err)
+	}
+
+	if failed := results.Failed(); len(failed) != 1 || failed[0].MailboxName != "Broken" {
+		t.Errorf("want only Broken mailbox reported as failed, got %q", failed.MailboxNames())
+	}
+
+	if want, got := "Broken(error), INBOX(4)", results.MessagesFoundSummary(); want != got {
+		t.Errorf("want summary %q, got %q", want, got)
+	}
+}
+
+// TestCheckMailPartialResults asserts that an error examining one mailbox is
+// recorded for that mailbox and that the remaining mailboxes are still
+// checked.
+func TestCheckMailPartialResults(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "", map[string]testCommandHandler{
+		"EXAMINE": func(args string) ([]string, error) {
+			if strings.Contains(args, "Broken") {
+				return nil, errors.New("mailbox is corrupt")
+			}
+
+			return []string{`* 0 EXISTS`, `* 0 RECENT`}, nil
+		},
+	})
+
+	results, err := CheckMail(c, "user", []string{"INBOX", "Broken"}, MessageFilter{}, FetchOptions{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want, got := []string{"INBOX", "Broken"}, results.MailboxNames(); !slices.Equal(want, got) {
+		t.Fatalf("want results for %q, got %q", want, got)
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("want no error for INBOX, got %v", results[0].Err)
+	}
+
+	if results.Err() == nil || !strings.Contains(results.Err().Error(), "mailbox is corrupt") {
+		t.Errorf("want error recorded for Broken mailbox, got %v", results.Err())
+	}
+}
@@ -8,7 +8,9 @@
 package mbxs
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -20,6 +22,13 @@ type Message struct {
 	// specified mailbox.
 	MessageID string
 
+	// UID is the unique identifier of the message within the mailbox. This
+	// value is only unique in combination with the UIDVALIDITY value of the
+	// mailbox.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc3501#section-2.3.1.1
+	UID uint32
+
 	// EnvelopeDate is the crafted date value for an email message found
 	// within a specific mailbox. This is the date set by the mail transport
 	// software that initially received the email message from the mail
@@ -31,19 +40,18 @@ type Message struct {
 	// use with templates.
 	EnvelopeDateFormatted string
 
-	// ReceivedDate is the date when the email message was received by the
-	// hosting mail server. This value is separate from EnvelopeDate (which is
-	// set by the first SMTP server used by the sending mail client) and the
-	// date header generated by the sending client's mail software.
-	//
-	// NOTE: In testing, this gave a wildly incorrect value. Example:
-	//
-	// EnvelopeDate: 2017-05-12 17:40:58 +0000 +0000
-	// EnvelopeLocalDate: 2017-05-12 12:40:58 -0500 CDT
-	// ReceivedDate: 0001-01-01 00:00:00 +0000 UTC
-	// ReceivedLocalDate: 0000-12-31 18:09:24 -0550 LMT
+	// InternalDate is the date when the email message was received by the
+	// hosting mail server (the IMAP INTERNALDATE message attribute). This
+	// value is separate from EnvelopeDate (which is set by the first SMTP
+	// server used by the sending mail client) and the date header generated
+	// by the sending client's mail software.
 	//
-	// ReceivedDate time.Time
+	// https://datatracker.ietf.org/doc/html/rfc3501#section-2.3.3
+	InternalDate time.Time
+
+	// InternalDateFormatted is the InternalDate value stored as a string for
+	// later use with templates.
+	InternalDateFormatted string
 
 	// OriginalSubject is the unmodified, original subject line of an email
 	// message found within a specified mailbox.
@@ -53,6 +61,63 @@ type Message struct {
 	// has been modified to remove characters incompatible with the a target
 	// character set (e.g., MySQL's utf8mb3).
 	ModifiedSubject string
+
+	// From is the comma-separated list of authors of the message as listed
+	// in the From header. Each entry is in "Name <address>" format if a
+	// display name is set. Control characters and characters incompatible
+	// with the utf8mb3 character set are replaced; the value is not escaped
+	// for any report format.
+	From string
+
+	// Sender is the agent responsible for sending the message (the Sender
+	// header) in the same format as From. Per RFC 3501 servers report the
+	// From value if the Sender header is not present.
+	Sender string
+
+	// ReplyTo is the comma-separated list of addresses replies should be
+	// sent to (the Reply-To header) in the same format as From.
+	ReplyTo string
+
+	// To is the comma-separated list of primary recipients of the message
+	// (the To header) in the same format as From.
+	To string
+
+	// Size is the size of the message in octets (the IMAP RFC822.SIZE
+	// message attribute).
+	Size uint32
+
+	// Flags is the collection of flags set on the message (e.g., \Seen or
+	// \Flagged).
+	Flags []string
+}
+
+// SizeFormatted returns the size of the message in human readable format
+// (e.g., "12.3 KiB").
+func (m Message) SizeFormatted() string {
+	return FormatMessageSize(m.Size)
+}
+
+// HasFlag indicates whether the specified flag (e.g., \Seen) is set on the
+// message. Flags are matched case-insensitively.
+func (m Message) HasFlag(flag string) bool {
+	for _, f := range m.Flags {
+		if strings.EqualFold(f, flag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ArrivalDate returns the date used to determine the age of an email
+// message. The InternalDate value is used if set, otherwise the EnvelopeDate
+// value is used. A zero value is returned if neither is available.
+func (m Message) ArrivalDate() time.Time {
+	if !m.InternalDate.IsZero() {
+		return m.InternalDate
+	}
+
+	return m.EnvelopeDate
 }
 
 // MailboxCheckResult records mail items found for a specific mailbox.
@@ -60,6 +125,144 @@ type MailboxCheckResult struct {
 	MailboxName string
 	ItemsFound  int
 	Messages    []Message
+
+	// UnseenFound is the number of messages counted in ItemsFound (those
+	// matching the message filter or new since the previous check) without
+	// the \Seen flag.
+	UnseenFound int
+
+	// RecentFound is the number of messages in the mailbox with the \Recent
+	// flag. This is only recorded when counting messages via the STATUS
+	// command.
+	RecentFound int
+
+	// Criteria is the collection of labels describing the message filter
+	// (e.g., "unseen" or "filtered") used to limit the messages counted and
+	// listed. All messages in the mailbox are counted if empty.
+	Criteria []string
+
+	// OldestMessageDate is the arrival date of the oldest message found in
+	// the mailbox. This is the zero value if no messages were found or if
+	// the arrival date could not be determined for any messages. If the
+	// number of messages retrieved is limited, the date the message with the
+	// lowest UID was received by the server is also considered.
+	OldestMessageDate time.Time
+
+	// NewestMessageDate is the arrival date of the newest message found in
+	// the mailbox. This is the zero value if no messages were found or if
+	// the arrival date could not be determined for any messages.
+	NewestMessageDate time.Time
+
+	// MailboxMessages is the total number of messages in the mailbox
+	// regardless of any message filter or delta mode.
+	MailboxMessages int
+
+	// Checkpoint is the position reached in the mailbox by this check. This
+	// is the zero value if the mailbox could not be checked.
+	Checkpoint Checkpoint
+
+	// CheckpointReset indicates that the checkpoint recorded for the mailbox
+	// by a previous check was discarded because the UIDVALIDITY value of the
+	// mailbox changed. All matching messages are treated as new.
+	CheckpointReset bool
+
+	// Err is the error encountered while checking the mailbox, if any. The
+	// count and message details are not reliable if set.
+	Err error
+}
+
+// OldestMessageAge returns the age of the oldest message found in the
+// mailbox relative to the given time or zero if not known.
+func (mcr MailboxCheckResult) OldestMessageAge(now time.Time) time.Duration {
+	return messageAge(now, mcr.OldestMessageDate)
+}
+
+// NewestMessageAge returns the age of the newest message found in the
+// mailbox relative to the given time or zero if not known.
+func (mcr MailboxCheckResult) NewestMessageAge(now time.Time) time.Duration {
+	return messageAge(now, mcr.NewestMessageDate)
+}
+
+// NewestMessage returns the newest message found in the mailbox or false if
+// no messages with a known arrival date were found.
+func (mcr MailboxCheckResult) NewestMessage() (Message, bool) {
+	var newest Message
+	var found bool
+
+	for _, msg := range mcr.Messages {
+		date := msg.ArrivalDate()
+		if date.IsZero() {
+			continue
+		}
+
+		if !found || date.After(newest.ArrivalDate()) {
+			newest = msg
+			found = true
+		}
+	}
+
+	return newest, found
+}
+
+// setMessageDateRange records the oldest and newest arrival dates from the
+// collected messages. Messages without a known arrival date are skipped.
+func (mcr *MailboxCheckResult) setMessageDateRange() {
+	for _, msg := range mcr.Messages {
+		mcr.trackMessageDate(msg.ArrivalDate())
+	}
+}
+
+// trackMessageDate updates the recorded oldest and newest arrival dates to
+// include the given date. Zero dates are ignored.
+func (mcr *MailboxCheckResult) trackMessageDate(date time.Time) {
+	if date.IsZero() {
+		return
+	}
+
+	if mcr.OldestMessageDate.IsZero() || date.Before(mcr.OldestMessageDate) {
+		mcr.OldestMessageDate = date
+	}
+
+	if mcr.NewestMessageDate.IsZero() || date.After(mcr.NewestMessageDate) {
+		mcr.NewestMessageDate = date
+	}
+}
+
+// FormatMessageAge returns a human-readable representation of the given
+// message age rounded to the nearest second.
+func FormatMessageAge(age time.Duration) string {
+	return age.Round(time.Second).String()
+}
+
+// FormatMessageSize returns the given message size in octets in human
+// readable format using binary (1024) units.
+func FormatMessageSize(size uint32) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size)
+	var suffix string
+	for _, suffix = range []string{"KiB", "MiB", "GiB"} {
+		value /= unit
+		if value < unit {
+			break
+		}
+	}
+
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// messageAge returns the duration between the given time and message date or
+// zero if the message date is not known. Dates in the future (e.g., due to
+// clock skew) are reported as zero.
+func messageAge(now time.Time, date time.Time) time.Duration {
+	if date.IsZero() || date.After(now) {
+		return 0
+	}
+
+	return now.Sub(date)
 }
 
 // MailboxCheckResults represents a collection of all results from mailbox
@@ -76,6 +279,38 @@ func (mcr MailboxCheckResults) GotMail() bool {
 	return false
 }
 
+// Failed returns the results for mailboxes which could not be checked.
+func (mcr MailboxCheckResults) Failed() MailboxCheckResults {
+	var failed MailboxCheckResults
+	for _, result := range mcr {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err returns the errors encountered while checking mailboxes joined
+// together or nil if all mailboxes were checked successfully.
+func (mcr MailboxCheckResults) Err() error {
+	var errs []error
+	for _, result := range mcr {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MailboxNames returns the names of all checked mailboxes.
+func (mcr MailboxCheckResults) MailboxNames() []string {
+	names := make([]string, 0, len(mcr))
+	for _, result := range mcr {
+		names = append(names, result.MailboxName)
+	}
+	return names
+}
+
 // TotalMessagesFound returns a count of all messages found across all checked
 // mailboxes.
 func (mcr MailboxCheckResults) TotalMessagesFound() int {
@@ -86,12 +321,105 @@ func (mcr MailboxCheckResults) TotalMessagesFound() int {
 	return total
 }
 
+// OldestMessageAge returns the age of the oldest message found across all
+// checked mailboxes relative to the given time or zero if not known.
+func (mcr MailboxCheckResults) OldestMessageAge(now time.Time) time.Duration {
+	var oldest time.Duration
+	for _, result := range mcr {
+		if age := result.OldestMessageAge(now); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest
+}
+
+// NewestMessageAge returns the age of the newest message found across all
+// checked mailboxes relative to the given time or zero if not known.
+func (mcr MailboxCheckResults) NewestMessageAge(now time.Time) time.Duration {
+	var newest time.Duration
+	var found bool
+	for _, result := range mcr {
+		if result.NewestMessageDate.IsZero() {
+			continue
+		}
+
+		age := result.NewestMessageAge(now)
+		if !found || age < newest {
+			newest = age
+			found = true
+		}
+	}
+	return newest
+}
+
+// MessagesSince returns a copy of the results limited to messages with an
+// arrival date on or after the given time. Messages without a known arrival
+// date are excluded.
+func (mcr MailboxCheckResults) MessagesSince(t time.Time) MailboxCheckResults {
+	filtered := make(MailboxCheckResults, 0, len(mcr))
+	for _, result := range mcr {
+		recent := MailboxCheckResult{
+			MailboxName: result.MailboxName,
+			Criteria:    result.Criteria,
+			Err:         result.Err,
+		}
+
+		for _, msg := range result.Messages {
+			date := msg.ArrivalDate()
+			if date.IsZero() || date.Before(t) {
+				continue
+			}
+			recent.Messages = append(recent.Messages, msg)
+		}
+
+		recent.ItemsFound = len(recent.Messages)
+		recent.setMessageDateRange()
+
+		filtered = append(filtered, recent)
+	}
+
+	return filtered
+}
+
+// NewestMessage returns the newest message found across all checked
+// mailboxes along with the name of the mailbox containing it. False is
+// returned if no messages with a known arrival date were found.
+func (mcr MailboxCheckResults) NewestMessage() (string, Message, bool) {
+	var newestMailbox string
+	var newest Message
+	var found bool
+
+	for _, result := range mcr {
+		if msg, ok := result.NewestMessage(); ok {
+			if !found || msg.ArrivalDate().After(newest.ArrivalDate()) {
+				newestMailbox = result.MailboxName
+				newest = msg
+				found = true
+			}
+		}
+	}
+
+	return newestMailbox, newest, found
+}
+
 // MessagesFoundSummary returns a one-line summary of the mail items found in
 // checked mailboxes.
 func (mcr MailboxCheckResults) MessagesFoundSummary() string {
 	var summary string
 	for index, result := range mcr {
-		summary += fmt.Sprintf("%s(%d)", result.MailboxName, result.ItemsFound)
+		switch {
+		case result.Err != nil:
+			summary += fmt.Sprintf("%s(error)", result.MailboxName)
+		case len(result.Criteria) > 0:
+			summary += fmt.Sprintf(
+				"%s(%d %s)",
+				result.MailboxName,
+				result.ItemsFound,
+				strings.Join(result.Criteria, ","),
+			)
+		default:
+			summary += fmt.Sprintf("%s(%d)", result.MailboxName, result.ItemsFound)
+		}
 		if index < (len(mcr) - 1) {
 			// Append separator chars if not processing the last index item
 			summary += ", "
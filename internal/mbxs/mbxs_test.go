@@ -0,0 +1,131 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMailboxCheckResultMessageAges asserts that message ages are based on
+// the internal date, falling back to the envelope date when the internal
+// date is not available.
+func TestMailboxCheckResultMessageAges(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	result := MailboxCheckResult{
+		MailboxName: "INBOX",
+		ItemsFound:  3,
+		Messages: []Message{
+			{
+				// Envelope date is ignored in favor of the internal date.
+				EnvelopeDate: now.Add(-72 * time.Hour),
+				InternalDate: now.Add(-2 * time.Hour),
+			},
+			{
+				// No internal date; envelope date is used.
+				EnvelopeDate: now.Add(-30 * time.Hour),
+			},
+			{
+				// Neither date is known; skipped.
+			},
+		},
+	}
+	result.setMessageDateRange()
+
+	if want, got := 30*time.Hour, result.OldestMessageAge(now); want != got {
+		t.Errorf("want oldest message age %s, got %s", want, got)
+	}
+
+	if want, got := 2*time.Hour, result.NewestMessageAge(now); want != got {
+		t.Errorf("want newest message age %s, got %s", want, got)
+	}
+
+	results := MailboxCheckResults{result, {MailboxName: "Junk"}}
+
+	if want, got := 30*time.Hour, results.OldestMessageAge(now); want != got {
+		t.Errorf("want overall oldest message age %s, got %s", want, got)
+	}
+
+	if want, got := 2*time.Hour, results.NewestMessageAge(now); want != got {
+		t.Errorf("want overall newest message age %s, got %s", want, got)
+	}
+}
+
+// TestMailboxCheckResultsMessagesSince asserts that results can be limited
+// to messages received within a time window and that the newest message is
+// reported.
+func TestMailboxCheckResultsMessagesSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	results := MailboxCheckResults{
+		{
+			MailboxName: "INBOX",
+			ItemsFound:  2,
+			Messages: []Message{
+				{OriginalSubject: "old", InternalDate: now.Add(-48 * time.Hour)},
+				{OriginalSubject: "heartbeat", InternalDate: now.Add(-time.Hour)},
+			},
+		},
+		{
+			MailboxName: "Alerts",
+			ItemsFound:  1,
+			Messages: []Message{
+				{OriginalSubject: "older heartbeat", InternalDate: now.Add(-3 * time.Hour)},
+			},
+		},
+	}
+
+	recent := results.MessagesSince(now.Add(-24 * time.Hour))
+	if want, got := 2, recent.TotalMessagesFound(); want != got {
+		t.Errorf("want %d messages within window, got %d", want, got)
+	}
+
+	if want, got := 3, results.TotalMessagesFound(); want != got {
+		t.Errorf("original results modified; want %d messages, got %d", want, got)
+	}
+
+	folder, msg, ok := results.NewestMessage()
+	if !ok {
+		t.Fatal("want newest message, got none")
+	}
+
+	if folder != "INBOX" || msg.OriginalSubject != "heartbeat" {
+		t.Errorf("want newest message %q in INBOX, got %q in %s", "heartbeat", msg.OriginalSubject, folder)
+	}
+
+	if _, _, ok := (MailboxCheckResults{{MailboxName: "Junk"}}).NewestMessage(); ok {
+		t.Error("want no newest message for empty results")
+	}
+}
+
+// TestFormatMessageSize asserts that message sizes are formatted using
+// binary units.
+func TestFormatMessageSize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[uint32]string{
+		0:          "0 B",
+		1023:       "1023 B",
+		1024:       "1.0 KiB",
+		15360:      "15.0 KiB",
+		5 << 20:    "5.0 MiB",
+		3 << 30:    "3.0 GiB",
+		4294967295: "4.0 GiB",
+	}
+
+	for size, want := range tests {
+		if got := FormatMessageSize(size); got != want {
+			t.Errorf("FormatMessageSize(%d): want %q, got %q", size, want, got)
+		}
+	}
+}
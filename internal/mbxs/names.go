@@ -0,0 +1,236 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"strings"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/emersion/go-imap/commands"
+	"github.com/emersion/go-imap/responses"
+	"github.com/emersion/go-imap/utf7"
+	"github.com/rs/zerolog"
+)
+
+// Session is an IMAP client connection along with the extensions enabled for
+// the connection which change how commands are sent and responses received.
+//
+// International mailbox names are encoded as modified UTF-7 (RFC 3501
+// section 5.1.3) by the go-imap client library. Once UTF8=ACCEPT is enabled
+// (RFC 6855) mailbox names are instead sent and received as UTF-8; the
+// commands and responses which carry mailbox names are adjusted accordingly
+// for sessions with UTF8Accept set.
+type Session struct {
+	*client.Client
+
+	// UTF8Accept indicates whether UTF-8 mailbox names were enabled for the
+	// connection by EnableUTF8Accept.
+	UTF8Accept bool
+}
+
+// EnableUTF8Accept enables UTF-8 mailbox names (RFC 6855) for the given
+// client connection if advertised by the server. Whether UTF-8 mailbox names
+// were enabled is returned and should be recorded in the Session used for
+// the connection. Mailbox names are encoded as modified UTF-7 if not
+// enabled.
+func EnableUTF8Accept(c *client.Client, logger zerolog.Logger) (bool, error) {
+	for _, capability := range []string{IMAPv4CapabilityEnable, IMAPv4CapabilityUTF8Accept} {
+		supported, err := c.Support(capability)
+		if err != nil {
+			return false, err
+		}
+
+		if !supported {
+			logger.Debug().
+				Str("capability", capability).
+				Msg("Capability not advertised; using modified UTF-7 mailbox names")
+
+			return false, nil
+		}
+	}
+
+	enabled, err := c.Enable([]string{IMAPv4CapabilityUTF8Accept})
+	if err != nil {
+		return false, err
+	}
+
+	for _, capability := range enabled {
+		if strings.EqualFold(capability, IMAPv4CapabilityUTF8Accept) {
+			logger.Debug().Msg("Enabled UTF-8 mailbox names")
+
+			return true, nil
+		}
+	}
+
+	logger.Debug().Msg("Server did not enable UTF-8 mailbox names")
+
+	return false, nil
+}
+
+// NormalizeMailboxName returns the given (user-specified) mailbox name with
+// any modified UTF-7 encoding decoded. This allows international mailbox
+// names to be specified as-is or (as previously required) in their encoded
+// form, e.g., "Entwürfe" or "Entw&APw-rfe".
+func NormalizeMailboxName(name string) string {
+	if !strings.Contains(name, "&") {
+		return name
+	}
+
+	decoded, err := utf7.Encoding.NewDecoder().String(name)
+	if err != nil {
+		return name
+	}
+
+	return decoded
+}
+
+// listMailboxes lists mailboxes matching the given LIST pattern, sending
+// them down the given channel. The channel is closed once done.
+func listMailboxes(c *Session, pattern string, ch chan *imap.MailboxInfo) error {
+	if !c.UTF8Accept {
+		return c.List("", pattern, ch)
+	}
+
+	defer close(ch)
+
+	cmd := utf8Command{
+		Commander:    &commands.List{Mailbox: pattern},
+		mailboxNames: []int{0, 1},
+	}
+	res := utf8Response{
+		Handler:      &responses.List{Mailboxes: ch},
+		name:         "LIST",
+		mailboxField: 2,
+	}
+
+	status, err := c.Execute(cmd, res)
+	if err != nil {
+		return err
+	}
+
+	return status.Err()
+}
+
+// mailboxStatus requests the given status items for the named mailbox.
+func mailboxStatus(c *Session, name string, items []imap.StatusItem) (*imap.MailboxStatus, error) {
+	if !c.UTF8Accept {
+		return c.Status(name, items)
+	}
+
+	mbox := new(imap.MailboxStatus)
+	cmd := utf8Command{
+		Commander:    &commands.Status{Mailbox: name, Items: items},
+		mailboxNames: []int{0},
+	}
+	res := utf8Response{
+		Handler:      &responses.Status{Mailbox: mbox},
+		name:         "STATUS",
+		mailboxField: 0,
+	}
+
+	status, err := c.Execute(cmd, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return mbox, status.Err()
+}
+
+// selectMailbox selects the named mailbox, optionally read-only (EXAMINE).
+func selectMailbox(c *Session, name string, readOnly bool) (*imap.MailboxStatus, error) {
+	if !c.UTF8Accept {
+		return c.Select(name, readOnly)
+	}
+
+	// Mirror the client library's Select method so that mailbox updates
+	// (e.g., EXISTS responses) are tracked and commands requiring a
+	// selected mailbox are permitted.
+	mbox := &imap.MailboxStatus{Name: name, Items: make(map[imap.StatusItem]interface{})}
+	c.SetState(c.State(), mbox)
+
+	cmd := utf8Command{
+		Commander:    &commands.Select{Mailbox: name, ReadOnly: readOnly},
+		mailboxNames: []int{0},
+	}
+
+	status, err := c.Execute(cmd, &responses.Select{Mailbox: mbox})
+	if err == nil {
+		err = status.Err()
+	}
+
+	if err != nil {
+		c.SetState(imap.AuthenticatedState, nil)
+		return nil, err
+	}
+
+	mbox.ReadOnly = status.Code == imap.CodeReadOnly
+	c.SetState(imap.SelectedState, mbox)
+
+	return mbox, nil
+}
+
+// utf8Command sends the mailbox name arguments of a command as UTF-8 instead
+// of the modified UTF-7 used by the client library.
+type utf8Command struct {
+	imap.Commander
+
+	// mailboxNames are the indexes of the command arguments which are
+	// mailbox names.
+	mailboxNames []int
+}
+
+// Command returns the command with mailbox name arguments decoded.
+func (cmd utf8Command) Command() *imap.Command {
+	c := cmd.Commander.Command()
+
+	for _, i := range cmd.mailboxNames {
+		if i >= len(c.Arguments) {
+			continue
+		}
+
+		if name, ok := c.Arguments[i].(string); ok {
+			if decoded, err := utf7.Encoding.NewDecoder().String(name); err == nil {
+				c.Arguments[i] = decoded
+			}
+		}
+	}
+
+	return c
+}
+
+// utf8Response receives UTF-8 mailbox names in responses and encodes them as
+// the modified UTF-7 expected by the client library response handlers.
+type utf8Response struct {
+	responses.Handler
+
+	// name is the name of the response carrying a mailbox name.
+	name string
+
+	// mailboxField is the index of the response field (following the
+	// response name) which is a mailbox name.
+	mailboxField int
+}
+
+// Handle encodes the mailbox name of matching responses before passing the
+// response to the wrapped handler.
+func (h utf8Response) Handle(resp imap.Resp) error {
+	data, ok := resp.(*imap.DataResp)
+	if ok && len(data.Fields) > h.mailboxField+1 {
+		if name, ok := data.Fields[0].(string); ok && strings.EqualFold(name, h.name) {
+			if mailbox, err := imap.ParseString(data.Fields[h.mailboxField+1]); err == nil {
+				encoded, encodeErr := utf7.Encoding.NewEncoder().String(mailbox)
+				if encodeErr == nil {
+					data.Fields[h.mailboxField+1] = encoded
+				}
+			}
+		}
+	}
+
+	return h.Handler.Handle(resp)
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-imap"
+	"github.com/rs/zerolog"
+)
+
+func TestNormalizeMailboxName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"INBOX":            "INBOX",
+		"Entwürfe":         "Entwürfe",
+		"Entw&APw-rfe":     "Entwürfe",
+		"&ZeVnLIqe-":       "日本語",
+		"Projects/A&-B":    "Projects/A&B",
+		"Research & Dev":   "Research & Dev",
+		"Projects/&APw-/*": "Projects/ü/*",
+	}
+
+	for name, want := range tests {
+		if got := NormalizeMailboxName(name); got != want {
+			t.Errorf("NormalizeMailboxName(%q): want %q, got %q", name, want, got)
+		}
+	}
+}
+
+// TestValidateMailboxesListDelimiter asserts that the hierarchy delimiter
+// reported by the server is used when matching INBOX subfolders.
+func TestValidateMailboxesListDelimiter(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) *Session {
+		return newTestClient(t, "", map[string]testCommandHandler{
+			"LIST": func(string) ([]string, error) {
+				return []string{
+					`* LIST (\HasChildren) "." "INBOX"`,
+					`* LIST (\HasNoChildren) "." "INBOX.Reports"`,
+				}, nil
+			},
+		})
+	}
+
+	got, err := ValidateMailboxesList(newClient(t), []string{"Inbox", "inbox.Reports"}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"Inbox", "inbox.Reports"}; !slices.Equal(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	if _, err := ValidateMailboxesList(newClient(t), []string{"inbox/Reports"}, zerolog.Nop()); err == nil {
+		t.Error("want error for folder using the wrong hierarchy delimiter, got nil")
+	}
+}
+
+// TestInternationalMailboxNames asserts that international mailbox names may
+// be specified as-is or modified UTF-7 encoded and are sent to the server
+// encoded.
+func TestInternationalMailboxNames(t *testing.T) {
+	t.Parallel()
+
+	for _, folder := range []string{"Entwürfe", "Entw&APw-rfe"} {
+		c := newTestClient(t, "", map[string]testCommandHandler{
+			"LIST": func(string) ([]string, error) {
+				return []string{`* LIST (\HasNoChildren) "/" "Entw&APw-rfe"`}, nil
+			},
+			"STATUS": func(args string) ([]string, error) {
+				if !strings.HasPrefix(args, `"Entw&APw-rfe"`) {
+					return nil, fmt.Errorf("unexpected mailbox name: %s", args)
+				}
+				return []string{`* STATUS "Entw&APw-rfe" (MESSAGES 2 UNSEEN 1 RECENT 0)`}, nil
+			},
+		})
+
+		validated, err := ValidateMailboxesList(c, []string{folder}, zerolog.Nop())
+		if err != nil {
+			t.Fatalf("unexpected error validating %q: %v", folder, err)
+		}
+
+		results, err := CountMail(c, "user", validated, MessageFilter{}, zerolog.Nop())
+		if err != nil {
+			t.Fatalf("unexpected error counting mail in %q: %v", folder, err)
+		}
+
+		if results[0].MailboxName != "Entwürfe" || results[0].ItemsFound != 2 {
+			t.Errorf(
+				"want 2 messages in %q, got %d messages in %q",
+				"Entwürfe",
+				results[0].ItemsFound,
+				results[0].MailboxName,
+			)
+		}
+	}
+}
+
+// TestEnableUTF8Accept asserts that UTF-8 mailbox names are enabled when
+// advertised by the server and are then sent and received as-is.
+func TestEnableUTF8Accept(t *testing.T) {
+	t.Parallel()
+
+	expectName := func(command string) testCommandHandler {
+		return func(args string) ([]string, error) {
+			if !strings.Contains(args, `"日本語"`) {
+				return nil, fmt.Errorf("unexpected %s mailbox name: %s", command, args)
+			}
+
+			switch command {
+			case "STATUS":
+				return []string{`* STATUS "日本語" (MESSAGES 5 UNSEEN 0 RECENT 0)`}, nil
+			default:
+				return []string{`* 5 EXISTS`, `* 0 RECENT`}, nil
+			}
+		}
+	}
+
+	c := newTestClient(t, "ENABLE UTF8=ACCEPT", map[string]testCommandHandler{
+		"ENABLE": func(string) ([]string, error) {
+			return []string{"* ENABLED UTF8=ACCEPT"}, nil
+		},
+		"LIST": func(string) ([]string, error) {
+			return []string{`* LIST (\HasNoChildren) "/" "日本語"`}, nil
+		},
+		"STATUS":  expectName("STATUS"),
+		"EXAMINE": expectName("EXAMINE"),
+	})
+
+	enabled, err := EnableUTF8Accept(c.Client, zerolog.Nop())
+	if err != nil || !enabled {
+		t.Fatalf("want UTF-8 mailbox names enabled, got %t (error: %v)", enabled, err)
+	}
+	c.UTF8Accept = enabled
+
+	mailboxes, err := ListMailboxes(c, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error listing mailboxes: %v", err)
+	}
+
+	if want := []string{"日本語"}; !slices.Equal(mailboxes, want) {
+		t.Errorf("want mailboxes %q, got %q", want, mailboxes)
+	}
+
+	results, err := CountMail(c, "user", []string{"日本語"}, MessageFilter{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error counting mail: %v", err)
+	}
+
+	if results[0].ItemsFound != 5 {
+		t.Errorf("want 5 messages, got %d", results[0].ItemsFound)
+	}
+
+	mbox, err := selectMailbox(c, "日本語", true)
+	if err != nil {
+		t.Fatalf("unexpected error selecting mailbox: %v", err)
+	}
+
+	if mbox.Messages != 5 || c.State() != imap.SelectedState {
+		t.Errorf("want 5 messages in selected mailbox, got %d (state %v)", mbox.Messages, c.State())
+	}
+}
+
+// TestEnableUTF8AcceptUnsupported asserts that UTF-8 mailbox names are not
+// requested unless advertised by the server.
+func TestEnableUTF8AcceptUnsupported(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "ENABLE", nil)
+
+	enabled, err := EnableUTF8Accept(c.Client, zerolog.Nop())
+	if err != nil || enabled {
+		t.Errorf("want UTF-8 mailbox names not enabled, got %t (error: %v)", enabled, err)
+	}
+}
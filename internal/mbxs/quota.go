@@ -0,0 +1,242 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/responses"
+	"github.com/emersion/go-imap/utf7"
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrQuotaUnsupported indicates that the server does not advertise
+	// support for the QUOTA extension.
+	ErrQuotaUnsupported = errors.New("quota extension not supported by server")
+)
+
+// Quota resource names. The STORAGE resource is measured in units of 1024
+// octets (KiB); the MESSAGE resource is a count of messages.
+//
+// https://datatracker.ietf.org/doc/html/rfc9208#section-5
+const (
+	QuotaResourceStorage string = "STORAGE"
+	QuotaResourceMessage string = "MESSAGE"
+)
+
+// QuotaResource is the usage and limit of a single resource (e.g., STORAGE)
+// within a quota root.
+type QuotaResource struct {
+	// Name is the name of the resource (e.g., STORAGE or MESSAGE).
+	Name string
+
+	// Usage is the current usage of the resource.
+	Usage uint64
+
+	// Limit is the maximum permitted usage of the resource.
+	Limit uint64
+}
+
+// PercentUsed returns the usage of the resource as a percentage of the
+// limit. Zero is returned if no limit is set.
+func (qr QuotaResource) PercentUsed() float64 {
+	if qr.Limit == 0 {
+		return 0
+	}
+
+	return float64(qr.Usage) / float64(qr.Limit) * 100
+}
+
+// Quota is the collection of resource limits for a quota root. A quota root
+// may apply to one or more mailboxes.
+type Quota struct {
+	// Root is the name of the quota root. This is often an empty string.
+	Root string
+
+	// Resources is the collection of resources limited by the quota root.
+	Resources []QuotaResource
+}
+
+// GetQuotaRoot retrieves the quota roots and resource usage and limits for
+// the named mailbox using the GETQUOTAROOT command. ErrQuotaUnsupported is
+// returned if the QUOTA capability is not advertised by the server.
+//
+// https://datatracker.ietf.org/doc/html/rfc9208#section-4.3
+func GetQuotaRoot(c *Session, mailbox string, logger zerolog.Logger) ([]Quota, error) {
+
+	supported, err := c.Support(IMAPv4CapabilityQuota)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to list server capabilities: %w: %w",
+			commandErrorKind(err, ErrProtocol),
+			err,
+		)
+	}
+
+	if !supported {
+		logger.Debug().
+			Str("capability", IMAPv4CapabilityQuota).
+			Msg("Capability not advertised")
+
+		return nil, ErrQuotaUnsupported
+	}
+
+	var cmd imap.Commander = getQuotaRootCommand{mailbox: mailbox}
+	if c.UTF8Accept {
+		cmd = utf8Command{Commander: cmd, mailboxNames: []int{0}}
+	}
+
+	res := &quotaRootResponse{}
+
+	logger.Debug().Str("mailbox", mailbox).Msg("Running GETQUOTAROOT command")
+	status, err := c.Execute(cmd, res)
+	if err == nil {
+		err = status.Err()
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Str("mailbox", mailbox).Msg("Error occurred retrieving quota")
+
+		return nil, fmt.Errorf(
+			"failed to retrieve quota for mailbox %q: %w: %w",
+			mailbox,
+			commandErrorKind(err, ErrProtocol),
+			err,
+		)
+	}
+
+	logger.Debug().
+		Strs("quota_roots", res.roots).
+		Int("quotas", len(res.quotas)).
+		Msg("Retrieved quota")
+
+	return res.quotas, nil
+}
+
+// getQuotaRootCommand is a GETQUOTAROOT command.
+type getQuotaRootCommand struct {
+	mailbox string
+}
+
+// Command returns the GETQUOTAROOT command for the mailbox.
+func (cmd getQuotaRootCommand) Command() *imap.Command {
+	mailbox, _ := utf7.Encoding.NewEncoder().String(cmd.mailbox)
+
+	return &imap.Command{
+		Name:      "GETQUOTAROOT",
+		Arguments: []interface{}{imap.FormatMailboxName(mailbox)},
+	}
+}
+
+// quotaRootResponse handles the QUOTAROOT and QUOTA responses to a
+// GETQUOTAROOT command.
+type quotaRootResponse struct {
+	roots  []string
+	quotas []Quota
+}
+
+// Handle parses QUOTAROOT and QUOTA responses.
+func (r *quotaRootResponse) Handle(resp imap.Resp) error {
+	name, fields, ok := imap.ParseNamedResp(resp)
+	if !ok {
+		return responses.ErrUnhandled
+	}
+
+	switch name {
+	case "QUOTAROOT":
+		// The first field is the mailbox name, followed by zero or more
+		// quota root names.
+		for i := 1; i < len(fields); i++ {
+			root, err := imap.ParseString(fields[i])
+			if err != nil {
+				return fmt.Errorf("invalid QUOTAROOT response: %w", err)
+			}
+			r.roots = append(r.roots, root)
+		}
+
+	case "QUOTA":
+		quota, err := parseQuota(fields)
+		if err != nil {
+			return fmt.Errorf("invalid QUOTA response: %w", err)
+		}
+		r.quotas = append(r.quotas, quota)
+
+	default:
+		return responses.ErrUnhandled
+	}
+
+	return nil
+}
+
+// parseQuota parses the fields of a QUOTA response; the quota root name
+// followed by a list of resource name, usage and limit triples.
+func parseQuota(fields []interface{}) (Quota, error) {
+	if len(fields) < 2 {
+		return Quota{}, errors.New("not enough fields")
+	}
+
+	root, err := imap.ParseString(fields[0])
+	if err != nil {
+		return Quota{}, err
+	}
+
+	list, ok := fields[1].([]interface{})
+	if !ok || len(list)%3 != 0 {
+		return Quota{}, errors.New("resource list must contain name, usage and limit triples")
+	}
+
+	quota := Quota{
+		Root:      root,
+		Resources: make([]QuotaResource, 0, len(list)/3),
+	}
+
+	for i := 0; i < len(list); i += 3 {
+		name, err := imap.ParseString(list[i])
+		if err != nil {
+			return Quota{}, err
+		}
+
+		usage, err := parseQuotaNumber(list[i+1])
+		if err != nil {
+			return Quota{}, err
+		}
+
+		limit, err := parseQuotaNumber(list[i+2])
+		if err != nil {
+			return Quota{}, err
+		}
+
+		quota.Resources = append(quota.Resources, QuotaResource{
+			Name:  name,
+			Usage: usage,
+			Limit: limit,
+		})
+	}
+
+	return quota, nil
+}
+
+// parseQuotaNumber parses a quota usage or limit value. Values may exceed
+// the 32-bit numbers supported by the client library (RFC 9208 permits
+// 63-bit values).
+func parseQuotaNumber(field interface{}) (uint64, error) {
+	if n, ok := field.(uint32); ok {
+		return uint64(n), nil
+	}
+
+	s, err := imap.ParseString(field)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(s, 10, 64)
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestGetQuotaRoot(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "QUOTA", map[string]testCommandHandler{
+		"GETQUOTAROOT": func(args string) ([]string, error) {
+			if args != "INBOX" {
+				return nil, errors.New("unexpected mailbox " + args)
+			}
+
+			return []string{
+				`* QUOTAROOT INBOX "User quota"`,
+				`* QUOTA "User quota" (STORAGE 838860 1048576 MESSAGE 1200 10000)`,
+				`* QUOTA "Archive" (STORAGE 5000000000 8000000000)`,
+			}, nil
+		},
+	})
+
+	got, err := GetQuotaRoot(c, "INBOX", zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Quota{
+		{
+			Root: "User quota",
+			Resources: []QuotaResource{
+				{Name: QuotaResourceStorage, Usage: 838860, Limit: 1048576},
+				{Name: QuotaResourceMessage, Usage: 1200, Limit: 10000},
+			},
+		},
+		{
+			Root: "Archive",
+			Resources: []QuotaResource{
+				{Name: QuotaResourceStorage, Usage: 5000000000, Limit: 8000000000},
+			},
+		},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	if pct := got[0].Resources[1].PercentUsed(); pct != 12 {
+		t.Errorf("want 12 percent used, got %v", pct)
+	}
+}
+
+func TestGetQuotaRootUnsupported(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "", nil)
+
+	if _, err := GetQuotaRoot(c, "INBOX", zerolog.Nop()); !errors.Is(err, ErrQuotaUnsupported) {
+		t.Errorf("want %v, got %v", ErrQuotaUnsupported, err)
+	}
+}
+
+func TestGetQuotaRootError(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, "QUOTA", map[string]testCommandHandler{
+		"GETQUOTAROOT": func(string) ([]string, error) {
+			return nil, errors.New("no such mailbox")
+		},
+	})
+
+	if _, err := GetQuotaRoot(c, "INBOX", zerolog.Nop()); err == nil {
+		t.Error("want error, got nil")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/emersion/go-imap/client"
+	"github.com/rs/zerolog"
+)
+
+// transientResponses is the collection of (lowercase) text fragments found
+// in server responses or client errors which indicate a temporary condition
+// (e.g., throttling or a dropped connection) worth retrying.
+var transientResponses = []string{
+	// go-imap reports a BYE response or dropped connection during a command
+	// using this text.
+	"connection closed",
+
+	// Office 365 reports this after a successful login when the mailbox
+	// backend is not (yet) available.
+	"not connected",
+
+	// Office 365 "Server Unavailable. 15" and similar responses.
+	"server unavailable",
+
+	// RFC 5530 response code; e.g., NO [UNAVAILABLE].
+	"[unavailable]",
+
+	// Throttling responses (e.g., "Request is throttled").
+	"throttl",
+
+	"temporarily unavailable",
+	"try again",
+}
+
+// RetryPolicy controls how often and how quickly operations failing with a
+// transient error are retried.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	// A zero value disables retries.
+	MaxRetries int
+
+	// Delay is the time waited before the first retry. The delay is doubled
+	// for each subsequent retry.
+	Delay time.Duration
+}
+
+// IsTransient indicates whether the given error is likely due to a
+// temporary condition (e.g., a dropped connection, timeout, throttling or an
+// unavailable mailbox backend) which may not recur if the operation is
+// retried.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if isTimeout(err) ||
+		isConnectionError(err) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, client.ErrAlreadyLoggedOut) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range transientResponses {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Retry calls the given function until it succeeds or fails with an error
+// which is not transient. Retries are stopped once the retry policy limit is
+// reached or if waiting for the next attempt would exceed the deadline of
+// the given context. The number of retries performed and the error from the
+// last attempt are returned.
+func Retry(ctx context.Context, policy RetryPolicy, logger zerolog.Logger, fn func() error) (int, error) {
+	delay := policy.Delay
+
+	var retries int
+	for {
+		err := fn()
+
+		switch {
+		case err == nil:
+			return retries, nil
+
+		case !IsTransient(err):
+			return retries, err
+
+		case retries >= policy.MaxRetries:
+			if policy.MaxRetries > 0 {
+				logger.Warn().
+					Err(err).
+					Int("retries", retries).
+					Msg("Retry limit reached")
+			}
+
+			return retries, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			logger.Warn().
+				Err(err).
+				Int("retries", retries).
+				Msg("Insufficient time remaining to retry")
+
+			return retries, err
+		}
+
+		logger.Warn().
+			Err(err).
+			Int("retry", retries+1).
+			Dur("delay", delay).
+			Msg("Transient error occurred; retrying")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retries, err
+		case <-timer.C:
+		}
+
+		retries++
+		delay *= 2
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap/client"
+	"github.com/rs/zerolog"
+)
+
+// TestIsTransient asserts that temporary failures are distinguished from
+// permanent ones.
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"nil": {
+			err:  nil,
+			want: false,
+		},
+		"connection closed by BYE": {
+			err:  fmt.Errorf("failed to login: %w", errors.New("imap: connection closed")),
+			want: true,
+		},
+		"connection reset": {
+			err:  fmt.Errorf("read: %w", syscall.ECONNRESET),
+			want: true,
+		},
+		"unexpected EOF": {
+			err:  io.ErrUnexpectedEOF,
+			want: true,
+		},
+		"already logged out": {
+			err:  client.ErrAlreadyLoggedOut,
+			want: true,
+		},
+		"timeout": {
+			err:  context.DeadlineExceeded,
+			want: true,
+		},
+		"office 365 not connected": {
+			err:  errors.New("User is authenticated but not connected."),
+			want: true,
+		},
+		"throttled": {
+			err:  errors.New("Request is throttled. Suggested Backoff Time: 2000 milliseconds"),
+			want: true,
+		},
+		"unavailable response code": {
+			err:  errors.New("[UNAVAILABLE] Server Unavailable. 15"),
+			want: true,
+		},
+		"authentication failed": {
+			err:  fmt.Errorf("login failed: %w: %w", ErrAuthRejected, errors.New("AUTHENTICATE failed.")),
+			want: false,
+		},
+		"mailbox not found": {
+			err:  ErrMailboxNotFound,
+			want: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v): want %t, got %t", tt.err, tt.want, got)
+			}
+		})
+	}
+}
+
+// TestRetry asserts that only transient errors are retried and that the
+// retry policy limits are honored.
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	transientErr := errors.New("imap: connection closed")
+	permanentErr := errors.New("AUTHENTICATE failed.")
+
+	tests := map[string]struct {
+		policy      RetryPolicy
+		errs        []error
+		timeout     time.Duration
+		wantCalls   int
+		wantRetries int
+		wantErr     error
+	}{
+		"success on first attempt": {
+			policy:      RetryPolicy{MaxRetries: 2},
+			errs:        []error{nil},
+			wantCalls:   1,
+			wantRetries: 0,
+		},
+		"success after transient errors": {
+			policy:      RetryPolicy{MaxRetries: 2},
+			errs:        []error{transientErr, transientErr, nil},
+			wantCalls:   3,
+			wantRetries: 2,
+		},
+		"permanent error not retried": {
+			policy:      RetryPolicy{MaxRetries: 2},
+			errs:        []error{permanentErr, nil},
+			wantCalls:   1,
+			wantRetries: 0,
+			wantErr:     permanentErr,
+		},
+		"permanent error after transient error": {
+			policy:      RetryPolicy{MaxRetries: 2},
+			errs:        []error{transientErr, permanentErr, nil},
+			wantCalls:   2,
+			wantRetries: 1,
+			wantErr:     permanentErr,
+		},
+		"retry limit reached": {
+			policy:      RetryPolicy{MaxRetries: 2},
+			errs:        []error{transientErr, transientErr, transientErr, nil},
+			wantCalls:   3,
+			wantRetries: 2,
+			wantErr:     transientErr,
+		},
+		"retries disabled": {
+			policy:      RetryPolicy{MaxRetries: 0},
+			errs:        []error{transientErr, nil},
+			wantCalls:   1,
+			wantRetries: 0,
+			wantErr:     transientErr,
+		},
+		"insufficient time remaining": {
+			policy:      RetryPolicy{MaxRetries: 2, Delay: time.Minute},
+			errs:        []error{transientErr, nil},
+			timeout:     time.Second,
+			wantCalls:   1,
+			wantRetries: 0,
+			wantErr:     transientErr,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			var calls int
+			retries, err := Retry(ctx, tt.policy, zerolog.Nop(), func() error {
+				err := tt.errs[calls]
+				calls++
+
+				return err
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, got %v", tt.wantErr, err)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("want %d calls, got %d", tt.wantCalls, calls)
+			}
+
+			if retries != tt.wantRetries {
+				t.Errorf("want %d retries, got %d", tt.wantRetries, retries)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package mbxs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-imap/client"
+)
+
+// testCommandHandler returns the untagged response lines for an IMAP command
+// sent by the client or an error to be returned as a tagged NO response.
+type testCommandHandler func(args string) ([]string, error)
+
+// newTestClient returns an IMAP client connected to a scripted IMAP server.
+// Commands are dispatched to the handler registered for the (uppercase)
+// command name; commands without a handler receive a BAD response.
+func newTestClient(t *testing.T, capabilities string, handlers map[string]testCommandHandler) *Session {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+
+	go serveTestConn(serverConn, capabilities, handlers)
+
+	c, err := client.New(clientConn)
+	if err != nil {
+		t.Fatalf("failed to create IMAP client: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = c.Logout()
+	})
+
+	return &Session{Client: c}
+}
+
+// serveTestConn handles IMAP commands sent over the given connection until
+// the client logs out or the connection is closed.
+func serveTestConn(conn net.Conn, capabilities string, handlers map[string]testCommandHandler) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	w := bufio.NewWriter(conn)
+	r := bufio.NewReader(conn)
+
+	writeLine := func(line string) {
+		_, _ = io.WriteString(w, line+"\r\n")
+	}
+
+	// Clients start out authenticated to avoid scripting a login.
+	writeLine(fmt.Sprintf("* PREAUTH [CAPABILITY IMAP4rev1 %s] test server ready", capabilities))
+	_ = w.Flush()
+
+	for {
+		line, err := readTestCommand(r, w)
+		if err != nil {
+			return
+		}
+
+		fields := strings.SplitN(strings.TrimRight(line, "\r\n"), " ", 3)
+		if len(fields) < 2 {
+			continue
+		}
+
+		tag, command := fields[0], strings.ToUpper(fields[1])
+		var args string
+		if len(fields) > 2 {
+			args = fields[2]
+		}
+
+		switch handler, ok := handlers[command]; {
+		case command == "LOGOUT":
+			writeLine("* BYE logging out")
+			writeLine(tag + " OK LOGOUT completed")
+			_ = w.Flush()
+			return
+
+		case !ok:
+			writeLine(tag + " BAD unexpected command " + command)
+
+		default:
+			untagged, err := handler(args)
+			for _, resp := range untagged {
+				writeLine(resp)
+			}
+
+			if err != nil {
+				writeLine(tag + " NO " + err.Error())
+			} else {
+				writeLine(tag + " OK " + command + " completed")
+			}
+		}
+
+		_ = w.Flush()
+	}
+}
+
+// readTestCommand reads a command line sent by the client, inlining any
+// string literals (e.g., non-ASCII mailbox names) it contains.
+func readTestCommand(r *bufio.Reader, w *bufio.Writer) (string, error) {
+	var command strings.Builder
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		trimmed := strings.TrimRight(line, "\r\n")
+		start := strings.LastIndex(trimmed, "{")
+		if start == -1 || !strings.HasSuffix(trimmed, "}") {
+			command.WriteString(line)
+			return command.String(), nil
+		}
+
+		size, sync := strings.TrimSuffix(trimmed[start+1:len(trimmed)-1], "+"), !strings.HasSuffix(trimmed, "+}")
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			command.WriteString(line)
+			return command.String(), nil
+		}
+
+		if sync {
+			_, _ = io.WriteString(w, "+ Ready for literal data\r\n")
+			_ = w.Flush()
+		}
+
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(r, literal); err != nil {
+			return "", err
+		}
+
+		command.WriteString(trimmed[:start])
+		command.WriteString(strconv.Quote(string(literal)))
+	}
+}
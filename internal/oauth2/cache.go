@@ -0,0 +1,183 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package oauth2
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedTokenHeaderV1 is the header line written at the start of token
+// cache files encrypted using AES-256-GCM. The header is authenticated as
+// additional data so that it cannot be altered without detection. The
+// version field permits changing the format later while still reading files
+// written by earlier releases.
+const encryptedTokenHeaderV1 string = "check-mail-token v1 aes-256-gcm\n"
+
+// encryptedTokenHeaderPrefix is the portion of the header common to all
+// versions of the encrypted token cache file format.
+const encryptedTokenHeaderPrefix string = "check-mail-token "
+
+// tokenEncryptionKeySize is the required size in bytes of the (decoded) key
+// used to encrypt token cache files.
+const tokenEncryptionKeySize int = 32
+
+var (
+	// ErrInvalidTokenEncryptionKey indicates that a token encryption key is
+	// not valid.
+	ErrInvalidTokenEncryptionKey = errors.New("invalid token encryption key")
+
+	// ErrTokenDecryptionFailed indicates that encrypted token data could not
+	// be decrypted. This occurs if the wrong key is used or if the data has
+	// been modified.
+	ErrTokenDecryptionFailed = errors.New("failed to decrypt token data")
+
+	// ErrUnsupportedTokenCacheVersion indicates that an encrypted token
+	// cache file uses an unknown format version.
+	ErrUnsupportedTokenCacheVersion = errors.New("unsupported token cache format version")
+)
+
+// ParseTokenEncryptionKey decodes the given base64 encoded key (e.g., as
+// generated by `openssl rand -base64 32`) and returns the result or an
+// error if the key is not valid.
+func ParseTokenEncryptionKey(encodedKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode base64 encoded key: %v: %w",
+			err,
+			ErrInvalidTokenEncryptionKey,
+		)
+	}
+
+	if len(key) != tokenEncryptionKeySize {
+		return nil, fmt.Errorf(
+			"decoded key is %d bytes (want %d): %w",
+			len(key),
+			tokenEncryptionKeySize,
+			ErrInvalidTokenEncryptionKey,
+		)
+	}
+
+	return key, nil
+}
+
+// IsEncryptedTokenData indicates whether the given token cache file content
+// was written by EncryptTokenData.
+func IsEncryptedTokenData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedTokenHeaderPrefix))
+}
+
+// EncryptTokenData encrypts the given token data (JSON payload or access
+// token) using AES-256-GCM and the given key. The result is a versioned
+// header line followed by the base64 encoded nonce and ciphertext.
+func EncryptTokenData(key []byte, plaintext []byte) ([]byte, error) {
+	aead, err := newTokenAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(encryptedTokenHeaderV1))
+
+	var buf bytes.Buffer
+	buf.WriteString(encryptedTokenHeaderV1)
+	buf.WriteString(base64.StdEncoding.EncodeToString(sealed))
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// DecryptTokenData decrypts token data previously encrypted by
+// EncryptTokenData using the given key and returns the original token data
+// or an error if one occurs.
+func DecryptTokenData(key []byte, data []byte) ([]byte, error) {
+	header, payload, found := bytes.Cut(data, []byte("\n"))
+	if !found || !IsEncryptedTokenData(data) {
+		return nil, fmt.Errorf(
+			"missing encrypted token header: %w",
+			ErrTokenDecryptionFailed,
+		)
+	}
+
+	headerLine := string(header) + "\n"
+	if headerLine != encryptedTokenHeaderV1 {
+		return nil, fmt.Errorf(
+			"header %q: %w",
+			strings.TrimSpace(headerLine),
+			ErrUnsupportedTokenCacheVersion,
+		)
+	}
+
+	aead, err := newTokenAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(payload)))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode encrypted payload: %v: %w",
+			err,
+			ErrTokenDecryptionFailed,
+		)
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf(
+			"encrypted payload is truncated: %w",
+			ErrTokenDecryptionFailed,
+		)
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(headerLine))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"wrong key or modified file: %w",
+			ErrTokenDecryptionFailed,
+		)
+	}
+
+	return plaintext, nil
+}
+
+// newTokenAEAD returns an AES-256-GCM cipher using the given key.
+func newTokenAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != tokenEncryptionKeySize {
+		return nil, fmt.Errorf(
+			"key is %d bytes (want %d): %w",
+			len(key),
+			tokenEncryptionKeySize,
+			ErrInvalidTokenEncryptionKey,
+		)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidTokenEncryptionKey)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidTokenEncryptionKey)
+	}
+
+	return aead, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package oauth2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newTestTokenEncryptionKey(t *testing.T) []byte {
+	t.Helper()
+
+	key := make([]byte, tokenEncryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return key
+}
+
+// TestTokenDataEncryptionRoundTrip asserts that encrypted token data carries
+// the versioned header and decrypts to the original content.
+func TestTokenDataEncryptionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	key := newTestTokenEncryptionKey(t)
+	plaintext := []byte(`{"access_token": "secret-token", "token_type": "Bearer"}`)
+
+	encrypted, err := EncryptTokenData(key, plaintext)
+	if err != nil {
+		t.Fatalf("failed to encrypt token data: %v", err)
+	}
+
+	if !IsEncryptedTokenData(encrypted) {
+		t.Fatal("encrypted token data is missing header")
+	}
+
+	if IsEncryptedTokenData(plaintext) {
+		t.Fatal("plaintext token data reported as encrypted")
+	}
+
+	if bytes.Contains(encrypted, []byte("secret-token")) {
+		t.Fatal("encrypted token data contains plaintext access token")
+	}
+
+	decrypted, err := DecryptTokenData(key, encrypted)
+	if err != nil {
+		t.Fatalf("failed to decrypt token data: %v", err)
+	}
+
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Errorf("want %q, got %q", plaintext, decrypted)
+	}
+}
+
+// TestDecryptTokenDataFailures asserts that the wrong key, modified content
+// and unknown format versions are rejected.
+func TestDecryptTokenDataFailures(t *testing.T) {
+	t.Parallel()
+
+	key := newTestTokenEncryptionKey(t)
+
+	encrypted, err := EncryptTokenData(key, []byte("access-token"))
+	if err != nil {
+		t.Fatalf("failed to encrypt token data: %v", err)
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecryptTokenData(newTestTokenEncryptionKey(t), encrypted)
+		if !errors.Is(err, ErrTokenDecryptionFailed) {
+			t.Errorf("want %v, got %v", ErrTokenDecryptionFailed, err)
+		}
+	})
+
+	t.Run("modified payload", func(t *testing.T) {
+		t.Parallel()
+
+		modified := bytes.Clone(encrypted)
+		idx := len(encryptedTokenHeaderV1) + 20
+		if modified[idx] == 'A' {
+			modified[idx] = 'B'
+		} else {
+			modified[idx] = 'A'
+		}
+
+		_, err := DecryptTokenData(key, modified)
+		if !errors.Is(err, ErrTokenDecryptionFailed) {
+			t.Errorf("want %v, got %v", ErrTokenDecryptionFailed, err)
+		}
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		t.Parallel()
+
+		data := append([]byte("check-mail-token v9 aes-256-gcm\n"), encrypted[len(encryptedTokenHeaderV1):]...)
+
+		_, err := DecryptTokenData(key, data)
+		if !errors.Is(err, ErrUnsupportedTokenCacheVersion) {
+			t.Errorf("want %v, got %v", ErrUnsupportedTokenCacheVersion, err)
+		}
+	})
+}
+
+// TestParseTokenEncryptionKey asserts that only base64 encoded keys of the
+// expected size are accepted.
+func TestParseTokenEncryptionKey(t *testing.T) {
+	t.Parallel()
+
+	valid := base64.StdEncoding.EncodeToString(newTestTokenEncryptionKey(t))
+	if _, err := ParseTokenEncryptionKey(valid + "\n"); err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+
+	invalid := []string{
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte("too short")),
+	}
+	for _, input := range invalid {
+		if _, err := ParseTokenEncryptionKey(input); !errors.Is(err, ErrInvalidTokenEncryptionKey) {
+			t.Errorf("input %q: want %v, got %v", input, ErrInvalidTokenEncryptionKey, err)
+		}
+	}
+}
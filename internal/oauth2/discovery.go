@@ -0,0 +1,149 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// oidcDiscoveryPath is the well-known URI suffix appended to an issuer URL in
+// order to retrieve the OpenID Provider Configuration document.
+//
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+const oidcDiscoveryPath string = "/.well-known/openid-configuration"
+
+// oidcDiscoveryMaxBytes is the maximum number of bytes read from an OpenID
+// Provider Configuration response. Real-world documents are a few KB in
+// size; anything substantially larger is treated as suspect.
+const oidcDiscoveryMaxBytes int64 = 1 << 20
+
+var (
+	// ErrOIDCDiscoveryFailed indicates that an attempt to retrieve or parse
+	// an OpenID Provider Configuration document failed.
+	ErrOIDCDiscoveryFailed = errors.New("OpenID Connect discovery failed")
+)
+
+// ProviderEndpoints is the subset of OpenID Provider Metadata values used by
+// applications in this project.
+//
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type ProviderEndpoints struct {
+	// Issuer is the URL that the OpenID Provider asserts as its Issuer
+	// Identifier.
+	Issuer string `json:"issuer"`
+
+	// TokenURL is the URL of the OAuth 2.0 Token Endpoint.
+	TokenURL string `json:"token_endpoint"`
+
+	// AuthURL is the URL of the OAuth 2.0 Authorization Endpoint.
+	AuthURL string `json:"authorization_endpoint"`
+
+	// ScopesSupported is the optional list of scope values that the provider
+	// advertises support for.
+	ScopesSupported []string `json:"scopes_supported"`
+}
+
+// DiscoverProviderEndpoints retrieves the OpenID Provider Configuration
+// document for the given issuer URL and returns the advertised endpoints or
+// an error if one occurs.
+func DiscoverProviderEndpoints(ctx context.Context, issuerURL string) (ProviderEndpoints, error) {
+
+	issuerURL = strings.TrimSpace(issuerURL)
+	if issuerURL == "" {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"empty issuer URL: %w",
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + oidcDiscoveryPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"failed to prepare request for %s: %v: %w",
+			discoveryURL,
+			err,
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"failed to retrieve %s: %v: %w",
+			discoveryURL,
+			err,
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"unexpected response status %q from %s: %w",
+			resp.Status,
+			discoveryURL,
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, oidcDiscoveryMaxBytes))
+	if err != nil {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"failed to read response from %s: %v: %w",
+			discoveryURL,
+			err,
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+
+	var endpoints ProviderEndpoints
+	if err := json.Unmarshal(body, &endpoints); err != nil {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"failed to parse response from %s: %v: %w",
+			discoveryURL,
+			err,
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+
+	// The issuer value returned MUST be identical to the Issuer URL that was
+	// used as the prefix to /.well-known/openid-configuration to retrieve the
+	// configuration information.
+	//
+	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfigurationValidation
+	if strings.TrimSuffix(endpoints.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"issuer %q advertised by %s does not match requested issuer %q: %w",
+			endpoints.Issuer,
+			discoveryURL,
+			issuerURL,
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+
+	if endpoints.TokenURL == "" {
+		return ProviderEndpoints{}, fmt.Errorf(
+			"token endpoint not advertised by %s: %w",
+			discoveryURL,
+			ErrOIDCDiscoveryFailed,
+		)
+	}
+
+	return endpoints, nil
+}
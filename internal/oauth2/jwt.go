@@ -0,0 +1,203 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+// serviceAccountKeyType is the type value found in service account key files
+// issued by Google Cloud.
+const serviceAccountKeyType string = "service_account"
+
+// jwtRetryDelay is the delay between attempts to retrieve a token using the
+// JWT bearer grant.
+const jwtRetryDelay time.Duration = time.Second
+
+var (
+	// ErrInvalidServiceAccountKey indicates that a service account key file
+	// could not be parsed or is missing required values.
+	ErrInvalidServiceAccountKey = errors.New("invalid service account key")
+
+	// ErrInvalidToken indicates that a token was retrieved but is not valid
+	// (e.g., is empty or already expired).
+	ErrInvalidToken = errors.New("retrieved token is not valid")
+)
+
+// ServiceAccountKey is the subset of fields from a Google Cloud service
+// account key (JSON) file needed to request a token using the JWT bearer
+// grant.
+//
+// https://cloud.google.com/iam/docs/keys-create-delete
+type ServiceAccountKey struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	ClientID     string `json:"client_id"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURL     string `json:"token_uri"`
+}
+
+// ParseServiceAccountKey parses the given service account key JSON payload
+// and returns the result or an error if one occurs.
+func ParseServiceAccountKey(data []byte) (ServiceAccountKey, error) {
+	var key ServiceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return ServiceAccountKey{}, fmt.Errorf(
+			"failed to parse service account key: %v: %w",
+			err,
+			ErrInvalidServiceAccountKey,
+		)
+	}
+
+	switch {
+	case key.Type != serviceAccountKeyType:
+		return ServiceAccountKey{}, fmt.Errorf(
+			"unexpected key type %q (want %q): %w",
+			key.Type,
+			serviceAccountKeyType,
+			ErrInvalidServiceAccountKey,
+		)
+
+	case key.ClientEmail == "":
+		return ServiceAccountKey{}, fmt.Errorf(
+			"client_email field is empty: %w",
+			ErrInvalidServiceAccountKey,
+		)
+
+	case key.PrivateKey == "":
+		return ServiceAccountKey{}, fmt.Errorf(
+			"private_key field is empty: %w",
+			ErrInvalidServiceAccountKey,
+		)
+	}
+
+	return key, nil
+}
+
+// ReadServiceAccountKeyFile reads and parses the specified service account
+// key file and returns the result or an error if one occurs.
+func ReadServiceAccountKeyFile(filename string) (ServiceAccountKey, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return ServiceAccountKey{}, fmt.Errorf(
+			"failed to read service account key file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	key, err := ParseServiceAccountKey(data)
+	if err != nil {
+		return ServiceAccountKey{}, fmt.Errorf(
+			"failed to load service account key file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return key, nil
+}
+
+// GetJWTBearerToken receives a service account key, the subject (user) to
+// impersonate via domain-wide delegation and the requested scopes. A signed
+// JWT assertion is exchanged with the authorization server for a new token
+// which is returned or an error if one occurs.
+//
+// If tokenEndpointURL is empty the token URL recorded in the service account
+// key is used.
+//
+// Failed attempts are retried (up to maxAttempts) after a brief delay unless
+// the given context is done first. The error from the last attempt is
+// returned if no valid token is retrieved.
+//
+// https://datatracker.ietf.org/doc/html/rfc7523#section-2.1
+// https://developers.google.com/identity/protocols/oauth2/service-account#delegatingauthority
+func GetJWTBearerToken(
+	ctx context.Context,
+	key ServiceAccountKey,
+	subject string,
+	scopes []string,
+	tokenEndpointURL string,
+	maxAttempts int,
+) (*oauth2.Token, error) {
+
+	if tokenEndpointURL == "" {
+		tokenEndpointURL = key.TokenURL
+	}
+
+	if tokenEndpointURL == "" {
+		return nil, fmt.Errorf(
+			"token URL not provided and not present in service account key: %w",
+			ErrInvalidServiceAccountKey,
+		)
+	}
+
+	jwtConfig := jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Subject:      subject,
+		Scopes:       scopes,
+		TokenURL:     tokenEndpointURL,
+	}
+
+	var lastErr error
+
+	// Attempt to retrieve token, retry up to maximum before giving up. At
+	// least one attempt is made.
+	attempts := max(maxAttempts, 1)
+	for attempt := 1; attempt <= attempts; attempt++ {
+		token, err := jwtConfig.TokenSource(ctx).Token()
+
+		switch {
+		case err != nil:
+			lastErr = err
+
+		// Token validity failed (for reasons unknown).
+		case !token.Valid():
+			lastErr = ErrInvalidToken
+
+		default:
+			// Successful retrieval, return token.
+			return token, nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		// Wait briefly before trying again unless time has run out.
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf(
+				"failed to retrieve token after %d attempts: %w (retries stopped: %w)",
+				attempt,
+				lastErr,
+				ctx.Err(),
+			)
+		case <-time.After(jwtRetryDelay):
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"failed to retrieve token after %d attempts: %w",
+		attempts,
+		lastErr,
+	)
+
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Adam Chalkley
+//
+// https://github.com/atc0005/check-mail
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseServiceAccountKeyRejectsInvalidKeys asserts that service account
+// key payloads missing required values are rejected.
+func TestParseServiceAccountKeyRejectsInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"invalid JSON":      `{`,
+		"wrong type":        `{"type": "authorized_user", "client_email": "a@b", "private_key": "x"}`,
+		"empty email":       `{"type": "service_account", "private_key": "x"}`,
+		"empty private key": `{"type": "service_account", "client_email": "a@b"}`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseServiceAccountKey([]byte(input))
+			if !errors.Is(err, ErrInvalidServiceAccountKey) {
+				t.Errorf("want %v, got %v", ErrInvalidServiceAccountKey, err)
+			}
+		})
+	}
+}
+
+// TestGetJWTBearerTokenImpersonatesSubject asserts that a signed assertion
+// carrying the requested subject is exchanged for a token using the JWT
+// bearer grant.
+func TestGetJWTBearerTokenImpersonatesSubject(t *testing.T) {
+	t.Parallel()
+
+	const subject = "intake@example.com"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if got := r.PostForm.Get("grant_type"); got != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			http.Error(w, "unexpected grant type "+got, http.StatusBadRequest)
+			return
+		}
+
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		if len(parts) != 3 {
+			http.Error(w, "malformed assertion", http.StatusBadRequest)
+			return
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var claims struct {
+			Sub string `json:"sub"`
+		}
+		if err := json.Unmarshal(payload, &claims); err != nil || claims.Sub != subject {
+			http.Error(w, "unexpected subject", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "ya29.test", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	token, err := GetJWTBearerToken(
+		context.Background(),
+		newTestServiceAccountKey(t, srv.URL),
+		subject,
+		[]string{"https://mail.google.com/"},
+		"",
+		1,
+	)
+	if err != nil {
+		t.Fatalf("failed to retrieve token: %v", err)
+	}
+
+	if want, got := "ya29.test", token.AccessToken; want != got {
+		t.Errorf("want access token %q, got %q", want, got)
+	}
+}
+
+// TestGetJWTBearerTokenRetries asserts that failed attempts are retried
+// without waiting after the final attempt and that retries stop once the
+// context is done. The last error returned by the server is reported.
+func TestGetJWTBearerTokenRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		maxAttempts  int
+		cancel       bool
+		wantRequests int
+		wantErr      error
+	}{
+		"single attempt": {
+			maxAttempts:  1,
+			wantRequests: 1,
+		},
+		"retried": {
+			maxAttempts:  2,
+			wantRequests: 2,
+		},
+		"context done": {
+			maxAttempts:  5,
+			cancel:       true,
+			wantRequests: 1,
+			wantErr:      context.Canceled,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "Not a valid email."}`))
+			}))
+			t.Cleanup(srv.Close)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			if tt.cancel {
+				time.AfterFunc(100*time.Millisecond, cancel)
+			}
+
+			start := time.Now()
+			_, err := GetJWTBearerToken(
+				ctx,
+				newTestServiceAccountKey(t, srv.URL),
+				"intake@example.com",
+				[]string{"https://mail.google.com/"},
+				"",
+				tt.maxAttempts,
+			)
+			elapsed := time.Since(start)
+
+			if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+				t.Fatalf("want error reporting server response, got %v", err)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, got %v", tt.wantErr, err)
+			}
+
+			if want, got := tt.wantRequests, int(requests.Load()); want != got {
+				t.Errorf("want %d token requests, got %d", want, got)
+			}
+
+			if maxElapsed := time.Duration(tt.wantRequests-1)*jwtRetryDelay + 500*time.Millisecond; elapsed > maxElapsed {
+				t.Errorf("want token retrieval to finish within %v, took %v", maxElapsed, elapsed)
+			}
+		})
+	}
+}
+
+// newTestServiceAccountKey returns a service account key with a newly
+// generated private key using the given token URL.
+func newTestServiceAccountKey(t *testing.T, tokenURL string) ServiceAccountKey {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate private key: %v", err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+
+	return ServiceAccountKey{
+		Type:        serviceAccountKeyType,
+		ClientEmail: "monitoring@example.iam.gserviceaccount.com",
+		PrivateKey:  string(keyPEM),
+		TokenURL:    tokenURL,
+	}
+}
@@ -7,7 +7,10 @@
 
 package textutils
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+)
 
 // textileFormattingCharReplacements is a map of replacement characters that
 // are known to interfere with Textile formatted reports generated by the
@@ -70,3 +73,23 @@ func ReplaceUsingSubMap(s string, substMap map[rune]string) string {
 func ReplaceTextileFormatCharacters(s string) string {
 	return ReplaceUsingSubMap(s, textileFormattingCharReplacements)
 }
+
+// ReplaceControlCharacters accepts an original string and a replacement
+// string. For every control character (e.g., line breaks or tabs) found in
+// the original string, the replacement string is used in its place. A
+// modified copy of the original string is returned.
+func ReplaceControlCharacters(s string, r string) string {
+
+	var b strings.Builder
+
+	for _, c := range s {
+		switch {
+		case unicode.IsControl(c):
+			b.WriteString(r)
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	return b.String()
+}
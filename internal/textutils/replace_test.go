@@ -57,3 +57,23 @@ func TestReplaceAstralUnicode(t *testing.T) {
 	}
 
 }
+
+func TestReplaceControlCharacters(t *testing.T) {
+
+	tests := map[string]string{
+		"Jane Doe":           "Jane Doe",
+		"Jane\r\nDoe":        "Jane  Doe",
+		"Jane\tDoe\x00":      "Jane Doe ",
+		"Zoë \u2014 Finance": "Zoë \u2014 Finance",
+		"multi\u0085line":    "multi line",
+	}
+
+	for original, want := range tests {
+		got := ReplaceControlCharacters(original, " ")
+
+		if got != want {
+			t.Error("Expected", want, "Got", got)
+		}
+	}
+
+}
@@ -36,7 +36,8 @@ if [ -x "$(command -v selinuxenabled)" ]; then
 
         for plugin_name in \
             check_imap_mailbox_basic \
-            check_imap_mailbox_oauth2
+            check_imap_mailbox_oauth2 \
+            check_imap_quota
         do
 
             echo -e "\tApplying SELinux contexts on ${plugin_path}/${plugin_name}${plugin_name_suffix}"